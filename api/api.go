@@ -1,6 +1,10 @@
 package api
 
 import (
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/api/middleware"
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gabrielksneiva/go-financial-transactions/services"
 
 	"github.com/gofiber/fiber/v2"
@@ -17,18 +21,29 @@ func NewApp(
 	withdrawService *services.WithdrawService,
 	statementService *services.StatementService,
 	userService *services.UserService,
+	deadLetterService *services.DeadLetterService,
+	depositAddressService *services.DepositAddressService,
+	withdrawalAddressService *services.WithdrawalAddressService,
+	approvalService *services.ApprovalService,
+	transferService *services.TransferService,
+	scheduleService *services.ScheduleService,
+	limitService *services.LimitService,
+	rateLimiter d.RateLimiter,
+	idempotencyRepo d.IdempotencyRepository,
+	idempotencyTTL time.Duration,
 ) *App {
 	app := fiber.New()
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     "http://localhost:4000",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, Idempotency-Key",
+		ExposeHeaders:    "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, Retry-After",
 		AllowCredentials: true,
 	}))
 
-	handlers := NewHandlers(depositService, withdrawService, statementService, userService)
+	handlers := NewHandlers(depositService, withdrawService, statementService, userService, deadLetterService, depositAddressService, withdrawalAddressService, approvalService, transferService, scheduleService, limitService)
 
-	RegisterRoutes(app, handlers)
+	RegisterRoutes(app, handlers, middleware.Idempotency(idempotencyRepo, idempotencyTTL), rateLimiter)
 
 	return &App{
 		Fiber:    app,
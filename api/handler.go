@@ -1,6 +1,11 @@
 package api
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gabrielksneiva/go-financial-transactions/api/middleware"
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gabrielksneiva/go-financial-transactions/services"
@@ -13,21 +18,94 @@ type Handlers struct {
 	WithdrawService  *services.WithdrawService
 	StatementService *services.StatementService
 	UserService      *services.UserService
+	DeadLetters      *services.DeadLetterService
+	DepositAddresses *services.DepositAddressService
+	// WithdrawalAddresses é a lista de destinos de saque de cada usuário
+	WithdrawalAddresses *services.WithdrawalAddressService
+	// Approvals decide os saques que passaram do limite de aprovação
+	Approvals *services.ApprovalService
+	Transfers *services.TransferService
+	// Schedules guarda os saques e transferências agendados
+	Schedules *services.ScheduleService
+	// Limits mostra e ajusta os tetos de valor; sem ele não há tetos
+	Limits *services.LimitService
+}
+
+// ApprovalRequest traz o motivo da decisão, gravado na trilha de auditoria
+type ApprovalRequest struct {
+	Reason string `json:"reason"`
 }
 
+// Amount é recebido como string decimal ("0.29") para não perder precisão
 type TransactionRequest struct {
-	Amount float64 `json:"amount"`
+	Amount   string `json:"amount" form:"amount"`
+	Currency string `json:"currency" form:"currency"`
+	// DestinationID escolhe um destino da lista de endereços; só vale para saques
+	DestinationID uint `json:"destination_id" form:"destination_id"`
+}
+
+// TransferRequest identifica o destinatário pelo ID ou, sem ele, pelo e-mail
+type TransferRequest struct {
+	RecipientID    uint   `json:"recipient_id"`
+	RecipientEmail string `json:"recipient_email"`
+	Amount         string `json:"amount"`
+	Currency       string `json:"currency"`
+	Memo           string `json:"memo"`
 }
 
+// ScheduleRequest descreve um saque (destination_id) ou transferência (recipient_id ou
+// recipient_email) agendado; start_at em RFC3339, vazio para começar agora
+type ScheduleRequest struct {
+	Type           string    `json:"type"`
+	Amount         string    `json:"amount"`
+	Currency       string    `json:"currency"`
+	DestinationID  uint      `json:"destination_id"`
+	RecipientID    uint      `json:"recipient_id"`
+	RecipientEmail string    `json:"recipient_email"`
+	Memo           string    `json:"memo"`
+	Recurrence     string    `json:"recurrence"`
+	Cron           string    `json:"cron"`
+	StartAt        time.Time `json:"start_at"`
+}
+
+// LimitOverrideRequest traz os tetos próprios do usuário em decimal; campos ausentes herdam a
+// regra do papel e do nível de KYC, e "0" tira o teto
+type LimitOverrideRequest struct {
+	Currency          string  `json:"currency"`
+	MaxWithdrawal     *string `json:"max_withdrawal"`
+	DailyWithdrawal   *string `json:"daily_withdrawal"`
+	MonthlyWithdrawal *string `json:"monthly_withdrawal"`
+	DailyDeposit      *string `json:"daily_deposit"`
+}
+
+// WithdrawalAddressRequest exige a senha de novo: incluir ou remover destinos pede reautenticação
+type WithdrawalAddressRequest struct {
+	Label    string `json:"label"`
+	Currency string `json:"currency"`
+	Address  string `json:"address"`
+	Password string `json:"password"`
+}
+
+// Amount é o saldo disponível; Held é o valor reservado por saques ainda não enviados
 type BalanceResponse struct {
-	UserID uint    `json:"user_id"`
-	Amount float64 `json:"amount"`
+	UserID   uint   `json:"user_id"`
+	Amount   string `json:"amount"`
+	Held     string `json:"held"`
+	Currency string `json:"currency"`
+}
+
+type AssetBalance struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
 }
 
+// Balance/Currency trazem o saldo em TRX; Balances traz todas as moedas do usuário
 type StatementResponse struct {
 	UserID       uint                          `json:"user_id"`
 	UserEmail    string                        `json:"user_email"`
-	Balance      float64                       `json:"balance"`
+	Balance      string                        `json:"balance"`
+	Currency     string                        `json:"currency"`
+	Balances     []AssetBalance                `json:"balances"`
 	Transactions []services.TransactionDisplay `json:"transactions"`
 }
 
@@ -36,12 +114,27 @@ func NewHandlers(
 	withdraw *services.WithdrawService,
 	statement *services.StatementService,
 	user *services.UserService,
+	deadLetters *services.DeadLetterService,
+	depositAddresses *services.DepositAddressService,
+	withdrawalAddresses *services.WithdrawalAddressService,
+	approvals *services.ApprovalService,
+	transfers *services.TransferService,
+	schedules *services.ScheduleService,
+	limits *services.LimitService,
 ) *Handlers {
 	return &Handlers{
 		DepositService:   deposit,
 		WithdrawService:  withdraw,
 		StatementService: statement,
 		UserService:      user,
+		DeadLetters:      deadLetters,
+		DepositAddresses: depositAddresses,
+
+		WithdrawalAddresses: withdrawalAddresses,
+		Approvals:           approvals,
+		Transfers:           transfers,
+		Schedules:           schedules,
+		Limits:              limits,
 	}
 }
 
@@ -53,12 +146,25 @@ func (h *Handlers) CreateDepositHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
 	}
 
-	if err := h.DepositService.Deposit(userID, req.Amount); err != nil {
+	amount, err := domain.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tx, err := h.DepositService.Deposit(userID, amount)
+	switch {
+	case errors.Is(err, domain.ErrRateLimited):
+		return rateLimited(c, err)
+	case errors.Is(err, domain.ErrLimitExceeded):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	c.Locals("transaction_id", tx.ID)
 	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
-		"message": "Deposit submitted",
+		"message":        "Deposit submitted",
+		"transaction_id": tx.ID,
 	})
 }
 
@@ -70,26 +176,144 @@ func (h *Handlers) CreateWithdrawHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
 	}
 
-	if err := h.WithdrawService.Withdraw(userID, req.Amount); err != nil {
+	amount, err := domain.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tx, err := h.WithdrawService.WithdrawTo(userID, amount, req.DestinationID)
+	switch {
+	case errors.Is(err, domain.ErrRateLimited):
+		return rateLimited(c, err)
+	case errors.Is(err, domain.ErrWithdrawalAddressNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrWithdrawalAddressCoolingDown), errors.Is(err, domain.ErrLimitExceeded):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	message := "Withdrawal submitted"
+	if tx.Status == domain.StatusAwaitingApproval {
+		message = "Withdrawal awaiting approval"
+	}
+
+	c.Locals("transaction_id", tx.ID)
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message":        message,
+		"transaction_id": tx.ID,
+		"status":         tx.Status,
+		"fee":            tx.FeeAmount().String(),
+	})
+}
+
+func (h *Handlers) CreateTransferHandler(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	var req TransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
+	}
+
+	amount, err := domain.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	tx, err := h.Transfers.Transfer(userID, req.RecipientID, req.RecipientEmail, amount, req.Memo)
+	switch {
+	case errors.Is(err, domain.ErrRateLimited):
+		return rateLimited(c, err)
+	case errors.Is(err, domain.ErrRecipientNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	c.Locals("transaction_id", tx.ID)
 	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
-		"message": "Withdrawal submitted",
+		"message":        "Transfer submitted",
+		"transaction_id": tx.ID,
+		"status":         tx.Status,
+		"fee":            tx.FeeAmount().String(),
 	})
 }
 
+// rateLimited responde 429 quando o serviço recusou pela cota de transações, com Retry-After
+func rateLimited(c *fiber.Ctx, err error) error {
+	var limited *domain.RateLimitError
+	if errors.As(err, &limited) {
+		middleware.SetRateLimitHeaders(c, limited.Result)
+	}
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+}
+
+// GetFeeQuoteHandler cota a tarifa de um saque (type=withdraw) ou transferência (type=transfer_out)
+func (h *Handlers) GetFeeQuoteHandler(c *fiber.Ctx) error {
+	userID := c.Locals("user_id").(uint)
+
+	amount, err := domain.ParseMoney(c.Query("amount"), c.Query("currency"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	var quote domain.FeeQuote
+	switch txType := c.Query("type"); txType {
+	case domain.WithdrawTransaction:
+		quote, err = h.WithdrawService.QuoteFee(userID, amount)
+	case domain.TransferOutTransaction:
+		if h.Transfers == nil {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Transfers not configured"})
+		}
+		quote, err = h.Transfers.QuoteFee(userID, amount)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "type must be withdraw or transfer_out"})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"type":     c.Query("type"),
+		"amount":   quote.Amount.String(),
+		"fee":      quote.Fee.String(),
+		"total":    quote.Total.String(),
+		"currency": quote.Amount.Currency,
+	})
+}
+
+// GetLimitsHandler mostra os tetos do usuário na moeda e quanto ainda pode movimentar
+func (h *Handlers) GetLimitsHandler(c *fiber.Ctx) error {
+	if h.Limits == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Limits not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+	currency := strings.ToUpper(c.Query("currency", domain.DefaultCurrency))
+
+	status, err := h.Limits.Status(userID, currency)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(services.ToLimitDisplay(*status))
+}
+
 func (h *Handlers) GetBalanceHandler(c *fiber.Ctx) error {
 	userID := c.Locals("user_id").(uint)
 
-	amount, err := h.StatementService.GetBalance(userID)
+	currency := strings.ToUpper(c.Query("currency", domain.DefaultCurrency))
+
+	amount, held, err := h.StatementService.GetBalanceWithHeld(userID, currency)
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
 
 	return c.JSON(BalanceResponse{
-		UserID: userID,
-		Amount: amount,
+		UserID:   userID,
+		Amount:   amount.String(),
+		Held:     held.String(),
+		Currency: amount.Currency,
 	})
 }
 
@@ -106,14 +330,262 @@ func (h *Handlers) GetStatementHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
 	}
 
+	balances := make([]AssetBalance, 0, len(statement.Balances))
+	for _, b := range statement.Balances {
+		balances = append(balances, AssetBalance{Amount: b.String(), Currency: b.Currency})
+	}
+
 	return c.JSON(StatementResponse{
 		UserID:       userID,
 		UserEmail:    userRetrieved.Email,
-		Balance:      statement.Balance,
+		Balance:      statement.Balance.String(),
+		Currency:     statement.Balance.Currency,
+		Balances:     balances,
 		Transactions: services.ToTransactionDisplay(statement.Transactions),
 	})
 }
 
+func (h *Handlers) GetDepositAddressHandler(c *fiber.Ctx) error {
+	if h.DepositAddresses == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": services.ErrDepositAddressesDisabled.Error()})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	addr, err := h.DepositAddresses.GetOrAssign(userID)
+	switch {
+	case errors.Is(err, services.ErrDepositAddressesDisabled):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"user_id":  userID,
+		"address":  addr.Address,
+		"currency": "TRX",
+	})
+}
+
+func (h *Handlers) ListWithdrawalAddressesHandler(c *fiber.Ctx) error {
+	if h.WithdrawalAddresses == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Withdrawal addresses not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	addrs, err := h.WithdrawalAddresses.List(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"withdrawal_addresses": addrs,
+	})
+}
+
+func (h *Handlers) AddWithdrawalAddressHandler(c *fiber.Ctx) error {
+	if h.WithdrawalAddresses == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Withdrawal addresses not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	var req WithdrawalAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
+	}
+
+	addr, err := h.WithdrawalAddresses.Add(userID, req.Password, req.Label, req.Currency, req.Address)
+	if err != nil {
+		return c.Status(withdrawalAddressErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(addr)
+}
+
+func (h *Handlers) RemoveWithdrawalAddressHandler(c *fiber.Ctx) error {
+	if h.WithdrawalAddresses == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Withdrawal addresses not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid withdrawal address ID"})
+	}
+
+	var req WithdrawalAddressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
+	}
+
+	if err := h.WithdrawalAddresses.Remove(userID, req.Password, uint(id)); err != nil {
+		return c.Status(withdrawalAddressErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func withdrawalAddressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrReauthenticationFailed):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, domain.ErrWithdrawalAddressNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrWithdrawalAddressExists):
+		return fiber.StatusConflict
+	case errors.Is(err, domain.ErrInvalidAddress), errors.Is(err, domain.ErrAssetNotSupported),
+		errors.Is(err, domain.ErrChainNotSupported), errors.Is(err, services.ErrInvalidLabel):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
+func (h *Handlers) ListSchedulesHandler(c *fiber.Ctx) error {
+	if h.Schedules == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Schedules not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	schedules, err := h.Schedules.List(userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	result := make([]services.ScheduleDisplay, 0, len(schedules))
+	for _, s := range schedules {
+		result = append(result, services.ToScheduleDisplay(s))
+	}
+	return c.JSON(fiber.Map{
+		"schedules": result,
+	})
+}
+
+func (h *Handlers) GetScheduleHandler(c *fiber.Ctx) error {
+	if h.Schedules == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Schedules not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid schedule ID"})
+	}
+
+	schedule, err := h.Schedules.Get(userID, uint(id))
+	if err != nil {
+		return c.Status(scheduleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(services.ToScheduleDisplay(*schedule))
+}
+
+func (h *Handlers) CreateScheduleHandler(c *fiber.Ctx) error {
+	if h.Schedules == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Schedules not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	in, err := parseScheduleRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	schedule, err := h.Schedules.Create(userID, in)
+	if err != nil {
+		return c.Status(scheduleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(services.ToScheduleDisplay(*schedule))
+}
+
+func (h *Handlers) UpdateScheduleHandler(c *fiber.Ctx) error {
+	if h.Schedules == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Schedules not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid schedule ID"})
+	}
+
+	in, err := parseScheduleRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	schedule, err := h.Schedules.Update(userID, uint(id), in)
+	if err != nil {
+		return c.Status(scheduleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(services.ToScheduleDisplay(*schedule))
+}
+
+func (h *Handlers) DeleteScheduleHandler(c *fiber.Ctx) error {
+	if h.Schedules == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Schedules not configured"})
+	}
+
+	userID := c.Locals("user_id").(uint)
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid schedule ID"})
+	}
+
+	if err := h.Schedules.Delete(userID, uint(id)); err != nil {
+		return c.Status(scheduleErrorStatus(err)).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func parseScheduleRequest(c *fiber.Ctx) (services.ScheduleInput, error) {
+	var req ScheduleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return services.ScheduleInput{}, errors.New("Invalid JSON")
+	}
+
+	amount, err := domain.ParseMoney(req.Amount, req.Currency)
+	if err != nil {
+		return services.ScheduleInput{}, err
+	}
+
+	return services.ScheduleInput{
+		Type:           req.Type,
+		Amount:         amount,
+		DestinationID:  req.DestinationID,
+		RecipientID:    req.RecipientID,
+		RecipientEmail: req.RecipientEmail,
+		Memo:           req.Memo,
+		Recurrence:     req.Recurrence,
+		Cron:           req.Cron,
+		StartAt:        req.StartAt,
+	}, nil
+}
+
+func scheduleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrScheduleNotFound), errors.Is(err, domain.ErrWithdrawalAddressNotFound),
+		errors.Is(err, domain.ErrRecipientNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, domain.ErrInvalidSchedule), errors.Is(err, domain.ErrSelfTransfer),
+		errors.Is(err, domain.ErrMemoTooLong), errors.Is(err, domain.ErrAssetNotSupported):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
+
 func (h *Handlers) RegisterHandler(c *fiber.Ctx) error {
 	// Agora só name, email e password são obrigatórios
 	var req struct {
@@ -183,3 +655,185 @@ func (h *Handlers) LoginHandler(c *fiber.Ctx) error {
 		"message": "Login bem-sucedido!",
 	})
 }
+
+func (h *Handlers) ListDeadLettersHandler(c *fiber.Ctx) error {
+	if h.DeadLetters == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Dead-letter queue not configured"})
+	}
+
+	letters, err := h.DeadLetters.List(c.Context(), c.QueryInt("limit", services.DefaultDeadLetterLimit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"dead_letters": letters,
+	})
+}
+
+func (h *Handlers) RedriveDeadLetterHandler(c *fiber.Ctx) error {
+	if h.DeadLetters == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Dead-letter queue not configured"})
+	}
+
+	partition, err := strconv.Atoi(c.Params("partition"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid partition"})
+	}
+	offset, err := strconv.ParseInt(c.Params("offset"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid offset"})
+	}
+
+	tx, err := h.DeadLetters.Redrive(c.Context(), partition, offset)
+	switch {
+	case errors.Is(err, domain.ErrDeadLetterNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrDeadLetterNotRedrivable):
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message":        "Dead letter redriven",
+		"transaction_id": tx.ID,
+	})
+}
+
+func (h *Handlers) ListPendingWithdrawalsHandler(c *fiber.Ctx) error {
+	if h.Approvals == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Withdrawal approvals not configured"})
+	}
+
+	txs, err := h.Approvals.ListPending(c.QueryInt("limit", services.DefaultApprovalListLimit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"withdrawals": services.ToTransactionDisplay(txs),
+	})
+}
+
+func (h *Handlers) ApproveWithdrawalHandler(c *fiber.Ctx) error {
+	return h.decideWithdrawal(c, h.Approvals.Approve)
+}
+
+func (h *Handlers) RejectWithdrawalHandler(c *fiber.Ctx) error {
+	return h.decideWithdrawal(c, h.Approvals.Reject)
+}
+
+func (h *Handlers) ListApprovalDecisionsHandler(c *fiber.Ctx) error {
+	if h.Approvals == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Withdrawal approvals not configured"})
+	}
+
+	decisions, err := h.Approvals.Decisions(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"decisions": decisions,
+	})
+}
+
+func (h *Handlers) decideWithdrawal(c *fiber.Ctx, decide func(txID string, approverID uint, reason string) (*domain.Transaction, error)) error {
+	if h.Approvals == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Withdrawal approvals not configured"})
+	}
+
+	approverID := c.Locals("user_id").(uint)
+
+	var req ApprovalRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
+		}
+	}
+
+	tx, err := decide(c.Params("id"), approverID, req.Reason)
+	switch {
+	case errors.Is(err, domain.ErrTransactionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrSelfApproval):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrStatusConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"transaction_id": tx.ID,
+		"status":         tx.Status,
+	})
+}
+
+func (h *Handlers) SetLimitOverrideHandler(c *fiber.Ctx) error {
+	if h.Limits == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Limits not configured"})
+	}
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user"})
+	}
+
+	var req LimitOverrideRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid JSON"})
+	}
+
+	override := domain.LimitOverride{UserID: uint(userID), Currency: strings.ToUpper(req.Currency)}
+	if override.Currency == "" {
+		override.Currency = domain.DefaultCurrency
+	}
+	fields := []struct {
+		value *string
+		dst   **int64
+	}{
+		{req.MaxWithdrawal, &override.MaxWithdrawal},
+		{req.DailyWithdrawal, &override.DailyWithdrawal},
+		{req.MonthlyWithdrawal, &override.MonthlyWithdrawal},
+		{req.DailyDeposit, &override.DailyDeposit},
+	}
+	for _, f := range fields {
+		if f.value == nil {
+			continue
+		}
+		amount, err := domain.ParseMoney(*f.value, override.Currency)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		*f.dst = &amount.Units
+	}
+
+	if err := h.Limits.SetOverride(override); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	status, err := h.Limits.Status(override.UserID, override.Currency)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.JSON(services.ToLimitDisplay(*status))
+}
+
+func (h *Handlers) DeleteLimitOverrideHandler(c *fiber.Ctx) error {
+	if h.Limits == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Limits not configured"})
+	}
+
+	userID, err := strconv.ParseUint(c.Params("id"), 10, 64)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid user"})
+	}
+
+	currency := strings.ToUpper(c.Query("currency", domain.DefaultCurrency))
+	if err := h.Limits.DeleteOverride(uint(userID), currency); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
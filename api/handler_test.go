@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -10,6 +11,7 @@ import (
 	"time"
 
 	"github.com/gabrielksneiva/go-financial-transactions/api"
+	"github.com/gabrielksneiva/go-financial-transactions/client"
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gabrielksneiva/go-financial-transactions/mocks"
 	"github.com/gabrielksneiva/go-financial-transactions/services"
@@ -18,23 +20,24 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
 )
 
-func setupTestApp() (*fiber.App, *mocks.Producer, *mocks.TransactionRepository, *mocks.BalanceRepository, *mocks.UserRepository, *mocks.RateLimiter) {
+func setupTestApp() (*fiber.App, *mocks.OutboxRepository, *mocks.TransactionRepository, *mocks.BalanceRepository, *mocks.UserRepository, *mocks.RateLimiter) {
 	txRepo := new(mocks.TransactionRepository)
 	balanceRepo := new(mocks.BalanceRepository)
 	userRepo := new(mocks.UserRepository)
 	rateLimiter := new(mocks.RateLimiter)
-	producer := new(mocks.Producer)
+	outbox := new(mocks.OutboxRepository)
 
-	depositService := services.NewDepositService(txRepo, balanceRepo, producer, rateLimiter)
-	withdrawService := services.NewWithdrawService(txRepo, balanceRepo, producer, rateLimiter)
+	depositService := services.NewDepositService(txRepo, balanceRepo, outbox, rateLimiter)
+	withdrawService := services.NewWithdrawService(txRepo, balanceRepo, outbox, rateLimiter)
 	statementService := services.NewStatementService(txRepo, balanceRepo)
 	userService := services.NewUserService(userRepo)
 
-	appStruct := api.NewApp(depositService, withdrawService, statementService, userService)
+	appStruct := api.NewApp(depositService, withdrawService, statementService, userService, nil, nil, nil, nil, nil, nil, nil, nil, nil, time.Hour)
 
-	return appStruct.Fiber, producer, txRepo, balanceRepo, userRepo, rateLimiter
+	return appStruct.Fiber, outbox, txRepo, balanceRepo, userRepo, rateLimiter
 }
 
 func generateTestJWT(userID uint) string {
@@ -47,6 +50,26 @@ func generateTestJWT(userID uint) string {
 	return tokenString
 }
 
+func setupAdminTestApp() (*fiber.App, *mocks.DeadLetterQueue, *mocks.Producer) {
+	queue := new(mocks.DeadLetterQueue)
+	producer := new(mocks.Producer)
+	deadLetters := services.NewDeadLetterService(queue, producer)
+
+	appStruct := api.NewApp(nil, nil, nil, nil, deadLetters, nil, nil, nil, nil, nil, nil, nil, nil, time.Hour)
+	return appStruct.Fiber, queue, producer
+}
+
+func generateAdminJWT(userID uint) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": userID,
+		"role":    "admin",
+		"exp":     time.Now().Add(time.Hour * 1).Unix(),
+	})
+
+	tokenString, _ := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return tokenString
+}
+
 func TestStatementHandler_Success(t *testing.T) {
 	app, _, txRepoMock, balanceRepoMock, userRepoMock, _ := setupTestApp()
 
@@ -54,10 +77,12 @@ func TestStatementHandler_Success(t *testing.T) {
 
 	userRepoMock.On("GetByID", userID).Return(&domain.User{ID: userID}, nil)
 	txRepoMock.On("GetByUser", userID).Return([]domain.Transaction{
-		{ID: "tx1", UserID: userID, Amount: 50.0, Type: "deposit"},
+		{ID: "tx1", UserID: userID, Amount: domain.NewMoney(50_000000, "TRX"), Type: "deposit"},
 	}, nil)
-	balanceRepoMock.On("GetBalance", userID).Return(&domain.Balance{
-		UserID: userID, Amount: 50.0,
+	balanceRepoMock.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 50_000000}, nil)
+	balanceRepoMock.On("ListBalances", userID).Return([]domain.Balance{
+		{UserID: userID, Currency: "TRX", Units: 50_000000},
+		{UserID: userID, Currency: "USDT", Units: 12_500000},
 	}, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/statement/789", nil)
@@ -67,6 +92,10 @@ func TestStatementHandler_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 
+	var body api.StatementResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []api.AssetBalance{{Amount: "50.000000", Currency: "TRX"}, {Amount: "12.500000", Currency: "USDT"}}, body.Balances)
+
 	userRepoMock.AssertExpectations(t)
 	txRepoMock.AssertExpectations(t)
 	balanceRepoMock.AssertExpectations(t)
@@ -77,13 +106,11 @@ func TestWithdrawHandler_InsufficientFunds(t *testing.T) {
 
 	userID := uint(456)
 
-	balanceRepoMock.On("GetBalance", userID).Return(&domain.Balance{
-		UserID: userID, Amount: 50.0,
-	}, nil)
+	balanceRepoMock.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 50_000000}, nil)
 
 	rateLimiterMock.On("CheckTransactionRateLimit", mock.AnythingOfType("uint")).Return(nil)
 
-	body := []byte(`{"amount":100.0}`)
+	body := []byte(`{"amount":"100.0"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/withdraw", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+generateTestJWT(userID))
@@ -98,9 +125,7 @@ func TestBalanceHandler_Success(t *testing.T) {
 
 	userID := uint(123)
 
-	balanceRepoMock.On("GetBalance", userID).Return(&domain.Balance{
-		UserID: userID, Amount: 150.0,
-	}, nil)
+	balanceRepoMock.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 150_000000}, nil)
 
 	rateLimiterMock.On("CheckTransactionRateLimit", mock.AnythingOfType("uint")).Return(nil)
 
@@ -112,6 +137,25 @@ func TestBalanceHandler_Success(t *testing.T) {
 	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 }
 
+func TestBalanceHandler_Currency(t *testing.T) {
+	app, _, _, balanceRepoMock, _, _ := setupTestApp()
+
+	userID := uint(123)
+
+	balanceRepoMock.On("GetBalance", userID, "USDT").Return(&domain.Balance{UserID: userID, Currency: "USDT", Units: 7_250000, Held: 2_000000}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/balance/123?currency=usdt", nil)
+	req.Header.Set("Authorization", "Bearer "+generateTestJWT(userID))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body api.BalanceResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, api.BalanceResponse{UserID: userID, Amount: "7.250000", Held: "2.000000", Currency: "USDT"}, body)
+}
+
 func TestDepositHandler_InvalidJSON(t *testing.T) {
 	app, _, _, _, _, _ := setupTestApp()
 
@@ -128,14 +172,14 @@ func TestDepositHandler_InvalidJSON(t *testing.T) {
 }
 
 func TestDepositHandler_InternalError(t *testing.T) {
-	app, producerMock, _, _, _, rateLimiterMock := setupTestApp()
+	app, outboxMock, _, _, _, rateLimiterMock := setupTestApp()
 
 	userID := uint(222)
 
-	producerMock.On("SendTransaction", mock.Anything).Return(errors.New("kafka error"))
+	outboxMock.On("SaveTransactionWithOutbox", mock.Anything).Return(errors.New("db error"))
 	rateLimiterMock.On("CheckTransactionRateLimit", mock.AnythingOfType("uint")).Return(nil)
 
-	body := []byte(`{"amount":50.0}`)
+	body := []byte(`{"amount":"50.0"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/deposit", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+generateTestJWT(userID))
@@ -145,6 +189,23 @@ func TestDepositHandler_InternalError(t *testing.T) {
 	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
 }
 
+func TestDepositHandler_RateLimited(t *testing.T) {
+	app, _, _, _, _, rateLimiterMock := setupTestApp()
+
+	limited := &domain.RateLimitError{Result: domain.RateLimitResult{Limit: 10, ResetAfter: time.Minute, RetryAfter: 5500 * time.Millisecond}}
+	rateLimiterMock.On("CheckTransactionRateLimit", uint(222)).Return(limited)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/deposit", bytes.NewBuffer([]byte(`{"amount":"50.0"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+generateTestJWT(222))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "6", resp.Header.Get("Retry-After"))
+	assert.Equal(t, "0", resp.Header.Get("X-RateLimit-Remaining"))
+}
+
 func TestWithdrawHandler_InvalidJSON(t *testing.T) {
 	app, _, _, _, _, _ := setupTestApp()
 
@@ -165,10 +226,10 @@ func TestWithdrawHandler_DBError(t *testing.T) {
 
 	userID := uint(999)
 
-	balanceRepo.On("GetBalance", userID).Return(nil, errors.New("db error"))
+	balanceRepo.On("GetBalance", userID, "TRX").Return(nil, errors.New("db error"))
 	rateLimiterMock.On("CheckTransactionRateLimit", mock.AnythingOfType("uint")).Return(nil)
 
-	body := []byte(`{"amount":20.0}`)
+	body := []byte(`{"amount":"20.0"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/withdraw", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+generateTestJWT(userID))
@@ -183,7 +244,7 @@ func TestBalanceHandler_NotFound(t *testing.T) {
 
 	userID := uint(404)
 
-	balanceRepo.On("GetBalance", userID).Return(nil, errors.New("not found"))
+	balanceRepo.On("GetBalance", userID, "TRX").Return(nil, errors.New("not found"))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/balance/404", nil)
 	req.Header.Set("Authorization", "Bearer "+generateTestJWT(userID))
@@ -200,7 +261,7 @@ func TestStatementHandler_NotFound(t *testing.T) {
 
 	userRepoMock.On("GetByID", userID).Return(nil, errors.New("user not found"))
 	txRepoMock.On("GetByUser", userID).Return(nil, errors.New("transactions not found"))
-	balanceRepo.On("GetBalance", mock.AnythingOfType("uint")).Return(nil, errors.New("balance not found"))
+	balanceRepo.On("GetBalance", mock.AnythingOfType("uint"), "TRX").Return(nil, errors.New("balance not found"))
 	rateLimiterMock.On("CheckTransactionRateLimit", mock.AnythingOfType("uint")).Return(nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/statement/789", nil)
@@ -214,7 +275,7 @@ func TestStatementHandler_NotFound(t *testing.T) {
 func TestDepositHandler_Unauthorized(t *testing.T) {
 	app, _, _, _, _, _ := setupTestApp()
 
-	body := []byte(`{"amount":50.0}`)
+	body := []byte(`{"amount":"50.0"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/deposit", bytes.NewBuffer(body))
 	req.Header.Set("Content-Type", "application/json")
 
@@ -263,3 +324,317 @@ func TestCreateUser_InvalidJSON(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 }
+
+func TestDeadLetters_RequireAdmin(t *testing.T) {
+	app, queue, _ := setupAdminTestApp()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/dlq", nil)
+	req.Header.Set("Authorization", "Bearer "+generateTestJWT(1))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	queue.AssertNotCalled(t, "ListDeadLetters", mock.Anything, mock.Anything)
+}
+
+func TestDeadLetters_List(t *testing.T) {
+	app, queue, _ := setupAdminTestApp()
+
+	queue.On("ListDeadLetters", mock.Anything, 10).Return([]domain.DeadLetter{
+		{Partition: 0, Offset: 3, Error: "db down", Attempts: 5, OriginalOffset: 120},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/dlq?limit=10", nil)
+	req.Header.Set("Authorization", "Bearer "+generateAdminJWT(1))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	queue.AssertExpectations(t)
+}
+
+func TestDeadLetters_Redrive(t *testing.T) {
+	app, queue, producer := setupAdminTestApp()
+
+	queue.On("GetDeadLetter", mock.Anything, 0, int64(3)).Return(&domain.DeadLetter{
+		Value: `{"id":"tx-1","UserID":7,"amount":{"units":1000000,"currency":"TRX"},"type":"deposit"}`,
+	}, nil)
+	queue.On("GetDeadLetter", mock.Anything, 0, int64(4)).Return(nil, domain.ErrDeadLetterNotFound)
+	producer.On("SendTransaction", mock.AnythingOfType("domain.Transaction")).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/dlq/0/3/redrive", nil)
+	req.Header.Set("Authorization", "Bearer "+generateAdminJWT(1))
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/admin/dlq/0/4/redrive", nil)
+	req.Header.Set("Authorization", "Bearer "+generateAdminJWT(1))
+	resp, err = app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	producer.AssertNumberOfCalls(t, "SendTransaction", 1)
+}
+
+func TestDepositAddressHandler_NotConfigured(t *testing.T) {
+	app, _, _, _, _, _ := setupTestApp()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/deposit-address", nil)
+	req.Header.Set("Authorization", "Bearer "+generateTestJWT(5))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+}
+
+func TestDepositAddressHandler_Success(t *testing.T) {
+	repo := new(mocks.DepositRepository)
+	repo.On("GetDepositAddress", uint(5)).Return(&domain.DepositAddress{UserID: 5, Address: "TAddr5", Index: 5}, nil)
+	app := api.NewApp(nil, nil, nil, nil, nil, services.NewDepositAddressService(repo, nil), nil, nil, nil, nil, nil, nil, nil, time.Hour).Fiber
+
+	req := httptest.NewRequest(http.MethodGet, "/api/deposit-address", nil)
+	req.Header.Set("Authorization", "Bearer "+generateTestJWT(5))
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "TAddr5", body["address"])
+}
+
+func setupWithdrawalAddressApp(t *testing.T) (*fiber.App, *mocks.WithdrawalAddressRepository, *mocks.BalanceRepository, *mocks.RateLimiter) {
+	destinations := new(mocks.WithdrawalAddressRepository)
+	userRepo := new(mocks.UserRepository)
+	balanceRepo := new(mocks.BalanceRepository)
+	rateLimiter := new(mocks.RateLimiter)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	userRepo.On("GetByID", uint(9)).Return(&domain.User{ID: 9, Password: string(hashed)}, nil)
+
+	withdrawService := services.NewWithdrawService(nil, balanceRepo, nil, rateLimiter)
+	withdrawService.Destinations = destinations
+	validators := map[string]domain.AddressValidator{domain.ChainTron: client.TronAddressValidator{}}
+	addresses := services.NewWithdrawalAddressService(destinations, userRepo, validators, time.Hour)
+
+	app := api.NewApp(nil, withdrawService, nil, nil, nil, nil, addresses, nil, nil, nil, nil, nil, nil, time.Hour).Fiber
+	return app, destinations, balanceRepo, rateLimiter
+}
+
+func TestWithdrawalAddressHandlers(t *testing.T) {
+	app, destinations, balanceRepo, rateLimiter := setupWithdrawalAddressApp(t)
+	destinations.On("SaveWithdrawalAddress", mock.AnythingOfType("*domain.WithdrawalAddress")).Return(nil)
+	destinations.On("GetWithdrawalAddress", uint(9), uint(1)).Return(&domain.WithdrawalAddress{
+		ID: 1, UserID: 9, Chain: domain.ChainTron, Address: "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", ActiveAt: time.Now().Add(time.Hour),
+	}, nil)
+	balanceRepo.On("GetBalance", uint(9), "TRX").Return(&domain.Balance{UserID: 9, Currency: "TRX", Units: 100_000000}, nil)
+	rateLimiter.On("CheckTransactionRateLimit", uint(9)).Return(nil)
+
+	send := func(method, path, body string) *http.Response {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+generateTestJWT(9))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := send(http.MethodPost, "/api/withdrawal-addresses", `{"label":"exchange","currency":"TRX","address":"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH","password":"wrong"}`)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = send(http.MethodPost, "/api/withdrawal-addresses", `{"label":"exchange","currency":"TRX","address":"not-an-address","password":"secret"}`)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = send(http.MethodPost, "/api/withdrawal-addresses", `{"label":"exchange","currency":"TRX","address":"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH","password":"secret"}`)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+
+	// ainda em carência
+	resp = send(http.MethodPost, "/api/withdraw", `{"amount":"1","currency":"TRX","destination_id":1}`)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp = send(http.MethodDelete, "/api/withdrawal-addresses/1", `{}`)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	destinations.AssertNotCalled(t, "DeleteWithdrawalAddress", uint(9), uint(1))
+}
+
+func TestApprovalHandlers(t *testing.T) {
+	txRepo := new(mocks.TransactionRepository)
+	approvals := new(mocks.ApprovalRepository)
+	app := api.NewApp(nil, nil, nil, nil, nil, nil, nil, services.NewApprovalService(txRepo, approvals), nil, nil, nil, nil, nil, time.Hour).Fiber
+
+	txRepo.On("ListTransactionsByStatus", domain.StatusAwaitingApproval, 100).
+		Return([]domain.Transaction{{ID: "tx-big", UserID: 1, Amount: domain.NewMoney(90_000_000000, "TRX"), Type: "withdraw", Status: domain.StatusAwaitingApproval}}, nil)
+	approvals.On("ApproveWithdrawal", "tx-big", uint(1), "").Return(nil, domain.ErrSelfApproval)
+	approvals.On("RejectWithdrawal", "tx-big", uint(2), "limit").
+		Return(&domain.Transaction{ID: "tx-big", Status: domain.StatusRejected}, nil)
+
+	do := func(method, path, token, body string) *http.Response {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := do(http.MethodGet, "/api/admin/withdrawals/pending", generateTestJWT(2), "")
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp = do(http.MethodGet, "/api/admin/withdrawals/pending", generateAdminJWT(2), "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var list map[string][]map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	assert.Len(t, list["withdrawals"], 1)
+
+	resp = do(http.MethodPost, "/api/admin/withdrawals/tx-big/approve", generateAdminJWT(1), "")
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp = do(http.MethodPost, "/api/admin/withdrawals/tx-big/reject", generateAdminJWT(2), `{"reason":"limit"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	approvals.AssertExpectations(t)
+}
+
+func TestTransferHandler(t *testing.T) {
+	users := new(mocks.UserRepository)
+	balanceRepo := new(mocks.BalanceRepository)
+	outbox := new(mocks.OutboxRepository)
+	rateLimiter := new(mocks.RateLimiter)
+	transfers := services.NewTransferService(users, balanceRepo, outbox, rateLimiter)
+	app := api.NewApp(nil, nil, nil, nil, nil, nil, nil, nil, transfers, nil, nil, nil, nil, time.Hour).Fiber
+
+	users.On("GetByEmail", "bob@example.com").Return(&domain.User{ID: 6}, nil)
+	users.On("GetByEmail", "ghost@example.com").Return(nil, errors.New("record not found"))
+	rateLimiter.On("CheckTransactionRateLimit", uint(5)).Return(nil)
+	balanceRepo.On("GetBalance", uint(5), "USDT").Return(&domain.Balance{UserID: 5, Currency: "USDT", Units: 10_000000}, nil)
+	outbox.On("SaveTransactionWithOutbox", mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.CounterpartyID == 6 && tx.Amount == domain.NewMoney(2_500000, "USDT")
+	})).Return(nil)
+
+	post := func(body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/api/transfers", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+generateTestJWT(5))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := post(`{"recipient_email":"ghost@example.com","amount":"2.5","currency":"USDT"}`)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp = post(`{"recipient_email":"bob@example.com","amount":"2.5","currency":"USDT","memo":"dinner"}`)
+	assert.Equal(t, fiber.StatusAccepted, resp.StatusCode)
+	outbox.AssertExpectations(t)
+}
+
+func TestScheduleHandlers(t *testing.T) {
+	repo := new(mocks.ScheduleRepository)
+	users := new(mocks.UserRepository)
+	transfers := services.NewTransferService(users, new(mocks.BalanceRepository), new(mocks.OutboxRepository), new(mocks.RateLimiter))
+	app := api.NewApp(nil, nil, nil, nil, nil, nil, nil, nil, nil, services.NewScheduleService(repo, nil, transfers), nil, nil, nil, time.Hour).Fiber
+
+	users.On("GetByEmail", "bob@example.com").Return(&domain.User{ID: 6}, nil)
+	repo.On("CreateSchedule", mock.MatchedBy(func(s *domain.Schedule) bool {
+		return s.UserID == 5 && s.RecipientID == 6 && s.Cron == "0 9 * * 1"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*domain.Schedule).ID = 1
+	})
+	repo.On("GetSchedule", uint(5), uint(2)).Return(nil, domain.ErrScheduleNotFound)
+
+	do := func(method, path, body string) *http.Response {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+generateTestJWT(5))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/api/schedules", `{"type":"transfer","recipient_email":"bob@example.com","amount":"2.5","currency":"USDT","recurrence":"cron","cron":"0 9 * * 1"}`)
+	assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	var created map[string]interface{}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Equal(t, "2.500000", created["amount"])
+	assert.NotNil(t, created["next_run_at"])
+
+	resp = do(http.MethodPost, "/api/schedules", `{"type":"transfer","recipient_email":"bob@example.com","amount":"2.5","currency":"USDT","recurrence":"hourly"}`)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = do(http.MethodGet, "/api/schedules/2", "")
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	repo.AssertExpectations(t)
+}
+
+func TestFeeQuoteHandler(t *testing.T) {
+	fees := new(mocks.FeeSchedule)
+	withdrawService := services.NewWithdrawService(nil, nil, nil, nil)
+	withdrawService.Fees = fees
+	app := api.NewApp(nil, withdrawService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, time.Hour).Fiber
+
+	fees.On("Quote", uint(5), domain.WithdrawTransaction, domain.NewMoney(10_000000, "TRX")).Return(domain.NewMoney(1_100000, "TRX"), nil)
+
+	get := func(query string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/api/fees/quote?"+query, nil)
+		req.Header.Set("Authorization", "Bearer "+generateTestJWT(5))
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := get("type=withdraw&amount=10&currency=TRX")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var quote map[string]string
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&quote))
+	assert.Equal(t, "1.100000", quote["fee"])
+	assert.Equal(t, "11.100000", quote["total"])
+
+	resp = get("type=deposit&amount=10&currency=TRX")
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func TestLimitHandlers(t *testing.T) {
+	policy := new(mocks.LimitPolicy)
+	limitRepo := new(mocks.LimitRepository)
+	app := api.NewApp(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, services.NewLimitService(policy, limitRepo), nil, nil, time.Hour).Fiber
+
+	limits := domain.Limits{Currency: "TRX", DailyWithdrawal: 10_000000}
+	policy.On("LimitsFor", uint(5), "TRX").Return(limits, nil)
+	limitRepo.On("GetLimitUsage", uint(5), "TRX", mock.AnythingOfType("time.Time")).Return(domain.LimitUsage{DailyWithdrawal: 4_000000}, nil)
+	limitRepo.On("SaveLimitOverride", mock.MatchedBy(func(o domain.LimitOverride) bool {
+		return o.UserID == 5 && o.Currency == "TRX" && *o.DailyWithdrawal == 10_000000 && o.DailyDeposit == nil
+	})).Return(nil)
+	limitRepo.On("DeleteLimitOverride", uint(5), "TRX").Return(nil)
+
+	do := func(method, path, token, body string) *http.Response {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		return resp
+	}
+
+	resp := do(http.MethodGet, "/api/limits", generateTestJWT(5), "")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var display services.LimitDisplay
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&display))
+	assert.Equal(t, "6.000000", *display.DailyWithdrawal.Remaining)
+	assert.Nil(t, display.MaxWithdrawal)
+
+	// só admins ajustam os tetos de um usuário
+	resp = do(http.MethodPut, "/api/admin/users/5/limits", generateTestJWT(5), `{"currency":"TRX","daily_withdrawal":"100"}`)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	resp = do(http.MethodPut, "/api/admin/users/5/limits", generateAdminJWT(1), `{"currency":"TRX","daily_withdrawal":"10"}`)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp = do(http.MethodPut, "/api/admin/users/5/limits", generateAdminJWT(1), `{"currency":"TRX","daily_withdrawal":"abc"}`)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = do(http.MethodDelete, "/api/admin/users/5/limits?currency=trx", generateAdminJWT(1), "")
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	limitRepo.AssertExpectations(t)
+}
@@ -0,0 +1,17 @@
+package middleware
+
+import "github.com/gofiber/fiber/v2"
+
+const RoleAdmin = "admin"
+
+// AdminOnly deve ser usado depois do JWTProtected, que grava a role do token
+func AdminOnly() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if role, _ := c.Locals("role").(string); role != RoleAdmin {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Admin role required",
+			})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const IdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyLease é quanto tempo uma requisição em andamento segura a chave. Se o processo
+// cair antes de gravar a resposta, uma nova tentativa com a mesma chave é aceita depois disso
+// em vez de receber 409 até o fim do TTL; por isso ele fica bem acima do tempo de resposta.
+const IdempotencyLease = time.Minute
+
+// Idempotency reaproveita a resposta da primeira requisição feita com o mesmo
+// Idempotency-Key. A mesma chave com outro corpo retorna 409.
+func Idempotency(store d.IdempotencyRepository, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyHeader)
+		if key == "" || store == nil {
+			return c.Next()
+		}
+
+		userID, ok := c.Locals("user_id").(uint)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid or missing user_id in token",
+			})
+		}
+
+		hash := requestHash(c)
+		now := time.Now()
+
+		lease := d.IdempotencyRecord{UserID: userID, Key: key, LeaseToken: uuid.New().String()}
+		existing, created, err := store.ReserveIdempotencyKey(d.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			RequestHash: hash,
+			LeaseToken:  lease.LeaseToken,
+			LockedUntil: now.Add(IdempotencyLease),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		})
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		if !created {
+			if existing.RequestHash != hash {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "Idempotency-Key already used with a different request",
+				})
+			}
+			if !existing.Completed {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error": "A request with this Idempotency-Key is still being processed",
+				})
+			}
+
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(existing.StatusCode).Send(existing.ResponseBody)
+		}
+
+		if err := c.Next(); err != nil {
+			_ = store.ReleaseIdempotencyKey(lease)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+
+		// Erros internos e 429 não são gravados para que o cliente possa tentar de novo com a mesma chave
+		if status >= fiber.StatusInternalServerError || status == fiber.StatusTooManyRequests {
+			if err := store.ReleaseIdempotencyKey(lease); err != nil {
+				log.Printf("⚠️ Erro ao liberar Idempotency-Key %s: %v", key, err)
+			}
+			return nil
+		}
+
+		txID, _ := c.Locals("transaction_id").(string)
+		if err := store.CompleteIdempotencyKey(d.IdempotencyRecord{
+			UserID:        userID,
+			Key:           key,
+			LeaseToken:    lease.LeaseToken,
+			StatusCode:    status,
+			ResponseBody:  append([]byte(nil), c.Response().Body()...),
+			TransactionID: txID,
+		}); err != nil {
+			log.Printf("⚠️ Erro ao gravar resposta da Idempotency-Key %s: %v", key, err)
+		}
+
+		return nil
+	}
+}
+
+func requestHash(c *fiber.Ctx) string {
+	h := sha256.New()
+	h.Write([]byte(c.Method()))
+	h.Write([]byte(c.Path()))
+	h.Write(c.Body())
+	return hex.EncodeToString(h.Sum(nil))
+}
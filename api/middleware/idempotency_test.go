@@ -0,0 +1,171 @@
+package middleware_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/api/middleware"
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupIdempotencyApp(store domain.IdempotencyRepository, status int) (*fiber.App, *int) {
+	calls := 0
+	app := fiber.New()
+	app.Post("/api/deposit",
+		func(c *fiber.Ctx) error {
+			c.Locals("user_id", uint(1))
+			return c.Next()
+		},
+		middleware.Idempotency(store, time.Hour),
+		func(c *fiber.Ctx) error {
+			calls++
+			c.Locals("transaction_id", "tx-1")
+			return c.Status(status).JSON(fiber.Map{"transaction_id": "tx-1"})
+		},
+	)
+	return app, &calls
+}
+
+func doRequest(t *testing.T, app *fiber.App, key, body string) (int, string, string) {
+	req := httptest.NewRequest("POST", "/api/deposit", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(middleware.IdempotencyHeader, key)
+	}
+
+	resp, err := app.Test(req)
+	assert.NoError(t, err)
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody), resp.Header.Get("Idempotent-Replayed")
+}
+
+func TestIdempotency_NoHeader(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusAccepted)
+
+	status, _, _ := doRequest(t, app, "", `{"amount":"1"}`)
+	assert.Equal(t, fiber.StatusAccepted, status)
+	assert.Equal(t, 1, *calls)
+	store.AssertNotCalled(t, "ReserveIdempotencyKey", mock.Anything)
+}
+
+func TestIdempotency_FirstRequestIsStored(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusAccepted)
+
+	store.On("ReserveIdempotencyKey", mock.MatchedBy(func(rec domain.IdempotencyRecord) bool {
+		return rec.UserID == 1 && rec.Key == "key-1" && rec.RequestHash != "" && rec.ExpiresAt.After(time.Now()) &&
+			rec.LeaseToken != "" && rec.LockedUntil.After(time.Now()) && rec.LockedUntil.Before(rec.ExpiresAt)
+	})).Return(&domain.IdempotencyRecord{}, true, nil)
+	store.On("CompleteIdempotencyKey", mock.MatchedBy(func(rec domain.IdempotencyRecord) bool {
+		return rec.StatusCode == fiber.StatusAccepted && rec.TransactionID == "tx-1" && len(rec.ResponseBody) > 0 && rec.LeaseToken != ""
+	})).Return(nil)
+
+	status, _, replayed := doRequest(t, app, "key-1", `{"amount":"1"}`)
+	assert.Equal(t, fiber.StatusAccepted, status)
+	assert.Empty(t, replayed)
+	assert.Equal(t, 1, *calls)
+	store.AssertExpectations(t)
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusAccepted)
+
+	store.On("ReserveIdempotencyKey", mock.Anything).
+		Return(func(rec domain.IdempotencyRecord) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{
+				RequestHash:  rec.RequestHash,
+				Completed:    true,
+				StatusCode:   fiber.StatusAccepted,
+				ResponseBody: []byte(`{"transaction_id":"tx-original"}`),
+			}, false, nil
+		})
+
+	status, body, replayed := doRequest(t, app, "key-1", `{"amount":"1"}`)
+	assert.Equal(t, fiber.StatusAccepted, status)
+	assert.Equal(t, `{"transaction_id":"tx-original"}`, body)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotency_DifferentBodyConflict(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusAccepted)
+
+	store.On("ReserveIdempotencyKey", mock.Anything).
+		Return(&domain.IdempotencyRecord{RequestHash: "another-body", Completed: true}, false, nil)
+
+	status, _, _ := doRequest(t, app, "key-1", `{"amount":"2"}`)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotency_InProgressConflict(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusAccepted)
+
+	store.On("ReserveIdempotencyKey", mock.Anything).
+		Return(func(rec domain.IdempotencyRecord) (*domain.IdempotencyRecord, bool, error) {
+			return &domain.IdempotencyRecord{RequestHash: rec.RequestHash}, false, nil
+		})
+
+	status, _, _ := doRequest(t, app, "key-1", `{"amount":"1"}`)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, _ := setupIdempotencyApp(store, fiber.StatusInternalServerError)
+
+	var reserved domain.IdempotencyRecord
+	store.On("ReserveIdempotencyKey", mock.Anything).Run(func(args mock.Arguments) {
+		reserved = args.Get(0).(domain.IdempotencyRecord)
+	}).Return(&domain.IdempotencyRecord{}, true, nil)
+	store.On("ReleaseIdempotencyKey", mock.MatchedBy(func(rec domain.IdempotencyRecord) bool {
+		return rec.UserID == 1 && rec.Key == "key-1" && rec.LeaseToken == reserved.LeaseToken
+	})).Return(nil)
+
+	status, _, _ := doRequest(t, app, "key-1", `{"amount":"1"}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	store.AssertNumberOfCalls(t, "ReleaseIdempotencyKey", 1)
+	store.AssertNotCalled(t, "CompleteIdempotencyKey", mock.Anything)
+}
+
+func TestIdempotency_RateLimitedReleasesKey(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusTooManyRequests)
+
+	// liberada a chave, a nova tentativa a reserva de novo em vez de receber o 429 gravado
+	store.On("ReserveIdempotencyKey", mock.Anything).Return(&domain.IdempotencyRecord{}, true, nil).Twice()
+	store.On("ReleaseIdempotencyKey", mock.Anything).Return(nil)
+
+	for range 2 {
+		status, _, replayed := doRequest(t, app, "key-1", `{"amount":"1"}`)
+		assert.Equal(t, fiber.StatusTooManyRequests, status)
+		assert.Empty(t, replayed)
+	}
+	assert.Equal(t, 2, *calls)
+	store.AssertNumberOfCalls(t, "ReleaseIdempotencyKey", 2)
+	store.AssertNotCalled(t, "CompleteIdempotencyKey", mock.Anything)
+}
+
+func TestIdempotency_StoreError(t *testing.T) {
+	store := new(mocks.IdempotencyRepository)
+	app, calls := setupIdempotencyApp(store, fiber.StatusAccepted)
+
+	store.On("ReserveIdempotencyKey", mock.Anything).Return(nil, false, errors.New("db down"))
+
+	status, _, _ := doRequest(t, app, "key-1", `{"amount":"1"}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, 0, *calls)
+}
@@ -72,6 +72,7 @@ func JWTProtected() fiber.Handler {
 		// ✅ Salva como uint para evitar cast nos handlers
 		c.Locals("user_id", uint(userIDFloat))
 		c.Locals("email", claims["email"])
+		c.Locals("role", claims["role"])
 
 		return c.Next()
 	}
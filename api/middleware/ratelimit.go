@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"log"
+	"math"
+	"strconv"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+	RetryAfterHeader         = "Retry-After"
+)
+
+// RateLimit consome uma requisição da classe para a chave que key extrai da requisição e responde
+// 429 com Retry-After quando a cota acabou. Com o limiter fora do ar a requisição passa: o limite
+// de transações dos serviços continua valendo
+func RateLimit(limiter d.RateLimiter, class string, key func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if limiter == nil {
+			return c.Next()
+		}
+
+		res, err := limiter.Allow(class, key(c))
+		if err != nil {
+			log.Printf("⚠️ Rate limiter indisponível (%s): %v", class, err)
+			return c.Next()
+		}
+		SetRateLimitHeaders(c, res)
+		if !res.Allowed {
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": d.ErrRateLimited.Error()})
+		}
+		return c.Next()
+	}
+}
+
+// SetRateLimitHeaders escreve os cabeçalhos X-RateLimit-*; com mais de um limite na rota fica o
+// que tem menos cota sobrando
+func SetRateLimitHeaders(c *fiber.Ctx, res d.RateLimitResult) {
+	if res.Limit == 0 {
+		return
+	}
+	if current := c.GetRespHeader(RateLimitRemainingHeader); current != "" {
+		if remaining, err := strconv.Atoi(current); err == nil && remaining <= res.Remaining && res.Allowed {
+			return
+		}
+	}
+
+	c.Set(RateLimitLimitHeader, strconv.Itoa(res.Limit))
+	c.Set(RateLimitRemainingHeader, strconv.Itoa(res.Remaining))
+	c.Set(RateLimitResetHeader, strconv.Itoa(seconds(res.ResetAfter.Seconds())))
+	if !res.Allowed {
+		c.Set(RetryAfterHeader, strconv.Itoa(seconds(res.RetryAfter.Seconds())))
+	}
+}
+
+// seconds arredonda para cima: esperar menos que Retry-After ainda seria recusado
+func seconds(s float64) int {
+	return int(math.Ceil(s))
+}
+
+// KeyByIP usa o IP de origem
+func KeyByIP(c *fiber.Ctx) string {
+	return c.IP()
+}
+
+// KeyByUser usa o usuário do token e, antes da autenticação, o IP
+func KeyByUser(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("user_id").(uint); ok {
+		return "user:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	return "ip:" + c.IP()
+}
+
+// KeyByEndpoint separa a cota do usuário (ou IP) por método e caminho
+func KeyByEndpoint(c *fiber.Ctx) string {
+	return KeyByUser(c) + ":" + c.Method() + " " + c.Path()
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/api/middleware"
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimit_HeadersAndRetryAfter(t *testing.T) {
+	limiter := repositories.NewMemoryRateLimiter(map[string]domain.RateLimit{
+		domain.RateLimitIP:       {Limit: 10, Period: time.Minute},
+		domain.RateLimitEndpoint: {Limit: 2, Period: time.Minute},
+	})
+
+	app := fiber.New()
+	app.Get("/api/limited",
+		middleware.RateLimit(limiter, domain.RateLimitIP, middleware.KeyByIP),
+		middleware.RateLimit(limiter, domain.RateLimitEndpoint, middleware.KeyByEndpoint),
+		func(c *fiber.Ctx) error { return c.SendString("ok") },
+	)
+
+	for _, remaining := range []string{"1", "0"} {
+		resp, err := app.Test(httptest.NewRequest("GET", "/api/limited", nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		// vale o cabeçalho do limite com menos cota sobrando
+		assert.Equal(t, "2", resp.Header.Get(middleware.RateLimitLimitHeader))
+		assert.Equal(t, remaining, resp.Header.Get(middleware.RateLimitRemainingHeader))
+		assert.Empty(t, resp.Header.Get(middleware.RetryAfterHeader))
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/limited", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "30", resp.Header.Get(middleware.RetryAfterHeader))
+	assert.Equal(t, "60", resp.Header.Get(middleware.RateLimitResetHeader))
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	limiter := new(mocks.RateLimiter)
+	limiter.On("Allow", domain.RateLimitUser, "ip:0.0.0.0").Return(domain.RateLimitResult{}, errors.New("connection refused"))
+
+	app := fiber.New()
+	app.Get("/", middleware.RateLimit(limiter, domain.RateLimitUser, middleware.KeyByUser), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(middleware.RateLimitLimitHeader))
+	limiter.AssertExpectations(t)
+}
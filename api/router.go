@@ -2,17 +2,45 @@ package api
 
 import (
 	"github.com/gabrielksneiva/go-financial-transactions/api/middleware"
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gofiber/fiber/v2"
 )
 
-func RegisterRoutes(app *fiber.App, h *Handlers) {
+// RegisterRoutes aplica o limite por IP a todas as rotas, o por rota também ao login e ao
+// cadastro, e o por usuário depois da autenticação; limiter nil desliga os três
+func RegisterRoutes(app *fiber.App, h *Handlers, idempotency fiber.Handler, limiter d.RateLimiter) {
+	perEndpoint := middleware.RateLimit(limiter, d.RateLimitEndpoint, middleware.KeyByEndpoint)
+	app.Use("/api", middleware.RateLimit(limiter, d.RateLimitIP, middleware.KeyByIP))
 
-	app.Post("/api/login", h.LoginHandler)
-	app.Post("/api/register", h.RegisterHandler)
+	app.Post("/api/login", perEndpoint, h.LoginHandler)
+	app.Post("/api/register", perEndpoint, h.RegisterHandler)
 
-	api := app.Group("/api", middleware.JWTProtected())
-	api.Post("/deposit", h.CreateDepositHandler)
-	api.Post("/withdraw", h.CreateWithdrawHandler)
+	api := app.Group("/api", middleware.JWTProtected(),
+		middleware.RateLimit(limiter, d.RateLimitUser, middleware.KeyByUser), perEndpoint)
+	api.Post("/deposit", idempotency, h.CreateDepositHandler)
+	api.Post("/withdraw", idempotency, h.CreateWithdrawHandler)
+	api.Post("/transfers", idempotency, h.CreateTransferHandler)
+	api.Get("/fees/quote", h.GetFeeQuoteHandler)
+	api.Get("/limits", h.GetLimitsHandler)
 	api.Get("/balance/:user_id", h.GetBalanceHandler)
 	api.Get("/statement/:user_id", h.GetStatementHandler)
+	api.Get("/deposit-address", h.GetDepositAddressHandler)
+	api.Get("/withdrawal-addresses", h.ListWithdrawalAddressesHandler)
+	api.Post("/withdrawal-addresses", h.AddWithdrawalAddressHandler)
+	api.Delete("/withdrawal-addresses/:id", h.RemoveWithdrawalAddressHandler)
+	api.Get("/schedules", h.ListSchedulesHandler)
+	api.Post("/schedules", h.CreateScheduleHandler)
+	api.Get("/schedules/:id", h.GetScheduleHandler)
+	api.Put("/schedules/:id", h.UpdateScheduleHandler)
+	api.Delete("/schedules/:id", h.DeleteScheduleHandler)
+
+	admin := api.Group("/admin", middleware.AdminOnly())
+	admin.Get("/dlq", h.ListDeadLettersHandler)
+	admin.Post("/dlq/:partition/:offset/redrive", h.RedriveDeadLetterHandler)
+	admin.Get("/withdrawals/pending", h.ListPendingWithdrawalsHandler)
+	admin.Post("/withdrawals/:id/approve", h.ApproveWithdrawalHandler)
+	admin.Post("/withdrawals/:id/reject", h.RejectWithdrawalHandler)
+	admin.Get("/withdrawals/:id/decisions", h.ListApprovalDecisionsHandler)
+	admin.Put("/users/:id/limits", h.SetLimitOverrideHandler)
+	admin.Delete("/users/:id/limits", h.DeleteLimitOverrideHandler)
 }
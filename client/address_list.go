@@ -0,0 +1,52 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ErrAddressIndexOutOfRange = errors.New("no deposit address for index")
+
+// AddressList atribui endereços de depósito gerados fora do servidor (uma carteira fria
+// exporta um endereço por linha; a linha N é o índice N). O servidor nunca vê as chaves.
+type AddressList struct {
+	addresses []string
+}
+
+func NewAddressList(addresses []string) *AddressList {
+	return &AddressList{addresses: addresses}
+}
+
+// LoadAddressList lê o arquivo ignorando linhas vazias e comentários (#)
+func LoadAddressList(path string) (*AddressList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var addresses []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		addresses = append(addresses, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewAddressList(addresses), nil
+}
+
+func (l *AddressList) DeriveAddress(index uint32) (string, error) {
+	if int(index) >= len(l.addresses) {
+		return "", fmt.Errorf("%w %d (%d available)", ErrAddressIndexOutOfRange, index, len(l.addresses))
+	}
+	return l.addresses[index], nil
+}
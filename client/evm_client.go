@@ -0,0 +1,334 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+// seletor de transfer(address,uint256), o mesmo do TRC-20
+var erc20TransferSelector = crypto.Keccak256([]byte(trc20TransferMethod))[:4]
+
+// EVMBackend é a parte do nó Ethereum usada pelo driver. *ethclient.Client a implementa,
+// assim como o backend simulado do go-ethereum usado nos testes.
+type EVMBackend interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+var (
+	_ domain.BlockchainClient = (*EVMClient)(nil)
+	_ domain.NonceTracker     = (*EVMClient)(nil)
+	_ domain.AddressValidator = EVMAddressValidator{}
+)
+
+// EVMAddressValidator valida endereços Ethereum localmente
+type EVMAddressValidator struct{}
+
+func (EVMAddressValidator) ValidateAddress(addr string) error {
+	return ValidateEVMAddress(addr)
+}
+
+// EVMClient envia ETH e tokens ERC-20 (transações EIP-1559) a partir de uma única carteira
+type EVMClient struct {
+	backend EVMBackend
+	signer  domain.Signer
+	chainID *big.Int
+	native  string
+	timeout time.Duration
+
+	// o nonce é lido do nó a cada envio; dois envios simultâneos pegariam o mesmo
+	sendMu sync.Mutex
+}
+
+func NewEVMClient(backend EVMBackend, s domain.Signer, native string) (*EVMClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar chain ID: %w", err)
+	}
+
+	return &EVMClient{
+		backend: backend,
+		signer:  s,
+		chainID: chainID,
+		native:  native,
+		timeout: 10 * time.Second,
+	}, nil
+}
+
+// DialEVMClient conecta ao nó JSON-RPC
+func DialEVMClient(rpcURL string, s domain.Signer, native string) (*EVMClient, error) {
+	backend, err := ethclient.Dial(rpcURL)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao conectar em %s: %w", rpcURL, err)
+	}
+	return NewEVMClient(backend, s, native)
+}
+
+// Address é o endereço da chave ativa do signer; consultado a cada envio para seguir rotações
+func (e *EVMClient) Address() (common.Address, error) {
+	pub, err := e.signer.PublicKey()
+	if err != nil {
+		return common.Address{}, fmt.Errorf("erro ao consultar signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// ValidateAddress confere o endereço sem consultar o nó; veja ValidateEVMAddress
+func (e *EVMClient) ValidateAddress(addr string) error {
+	return ValidateEVMAddress(addr)
+}
+
+// ValidateEVMAddress aceita endereços hex de 20 bytes; com letras maiúsculas e minúsculas
+// misturadas o checksum EIP-55 precisa bater
+func ValidateEVMAddress(addr string) error {
+	if !common.IsHexAddress(addr) {
+		return fmt.Errorf("%w: %q não é um endereço Ethereum", domain.ErrInvalidAddress, addr)
+	}
+	hexPart := addr[len(addr)-40:]
+	if hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) && common.HexToAddress(addr).Hex() != "0x"+hexPart {
+		return fmt.Errorf("%w: checksum EIP-55 inválido em %q", domain.ErrInvalidAddress, addr)
+	}
+	return nil
+}
+
+// EstimateFee multiplica o gás estimado pelo preço máximo que o envio aceitaria pagar
+func (e *EVMClient) EstimateFee(tx domain.BlockchainTransaction, asset domain.Asset) (domain.Money, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	from, err := e.Address()
+	if err != nil {
+		return domain.Money{}, err
+	}
+	msg, err := e.callMsg(from, tx, asset)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	gas, err := e.estimateGas(ctx, msg)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	_, feeCap, err := e.gasPrice(ctx)
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	nativeAsset, err := domain.LookupAsset(e.native)
+	if err != nil {
+		return domain.Money{}, err
+	}
+	fee, err := nativeAsset.ScaleFromChain(new(big.Int).Mul(feeCap, new(big.Int).SetUint64(gas)))
+	if err != nil {
+		return domain.Money{}, err
+	}
+	return domain.NewMoney(fee, nativeAsset.Symbol), nil
+}
+
+// Send assina e transmite o envio. Diferente da TRON, a transação não leva o ID local:
+// em uma transferência de ETH o campo data custaria gás e em um ERC-20 ele é o calldata.
+func (e *EVMClient) Send(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	log.Printf("🚀 Iniciando envio %s (%s) para a transação %s", asset.Symbol, e.chainID, transactionID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	if err := e.ValidateAddress(tx.ToAddress); err != nil {
+		return nil, err
+	}
+	from, err := e.Address()
+	if err != nil {
+		return nil, err
+	}
+	msg, err := e.callMsg(from, tx, asset)
+	if err != nil {
+		return nil, err
+	}
+	// um ERC-20 que reverteria falha aqui, antes de gastar gás
+	gas, err := e.estimateGas(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	tip, feeCap, err := e.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+
+	nonce, err := e.backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar nonce: %w", err)
+	}
+
+	signed, err := e.sign(types.NewTx(&types.DynamicFeeTx{
+		ChainID:   e.chainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        msg.To,
+		Value:     msg.Value,
+		Data:      msg.Data,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BlockchainTxResult{
+		TxID:        signed.Hash().Hex(),
+		FromAddress: from.Hex(),
+		ToAddress:   tx.ToAddress,
+		Amount:      domain.NewMoney(tx.Amount, asset.Symbol),
+		Nonce:       &nonce,
+	}
+
+	// assinada, a transação pode ter chegado ao mempool mesmo com erro na resposta; o nonce
+	// decide depois se ela ainda pode ser minerada
+	if err := e.backend.SendTransaction(ctx, signed); err != nil {
+		return nil, &domain.BroadcastError{Result: result, Err: fmt.Errorf("erro ao transmitir TX: %w", err)}
+	}
+
+	return result, nil
+}
+
+// GetTransactionInfo lê o recibo. Sem recibo, a transação que o nó ainda conhece (no mempool
+// ou minerada e não indexada) está pendente; só a que ele não conhece é ErrBlockchainTxNotFound.
+func (e *EVMClient) GetTransactionInfo(txID string) (*domain.BlockchainTxInfo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	hash := common.HexToHash(txID)
+	receipt, err := e.backend.TransactionReceipt(ctx, hash)
+	if err != nil {
+		// o recibo pode faltar também enquanto o nó indexa; a transação em si diz se ele a conhece
+		_, _, lookupErr := e.backend.TransactionByHash(ctx, hash)
+		switch {
+		case lookupErr == nil:
+			return nil, domain.ErrBlockchainTxPending
+		case errors.Is(lookupErr, ethereum.NotFound) && errors.Is(err, ethereum.NotFound):
+			return nil, domain.ErrBlockchainTxNotFound
+		}
+		return nil, fmt.Errorf("erro ao consultar recibo: %w", err)
+	}
+
+	info := &domain.BlockchainTxInfo{
+		TxID:        txID,
+		BlockNumber: receipt.BlockNumber.Int64(),
+		Success:     receipt.Status == types.ReceiptStatusSuccessful,
+	}
+	if !info.Success {
+		info.Reason = "execution reverted"
+	}
+	return info, nil
+}
+
+// NonceConsumed diz se a carteira já tem, em bloco, uma transação com esse nonce ou posterior;
+// um envio com o nonce que não está entre elas nunca mais será minerado
+func (e *EVMClient) NonceConsumed(from string, nonce uint64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	confirmed, err := e.backend.NonceAt(ctx, common.HexToAddress(from), nil)
+	if err != nil {
+		return false, fmt.Errorf("erro ao consultar nonce: %w", err)
+	}
+	return confirmed > nonce, nil
+}
+
+func (e *EVMClient) GetLatestBlockNumber() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+
+	number, err := e.backend.BlockNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int64(number), nil
+}
+
+// callMsg monta a chamada: valor para o destino no ETH, transfer(address,uint256) no contrato do token
+func (e *EVMClient) callMsg(from common.Address, tx domain.BlockchainTransaction, asset domain.Asset) (ethereum.CallMsg, error) {
+	to := common.HexToAddress(tx.ToAddress)
+	value := asset.ScaleToChain(tx.Amount)
+
+	if asset.IsNative() {
+		return ethereum.CallMsg{From: from, To: &to, Value: value}, nil
+	}
+
+	if !common.IsHexAddress(asset.Contract) {
+		return ethereum.CallMsg{}, fmt.Errorf("contrato inválido para %s: %q", asset.Symbol, asset.Contract)
+	}
+	contract := common.HexToAddress(asset.Contract)
+	return ethereum.CallMsg{From: from, To: &contract, Value: new(big.Int), Data: encodeERC20Transfer(to, value)}, nil
+}
+
+// estimateGas também é usado no ETH nativo: um destino que é contrato gasta mais que 21000
+func (e *EVMClient) estimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	gas, err := e.backend.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao estimar gás: %w", err)
+	}
+	return gas, nil
+}
+
+// gasPrice devolve a gorjeta sugerida e o teto por gás: duas vezes a base fee do último bloco
+// mais a gorjeta, o que cobre alguns blocos de alta da base fee
+func (e *EVMClient) gasPrice(ctx context.Context) (tip, feeCap *big.Int, err error) {
+	tip, err = e.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao consultar gorjeta: %w", err)
+	}
+	head, err := e.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao consultar último bloco: %w", err)
+	}
+
+	feeCap = new(big.Int).Set(tip)
+	if head.BaseFee != nil {
+		feeCap.Add(feeCap, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	}
+	return tip, feeCap, nil
+}
+
+// sign pede ao signer a assinatura do hash EIP-155/EIP-1559 da transação
+func (e *EVMClient) sign(tx *types.Transaction) (*types.Transaction, error) {
+	txSigner := types.LatestSignerForChainID(e.chainID)
+	sig, err := e.signer.SignHash(txSigner.Hash(tx).Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar: %w", err)
+	}
+	return tx.WithSignature(txSigner, sig)
+}
+
+func encodeERC20Transfer(to common.Address, amount *big.Int) []byte {
+	data := make([]byte, 0, 4+32+32)
+	data = append(data, erc20TransferSelector...)
+	data = append(data, common.LeftPadBytes(to.Bytes(), 32)...)
+	return append(data, common.LeftPadBytes(amount.Bytes(), 32)...)
+}
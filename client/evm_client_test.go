@@ -0,0 +1,202 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/signer"
+)
+
+// tokenRuntime é um ERC-20 mínimo montado à mão: transfer(address,uint256) move saldos guardados
+// no slot de cada endereço e reverte sem saldo; balanceOf(address) lê o slot.
+//
+//	PUSH1 0 CALLDATALOAD PUSH1 e0 SHR
+//	DUP1 PUSH4 a9059cbb EQ PUSH1 transfer JUMPI
+//	PUSH4 70a08231 EQ PUSH1 balanceOf JUMPI
+//	revert:    JUMPDEST PUSH1 0 DUP1 REVERT
+//	transfer:  JUMPDEST CALLER SLOAD PUSH1 24 CALLDATALOAD DUP1 DUP3 LT PUSH1 revert JUMPI
+//	           DUP1 DUP3 SUB CALLER SSTORE PUSH1 4 CALLDATALOAD DUP1 SLOAD DUP3 ADD SWAP1 SSTORE
+//	           PUSH1 1 PUSH1 0 MSTORE PUSH1 20 PUSH1 0 RETURN
+//	balanceOf: JUMPDEST PUSH1 4 CALLDATALOAD SLOAD PUSH1 0 MSTORE PUSH1 20 PUSH1 0 RETURN
+const tokenRuntime = "60003560e01c8063a9059cbb14601e576370a08231146042575b600080fd5b33546024358082106019578082033355600435805482019055600160005260206000f35b6004355460005260206000f3"
+
+var tokenAddress = common.HexToAddress("0x00000000000000000000000000000000000E2C20")
+
+func ethAsset() domain.Asset {
+	return domain.Asset{Symbol: "ETH", Decimals: 9, OnchainDecimals: 18, Chain: domain.ChainEthereum}
+}
+
+func usdcAsset() domain.Asset {
+	return domain.Asset{Symbol: "USDC", Decimals: 6, Chain: domain.ChainEthereum, Contract: tokenAddress.Hex()}
+}
+
+func newSimulatedEVM(t *testing.T, tokenBalance int64) (*EVMClient, *simulated.Backend) {
+	domain.RegisterAsset(ethAsset())
+	domain.RegisterAsset(usdcAsset())
+
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		from: {Balance: new(big.Int).Mul(big.NewInt(100), big.NewInt(params.Ether))},
+		tokenAddress: {
+			Code:    common.FromHex(tokenRuntime),
+			Storage: map[common.Hash]common.Hash{common.BytesToHash(from.Bytes()): common.BigToHash(big.NewInt(tokenBalance))},
+		},
+	})
+	t.Cleanup(func() { backend.Close() })
+
+	c, err := NewEVMClient(backend.Client(), signer.NewPrivateKeySigner(key), "ETH")
+	require.NoError(t, err)
+	return c, backend
+}
+
+func tokenBalanceOf(t *testing.T, backend *simulated.Backend, holder common.Address) int64 {
+	value, err := backend.Client().StorageAt(context.Background(), tokenAddress, common.BytesToHash(holder.Bytes()), nil)
+	require.NoError(t, err)
+	return new(big.Int).SetBytes(value).Int64()
+}
+
+func TestEVMClient_SendETH(t *testing.T) {
+	c, backend := newSimulatedEVM(t, 0)
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	// 1.5 ETH em gwei, a unidade do saldo
+	tx := domain.BlockchainTransaction{ToAddress: to.Hex(), Amount: 1_500_000000}
+
+	fee, err := c.EstimateFee(tx, ethAsset())
+	require.NoError(t, err)
+	assert.Equal(t, "ETH", fee.Currency)
+	assert.Positive(t, fee.Units)
+
+	result, err := c.Send(tx, ethAsset(), "local-eth")
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(1_500_000000, "ETH"), result.Amount)
+	from, err := c.Address()
+	require.NoError(t, err)
+	assert.Equal(t, from.Hex(), result.FromAddress)
+	require.NotNil(t, result.Nonce)
+	assert.Equal(t, uint64(0), *result.Nonce)
+
+	// no mempool a transação é conhecida e o nonce dela ainda não foi usado
+	_, err = c.GetTransactionInfo(result.TxID)
+	assert.ErrorIs(t, err, domain.ErrBlockchainTxPending)
+	consumed, err := c.NonceConsumed(result.FromAddress, *result.Nonce)
+	require.NoError(t, err)
+	assert.False(t, consumed)
+
+	backend.Commit()
+
+	consumed, err = c.NonceConsumed(result.FromAddress, *result.Nonce)
+	require.NoError(t, err)
+	assert.True(t, consumed)
+
+	info, err := c.GetTransactionInfo(result.TxID)
+	require.NoError(t, err)
+	assert.True(t, info.Success)
+	assert.Equal(t, int64(1), info.BlockNumber)
+
+	latest, err := c.GetLatestBlockNumber()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), latest)
+
+	_, err = c.GetTransactionInfo(common.HexToHash("0xabc").Hex())
+	assert.ErrorIs(t, err, domain.ErrBlockchainTxNotFound)
+
+	balance, err := backend.Client().BalanceAt(context.Background(), to, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "1500000000000000000", balance.String())
+}
+
+// lostAnswer entrega a transação ao nó, mas devolve erro como se a resposta tivesse se perdido
+type lostAnswer struct {
+	EVMBackend
+}
+
+func (b lostAnswer) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.EVMBackend.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	return errors.New("i/o timeout")
+}
+
+func TestEVMClient_SendWithoutAnswerKeepsHashAndNonce(t *testing.T) {
+	c, _ := newSimulatedEVM(t, 0)
+	c.backend = lostAnswer{c.backend}
+	to := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	_, err := c.Send(domain.BlockchainTransaction{ToAddress: to.Hex(), Amount: 1_000000000}, ethAsset(), "local-lost")
+
+	var broadcastErr *domain.BroadcastError
+	require.True(t, errors.As(err, &broadcastErr))
+	require.NotNil(t, broadcastErr.Result.Nonce)
+	assert.Equal(t, uint64(0), *broadcastErr.Result.Nonce)
+
+	// a transação chegou ao mempool apesar do erro
+	_, err = c.GetTransactionInfo(broadcastErr.Result.TxID)
+	assert.ErrorIs(t, err, domain.ErrBlockchainTxPending)
+}
+
+func TestEVMClient_SendERC20(t *testing.T) {
+	c, backend := newSimulatedEVM(t, 100_000000)
+	from, err := c.Address()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x2222222222222222222222222222222222222222")
+
+	result, err := c.Send(domain.BlockchainTransaction{ToAddress: to.Hex(), Amount: 12_500000}, usdcAsset(), "local-usdc")
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(12_500000, "USDC"), result.Amount)
+	backend.Commit()
+
+	info, err := c.GetTransactionInfo(result.TxID)
+	require.NoError(t, err)
+	assert.True(t, info.Success)
+	assert.Equal(t, int64(87_500000), tokenBalanceOf(t, backend, from))
+	assert.Equal(t, int64(12_500000), tokenBalanceOf(t, backend, to))
+
+	// sem saldo o contrato reverte já na estimativa de gás e nada é transmitido
+	_, err = c.Send(domain.BlockchainTransaction{ToAddress: to.Hex(), Amount: 500_000000}, usdcAsset(), "local-usdc-2")
+	assert.ErrorContains(t, err, "estimar gás")
+	backend.Commit()
+	latest, err := c.GetLatestBlockNumber()
+	require.NoError(t, err)
+	block, err := backend.Client().BlockByNumber(context.Background(), big.NewInt(latest))
+	require.NoError(t, err)
+	assert.Empty(t, block.Transactions())
+}
+
+func TestEVMClient_ValidateAddress(t *testing.T) {
+	c, _ := newSimulatedEVM(t, 0)
+
+	for _, valid := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+	} {
+		assert.NoError(t, c.ValidateAddress(valid), valid)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", // checksum EIP-55
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea",
+		"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH",
+	} {
+		assert.ErrorIs(t, c.ValidateAddress(invalid), domain.ErrInvalidAddress, invalid)
+	}
+
+	_, err := c.Send(domain.BlockchainTransaction{ToAddress: "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", Amount: 1}, ethAsset(), "local-bad")
+	assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+}
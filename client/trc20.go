@@ -0,0 +1,147 @@
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/fbsobreira/gotron-sdk/pkg/abi"
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const trc20TransferMethod = "transfer(address,uint256)"
+
+// seletor de transfer(address,uint256): os 4 primeiros bytes do keccak256 da assinatura
+var trc20TransferSelector = abi.Signature(trc20TransferMethod)
+
+var ErrEnergyLimitExceeded = errors.New("estimated energy exceeds the configured limit")
+
+// SendSignedTRC20 chama transfer(address,uint256) no contrato do token. tx.Amount está na
+// unidade mínima do token; o FeeLimit do ativo limita o TRX queimado com energia.
+func (t *TronClient) SendSignedTRC20(tx domain.BlockchainTransaction, token domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	log.Printf("🚀 Iniciando envio %s (TRC-20 %s)", token.Symbol, token.Contract)
+
+	if token.IsNative() {
+		return nil, fmt.Errorf("%s não é um token TRC-20", token.Symbol)
+	}
+	// sem fee limit a chamada não pode queimar TRX por energia e falha on-chain
+	if token.FeeLimit <= 0 {
+		return nil, fmt.Errorf("fee limit não configurado para %s", token.Symbol)
+	}
+
+	from, err := t.sender()
+	if err != nil {
+		return nil, err
+	}
+
+	params := trc20TransferParams(tx)
+
+	if token.EnergyLimit > 0 {
+		estimate, err := t.backend.EstimateEnergy(from, token.Contract, trc20TransferMethod, params, 0, "", 0)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao estimar energia: %w", err)
+		}
+		if estimate.GetEnergyRequired() > token.EnergyLimit {
+			return nil, fmt.Errorf("%w: %d > %d", ErrEnergyLimitExceeded, estimate.GetEnergyRequired(), token.EnergyLimit)
+		}
+	}
+
+	extTx, err := t.backend.TriggerContract(from, token.Contract, trc20TransferMethod, params, token.FeeLimit, 0, "", 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar chamada ao contrato: %w", err)
+	}
+
+	result := &domain.BlockchainTxResult{
+		FromAddress: from,
+		ToAddress:   tx.ToAddress,
+		Amount:      domain.NewMoney(tx.Amount, token.Symbol),
+	}
+	if err := t.signAndBroadcast(extTx, transactionID, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// trc20TransferParams monta os parâmetros de transfer(address,uint256) no formato do TriggerContract
+func trc20TransferParams(tx domain.BlockchainTransaction) string {
+	return fmt.Sprintf(`[{"address":"%s"},{"uint256":"%d"}]`, tx.ToAddress, tx.Amount)
+}
+
+// decodeTRC20Transfer lê destino e valor do calldata de transfer(address,uint256);
+// ok é false para outros métodos ou valores que não cabem em int64
+func decodeTRC20Transfer(data []byte) (to string, amount int64, ok bool) {
+	if len(data) != 4+32+32 || !bytes.Equal(data[:4], trc20TransferSelector) {
+		return "", 0, false
+	}
+
+	// o endereço ocupa os 20 últimos bytes da primeira palavra; na TRON ganha o prefixo 0x41
+	to = address.Address(append([]byte{address.TronBytePrefix}, data[16:36]...)).String()
+
+	value := new(big.Int).SetBytes(data[36:68])
+	if !value.IsInt64() {
+		return "", 0, false
+	}
+	return to, value.Int64(), true
+}
+
+// transferFromTransaction converte uma transação do bloco em Transfer quando é um envio de
+// TRX ou um transfer() para um token registrado; outras transações são ignoradas
+func transferFromTransaction(txe *api.TransactionExtention) (*domain.Transfer, error) {
+	tx := txe.GetTransaction()
+	contracts := tx.GetRawData().GetContract()
+	if len(contracts) != 1 {
+		return nil, nil
+	}
+
+	success := true
+	if ret := tx.GetRet(); len(ret) > 0 {
+		success = ret[0].GetRet() == core.Transaction_Result_SUCESS &&
+			(ret[0].GetContractRet() == core.Transaction_Result_DEFAULT || ret[0].GetContractRet() == core.Transaction_Result_SUCCESS)
+	}
+
+	transfer := &domain.Transfer{
+		TxID:    hex.EncodeToString(txe.GetTxid()),
+		Success: success,
+	}
+
+	switch contracts[0].GetType() {
+	case core.Transaction_Contract_TransferContract:
+		var contract core.TransferContract
+		if err := contracts[0].GetParameter().UnmarshalTo(&contract); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar transferência: %w", err)
+		}
+		transfer.FromAddress = address.Address(contract.GetOwnerAddress()).String()
+		transfer.ToAddress = address.Address(contract.GetToAddress()).String()
+		transfer.Amount = contract.GetAmount()
+
+	case core.Transaction_Contract_TriggerSmartContract:
+		var contract core.TriggerSmartContract
+		if err := contracts[0].GetParameter().UnmarshalTo(&contract); err != nil {
+			return nil, fmt.Errorf("erro ao decodificar chamada de contrato: %w", err)
+		}
+		token, known := domain.AssetByContract(domain.ChainTron, address.Address(contract.GetContractAddress()).String())
+		if !known {
+			return nil, nil
+		}
+		to, amount, ok := decodeTRC20Transfer(contract.GetData())
+		if !ok {
+			return nil, nil
+		}
+		transfer.FromAddress = address.Address(contract.GetOwnerAddress()).String()
+		transfer.ToAddress = to
+		transfer.Amount = amount
+		transfer.Currency = token.Symbol
+
+	default:
+		return nil, nil
+	}
+
+	return transfer, nil
+}
@@ -0,0 +1,392 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fbsobreira/gotron-sdk/pkg/abi"
+	"github.com/fbsobreira/gotron-sdk/pkg/address"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/signer"
+)
+
+const usdtContract = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+
+// fakeTRC20 simula o nó e um contrato TRC-20: monta as transações como o GrpcClient,
+// confere a assinatura no broadcast e aplica transfer() nos saldos do token
+type fakeTRC20 struct {
+	contract   string
+	balances   map[string]int64
+	energy     int64
+	broadcasts []*core.Transaction
+	// broadcastErr simula a conexão com o nó caindo depois de enviar a transação assinada
+	broadcastErr error
+}
+
+func newFakeTRC20(holder string, balance int64) *fakeTRC20 {
+	return &fakeTRC20{contract: usdtContract, balances: map[string]int64{holder: balance}, energy: 14_650}
+}
+
+func (f *fakeTRC20) Transfer(from, toAddress string, amount int64) (*api.TransactionExtention, error) {
+	owner, _ := address.Base58ToAddress(from)
+	to, err := address.Base58ToAddress(toAddress)
+	if err != nil {
+		return nil, err
+	}
+	return f.build(core.Transaction_Contract_TransferContract, &core.TransferContract{
+		OwnerAddress: owner.Bytes(), ToAddress: to.Bytes(), Amount: amount,
+	}, 0)
+}
+
+func (f *fakeTRC20) TriggerContract(from, contractAddress, method, jsonString string,
+	feeLimit, tAmount int64, tTokenID string, tTokenAmount int64) (*api.TransactionExtention, error) {
+	if contractAddress != f.contract {
+		return nil, fmt.Errorf("contract %s not found", contractAddress)
+	}
+	params, err := abi.LoadFromJSON(jsonString)
+	if err != nil {
+		return nil, err
+	}
+	data, err := abi.Pack(method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, _ := address.Base58ToAddress(from)
+	contract, _ := address.Base58ToAddress(contractAddress)
+	return f.build(core.Transaction_Contract_TriggerSmartContract, &core.TriggerSmartContract{
+		OwnerAddress: owner.Bytes(), ContractAddress: contract.Bytes(), Data: data,
+	}, feeLimit)
+}
+
+func (f *fakeTRC20) EstimateEnergy(from, contractAddress, method, jsonString string,
+	tAmount int64, tTokenID string, tTokenAmount int64) (*api.EstimateEnergyMessage, error) {
+	return &api.EstimateEnergyMessage{Result: &api.Return{Result: true}, EnergyRequired: f.energy}, nil
+}
+
+func (f *fakeTRC20) UpdateHash(tx *api.TransactionExtention) error {
+	raw, err := proto.Marshal(tx.Transaction.GetRawData())
+	if err != nil {
+		return err
+	}
+	h := sha256.Sum256(raw)
+	tx.Txid = h[:]
+	return nil
+}
+
+func (f *fakeTRC20) Broadcast(tx *core.Transaction) (*api.Return, error) {
+	if f.broadcastErr != nil {
+		return nil, f.broadcastErr
+	}
+	raw, _ := proto.Marshal(tx.GetRawData())
+	h := sha256.Sum256(raw)
+	if len(tx.GetSignature()) != 1 {
+		return &api.Return{Result: false, Message: []byte("missing signature")}, nil
+	}
+	pub, err := crypto.SigToPub(h[:], tx.GetSignature()[0])
+	if err != nil {
+		return nil, err
+	}
+
+	contract := tx.GetRawData().GetContract()[0]
+	if contract.GetType() == core.Transaction_Contract_TriggerSmartContract {
+		var call core.TriggerSmartContract
+		if err := contract.GetParameter().UnmarshalTo(&call); err != nil {
+			return nil, err
+		}
+		owner := address.Address(call.GetOwnerAddress()).String()
+		if AddressFromPubKey(pub) != owner {
+			return &api.Return{Result: false, Message: []byte("signature does not match owner")}, nil
+		}
+		to, amount, ok := decodeTRC20Transfer(call.GetData())
+		if !ok {
+			return &api.Return{Result: false, Message: []byte("unknown method")}, nil
+		}
+		if f.balances[owner] < amount {
+			return &api.Return{Result: false, Message: []byte("REVERT: transfer amount exceeds balance")}, nil
+		}
+		f.balances[owner] -= amount
+		f.balances[to] += amount
+	}
+
+	f.broadcasts = append(f.broadcasts, tx)
+	return &api.Return{Result: true}, nil
+}
+
+func (f *fakeTRC20) build(kind core.Transaction_Contract_ContractType, msg proto.Message, feeLimit int64) (*api.TransactionExtention, error) {
+	param, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+	ext := &api.TransactionExtention{
+		Transaction: &core.Transaction{RawData: &core.TransactionRaw{
+			Contract: []*core.Transaction_Contract{{Type: kind, Parameter: param}},
+			FeeLimit: feeLimit,
+		}},
+		Result: &api.Return{Result: true},
+	}
+	return ext, f.UpdateHash(ext)
+}
+
+func newTestWallet(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return key, AddressFromPubKey(&key.PublicKey)
+}
+
+func usdtAsset() domain.Asset {
+	return domain.Asset{Symbol: "USDT", Decimals: 6, Contract: usdtContract, FeeLimit: 30_000000}
+}
+
+func TestSendSignedTRC20_TransfersTokens(t *testing.T) {
+	key, from := newTestWallet(t)
+	_, to := newTestWallet(t)
+	backend := newFakeTRC20(from, 100_000000)
+	c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+	tx := domain.BlockchainTransaction{FromAddress: from, ToAddress: to, Amount: 12_500000}
+	result, err := c.SendSignedTRC20(tx, usdtAsset(), "local-tx-1")
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.NewMoney(12_500000, "USDT"), result.Amount)
+	assert.Equal(t, int64(87_500000), backend.balances[from])
+	assert.Equal(t, int64(12_500000), backend.balances[to])
+
+	require.Len(t, backend.broadcasts, 1)
+	sent := backend.broadcasts[0]
+	assert.Equal(t, int64(30_000000), sent.GetRawData().GetFeeLimit())
+	assert.Equal(t, "local-tx-1", string(sent.GetRawData().GetData()))
+
+	// o txID devolvido é o hash do raw_data já com o memo e o fee limit
+	raw, _ := proto.Marshal(sent.GetRawData())
+	h := sha256.Sum256(raw)
+	assert.Equal(t, hex.EncodeToString(h[:]), result.TxID)
+}
+
+func TestSendSignedTRC20_Rejections(t *testing.T) {
+	key, from := newTestWallet(t)
+	_, to := newTestWallet(t)
+	tx := domain.BlockchainTransaction{ToAddress: to, Amount: 1_000000}
+
+	t.Run("energy above limit", func(t *testing.T) {
+		backend := newFakeTRC20(from, 10_000000)
+		c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+		token := usdtAsset()
+		token.EnergyLimit = 10_000
+		_, err := c.SendSignedTRC20(tx, token, "tx-energy")
+		assert.ErrorIs(t, err, ErrEnergyLimitExceeded)
+		assert.Empty(t, backend.broadcasts)
+	})
+
+	t.Run("missing fee limit", func(t *testing.T) {
+		backend := newFakeTRC20(from, 10_000000)
+		c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+		token := usdtAsset()
+		token.FeeLimit = 0
+		_, err := c.SendSignedTRC20(tx, token, "tx-fee")
+		assert.Error(t, err)
+		assert.Empty(t, backend.broadcasts)
+	})
+
+	t.Run("contract reverts", func(t *testing.T) {
+		backend := newFakeTRC20(from, 500000)
+		c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+		_, err := c.SendSignedTRC20(tx, usdtAsset(), "tx-revert")
+		assert.ErrorContains(t, err, "exceeds balance")
+		// o nó recusou: não é um envio de resultado incerto
+		var broadcastErr *domain.BroadcastError
+		assert.False(t, errors.As(err, &broadcastErr))
+		assert.Equal(t, int64(500000), backend.balances[from])
+	})
+
+	t.Run("native asset", func(t *testing.T) {
+		c := &TronClient{backend: newFakeTRC20(from, 0), signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+		_, err := c.SendSignedTRC20(tx, domain.Asset{Symbol: "TRX", Decimals: 6}, "tx-native")
+		assert.Error(t, err)
+	})
+}
+
+func TestSendSignedTRX_UsesBackend(t *testing.T) {
+	key, from := newTestWallet(t)
+	_, to := newTestWallet(t)
+	backend := newFakeTRC20(from, 0)
+	c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+	result, err := c.SendSignedTRX(domain.BlockchainTransaction{ToAddress: to, Amount: 3_000000}, "local-trx")
+	require.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(3_000000, "TRX"), result.Amount)
+	require.Len(t, backend.broadcasts, 1)
+	assert.Equal(t, "local-trx", string(backend.broadcasts[0].GetRawData().GetData()))
+}
+
+func TestSendSignedTRX_BroadcastWithoutAnswerKeepsTheHash(t *testing.T) {
+	key, from := newTestWallet(t)
+	_, to := newTestWallet(t)
+	backend := newFakeTRC20(from, 0)
+	backend.broadcastErr = errors.New("context deadline exceeded")
+	c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+
+	_, err := c.SendSignedTRX(domain.BlockchainTransaction{ToAddress: to, Amount: 3_000000}, "local-timeout")
+
+	// a transação assinada pode ter chegado ao nó: o hash volta para ser acompanhado on-chain
+	var broadcastErr *domain.BroadcastError
+	require.True(t, errors.As(err, &broadcastErr))
+	assert.NotEmpty(t, broadcastErr.Result.TxID)
+	assert.Equal(t, from, broadcastErr.Result.FromAddress)
+	assert.Equal(t, to, broadcastErr.Result.ToAddress)
+}
+
+func TestTransferFromTransaction_TRC20(t *testing.T) {
+	domain.RegisterAsset(usdtAsset())
+
+	_, from := newTestWallet(t)
+	_, to := newTestWallet(t)
+	backend := newFakeTRC20(from, 0)
+
+	call, err := backend.TriggerContract(from, usdtContract, trc20TransferMethod,
+		fmt.Sprintf(`[{"address":"%s"},{"uint256":"%d"}]`, to, 42_000000), 30_000000, 0, "", 0)
+	require.NoError(t, err)
+
+	transfer, err := transferFromTransaction(call)
+	require.NoError(t, err)
+	require.NotNil(t, transfer)
+	assert.Equal(t, domain.Transfer{
+		TxID: hex.EncodeToString(call.GetTxid()), FromAddress: from, ToAddress: to,
+		Amount: 42_000000, Currency: "USDT", Success: true,
+	}, *transfer)
+
+	// contrato desconhecido é ignorado
+	backend.contract = "TXLAQ63Xg1NAzckPwKHvzw7CSEmLMEqcdj"
+	other, err := backend.TriggerContract(from, backend.contract, trc20TransferMethod,
+		fmt.Sprintf(`[{"address":"%s"},{"uint256":"1"}]`, to), 0, 0, "", 0)
+	require.NoError(t, err)
+	transfer, err = transferFromTransaction(other)
+	assert.NoError(t, err)
+	assert.Nil(t, transfer)
+
+	// TRX nativo continua sem moeda (TRX)
+	native, err := backend.Transfer(from, to, 7)
+	require.NoError(t, err)
+	transfer, err = transferFromTransaction(native)
+	require.NoError(t, err)
+	assert.Equal(t, "", transfer.Currency)
+	assert.Equal(t, int64(7), transfer.Amount)
+}
+
+func TestDecodeTRC20Transfer(t *testing.T) {
+	_, to := newTestWallet(t)
+
+	data, err := abi.Pack(trc20TransferMethod, []abi.Param{{"address": to}, {"uint256": "123456"}})
+	require.NoError(t, err)
+	decodedTo, amount, ok := decodeTRC20Transfer(data)
+	assert.True(t, ok)
+	assert.Equal(t, to, decodedTo)
+	assert.Equal(t, int64(123456), amount)
+
+	// valor maior que int64
+	huge := new(big.Int).Lsh(big.NewInt(1), 70)
+	data, err = abi.Pack(trc20TransferMethod, []abi.Param{{"address": to}, {"uint256": huge.String()}})
+	require.NoError(t, err)
+	_, _, ok = decodeTRC20Transfer(data)
+	assert.False(t, ok)
+
+	// outro método (approve)
+	data, err = abi.Pack("approve(address,uint256)", []abi.Param{{"address": to}, {"uint256": "1"}})
+	require.NoError(t, err)
+	_, _, ok = decodeTRC20Transfer(data)
+	assert.False(t, ok)
+
+	_, _, ok = decodeTRC20Transfer([]byte{0xa9})
+	assert.False(t, ok)
+}
+
+func TestTronClient_ValidateAddress(t *testing.T) {
+	_, valid := newTestWallet(t)
+	c := &TronClient{}
+
+	assert.NoError(t, c.ValidateAddress(valid))
+	for _, invalid := range []string{
+		"",
+		valid[:len(valid)-1] + "1",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"1BoatSLRHtKNngkdXEeobR76b53LETtpyT", // Base58Check válido, mas com o prefixo do Bitcoin
+	} {
+		assert.ErrorIs(t, c.ValidateAddress(invalid), domain.ErrInvalidAddress, invalid)
+	}
+}
+
+func TestTronClient_EstimateFeeAndSend(t *testing.T) {
+	key, from := newTestWallet(t)
+	_, to := newTestWallet(t)
+	backend := newFakeTRC20(from, 50_000000)
+	c := &TronClient{backend: backend, signer: signer.NewPrivateKeySigner(key), fromAddress: from}
+	tx := domain.BlockchainTransaction{ToAddress: to, Amount: 1_000000}
+
+	native, err := c.EstimateFee(tx, domain.Asset{Symbol: "TRX", Decimals: 6})
+	require.NoError(t, err)
+	token, err := c.EstimateFee(tx, usdtAsset())
+	require.NoError(t, err)
+
+	assert.Equal(t, "TRX", native.Currency)
+	assert.Positive(t, native.Units)
+	// o TRC-20 paga a energia estimada pelo contrato além da banda
+	assert.GreaterOrEqual(t, token.Units-native.Units, backend.energy*sunPerEnergy)
+	assert.Empty(t, backend.broadcasts)
+
+	_, err = c.Send(tx, usdtAsset(), "local-send")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1_000000), backend.balances[to])
+}
+
+func TestTronClient_FollowsSignerRotation(t *testing.T) {
+	signer.ScryptN, signer.ScryptP = keystore.LightScryptN, keystore.LightScryptP
+	ks, err := signer.CreateKeystore(t.TempDir(), "pass")
+	require.NoError(t, err)
+	pub, _ := ks.PublicKey()
+	first := AddressFromPubKey(pub)
+
+	_, to := newTestWallet(t)
+	backend := newFakeTRC20(first, 0)
+	c := &TronClient{backend: backend, signer: ks}
+
+	result, err := c.SendSignedTRX(domain.BlockchainTransaction{ToAddress: to, Amount: 1}, "before-rotation")
+	require.NoError(t, err)
+	assert.Equal(t, first, result.FromAddress)
+
+	// depois da rotação os envios saem da nova chave, e o fake confere a assinatura contra ela
+	_, err = ks.Rotate()
+	require.NoError(t, err)
+	backend.balances = map[string]int64{}
+	result, err = c.SendSignedTRC20(domain.BlockchainTransaction{ToAddress: to, Amount: 0}, usdtAsset(), "after-rotation")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, result.FromAddress)
+
+	// com TRON_FROM_ADDR fixado, uma chave diferente é recusada antes de montar a transação
+	pinned := &TronClient{backend: backend, signer: ks, fromAddress: first}
+	_, err = pinned.SendSignedTRX(domain.BlockchainTransaction{ToAddress: to, Amount: 1}, "pinned")
+	assert.ErrorContains(t, err, "não pertence")
+	// e a subida da API recusa o endereço fixado antes de aceitar saques
+	_, err = pinned.ActiveAddress()
+	assert.ErrorContains(t, err, "não pertence")
+	active, err := c.ActiveAddress()
+	require.NoError(t, err)
+	assert.Equal(t, result.FromAddress, active)
+}
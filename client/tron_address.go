@@ -0,0 +1,135 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mr-tron/base58"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const (
+	tronAddressPrefix = 0x41
+	// prefixo + 20 bytes do hash da chave pública
+	tronAddressLength = 21
+	checksumLength    = 4
+)
+
+var (
+	_ domain.AddressValidator = TronAddressValidator{}
+	_ domain.AddressValidator = (*NodeAddressValidator)(nil)
+)
+
+// DecodeTronAddress devolve os 21 bytes do endereço. Aceita o Base58Check (T...) e a forma
+// hex (41..., com ou sem 0x); confere o checksum, o prefixo 0x41 e o tamanho sem consultar o nó.
+func DecodeTronAddress(addr string) ([]byte, error) {
+	if hexPart, ok := tronHexForm(addr); ok {
+		raw, err := hex.DecodeString(hexPart)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q não é hex válido", domain.ErrInvalidAddress, addr)
+		}
+		return raw, nil
+	}
+
+	decoded, err := base58.Decode(addr)
+	if err != nil || len(decoded) != tronAddressLength+checksumLength {
+		return nil, fmt.Errorf("%w: %q não é um endereço TRON", domain.ErrInvalidAddress, addr)
+	}
+	raw, sum := decoded[:tronAddressLength], decoded[tronAddressLength:]
+	if !bytes.Equal(sum, tronChecksum(raw)) {
+		return nil, fmt.Errorf("%w: checksum inválido em %q", domain.ErrInvalidAddress, addr)
+	}
+	if raw[0] != tronAddressPrefix {
+		return nil, fmt.Errorf("%w: %q não tem o prefixo 0x41", domain.ErrInvalidAddress, addr)
+	}
+	return raw, nil
+}
+
+// ValidateTronAddress é a validação offline de DecodeTronAddress
+func ValidateTronAddress(addr string) error {
+	_, err := DecodeTronAddress(addr)
+	return err
+}
+
+// TronAddressToBase58 normaliza um endereço válido (em qualquer das duas formas) para o Base58Check
+func TronAddressToBase58(addr string) (string, error) {
+	raw, err := DecodeTronAddress(addr)
+	if err != nil {
+		return "", err
+	}
+	return base58.Encode(append(raw, tronChecksum(raw)...)), nil
+}
+
+// tronHexForm reconhece 42 dígitos hex começando em 41, com ou sem 0x
+func tronHexForm(addr string) (string, bool) {
+	hexPart := strings.TrimPrefix(strings.TrimPrefix(addr, "0x"), "0X")
+	if len(hexPart) != tronAddressLength*2 || !strings.HasPrefix(hexPart, "41") {
+		return "", false
+	}
+	return hexPart, true
+}
+
+// tronChecksum são os 4 primeiros bytes do sha256 duplo do payload
+func tronChecksum(payload []byte) []byte {
+	h1 := sha256.Sum256(payload)
+	h2 := sha256.Sum256(h1[:])
+	return h2[:checksumLength]
+}
+
+// TronAddressValidator valida endereços TRON localmente
+type TronAddressValidator struct{}
+
+func (TronAddressValidator) ValidateAddress(addr string) error {
+	return ValidateTronAddress(addr)
+}
+
+type validateRequest struct {
+	Address string `json:"address"`
+}
+type validateResponse struct {
+	Result  bool   `json:"result"`
+	Message string `json:"message"`
+}
+
+// NodeAddressValidator faz a checagem offline e depois pergunta ao nó (/wallet/validateaddress).
+// É opcional: deixa o cadastro dependente do nó estar no ar.
+type NodeAddressValidator struct {
+	url  string
+	http *http.Client
+}
+
+func NewNodeAddressValidator(nodeURL string) *NodeAddressValidator {
+	return &NodeAddressValidator{
+		url:  strings.TrimRight(nodeURL, "/") + "/wallet/validateaddress",
+		http: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (v *NodeAddressValidator) ValidateAddress(addr string) error {
+	if err := ValidateTronAddress(addr); err != nil {
+		return err
+	}
+
+	b, _ := json.Marshal(validateRequest{Address: addr})
+	resp, err := v.http.Post(v.url, "application/json", bytes.NewBuffer(b))
+	if err != nil {
+		return fmt.Errorf("erro ao validar endereço: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result validateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("erro ao decodificar validação: %w", err)
+	}
+	if !result.Result {
+		return fmt.Errorf("%w: %q recusado pelo nó: %s", domain.ErrInvalidAddress, addr, result.Message)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const (
+	knownAddress    = "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+	knownAddressHex = "41c8599111f29c1e1e061265b4af93ea1f274ad78a"
+)
+
+func TestDecodeTronAddress(t *testing.T) {
+	for _, form := range []string{knownAddress, knownAddressHex, "0x" + knownAddressHex, "41C8599111F29C1E1E061265B4AF93EA1F274AD78A"} {
+		raw, err := DecodeTronAddress(form)
+		require.NoError(t, err, form)
+		assert.Len(t, raw, tronAddressLength)
+
+		normalized, err := TronAddressToBase58(form)
+		require.NoError(t, err)
+		assert.Equal(t, knownAddress, normalized)
+	}
+
+	// checksum correto, mas com outro prefixo de rede
+	payload, _ := DecodeTronAddress(knownAddressHex)
+	payload = append([]byte{0x42}, payload[1:]...)
+	wrongPrefix := base58.Encode(append(payload, tronChecksum(payload)...))
+
+	for _, invalid := range []string{
+		"",
+		"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdh", // checksum
+		"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWY",   // tamanho
+		wrongPrefix,
+		"42c8599111f29c1e1e061265b4af93ea1f274ad78a",
+		"41c8599111f29c1e1e061265b4af93ea1f274ad7",
+		"41c8599111f29c1e1e061265b4af93ea1f274ad7zz",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	} {
+		assert.ErrorIs(t, ValidateTronAddress(invalid), domain.ErrInvalidAddress, invalid)
+	}
+}
+
+func TestNodeAddressValidator(t *testing.T) {
+	var calls int
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/wallet/validateaddress", r.URL.Path)
+		var req validateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		_ = json.NewEncoder(w).Encode(validateResponse{Result: req.Address == knownAddress, Message: "Invalid address"})
+	}))
+	defer node.Close()
+
+	v := NewNodeAddressValidator(node.URL + "/")
+	assert.NoError(t, v.ValidateAddress(knownAddress))
+	assert.ErrorIs(t, v.ValidateAddress(knownAddressHex), domain.ErrInvalidAddress)
+	assert.Equal(t, 2, calls)
+
+	// a checagem offline recusa antes de chamar o nó
+	assert.ErrorIs(t, v.ValidateAddress("TInvalido"), domain.ErrInvalidAddress)
+	assert.Equal(t, 2, calls)
+
+	node.Close()
+	assert.ErrorContains(t, v.ValidateAddress(knownAddress), "erro ao validar endereço")
+}
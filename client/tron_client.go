@@ -2,17 +2,20 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/ecdsa"
 	"crypto/sha256"
-	"encoding/json"
+	"encoding/hex"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"time"
 
 	"github.com/ethereum/go-ethereum/crypto"      // FromECDSAPub, Keccak256, Sign
 	"github.com/fbsobreira/gotron-sdk/pkg/client" // gRPC client :contentReference[oaicite:7]{index=7}
-	"github.com/mr-tron/base58"                   // Base58Check :contentReference[oaicite:8]{index=8}
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/api"
+	"github.com/fbsobreira/gotron-sdk/pkg/proto/core"
+	"github.com/mr-tron/base58" // Base58Check :contentReference[oaicite:8]{index=8}
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/proto"
@@ -20,37 +23,70 @@ import (
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 )
 
-type validateRequest struct {
-	Address string `json:"address"`
-}
-type validateResponse struct {
-	Result  bool   `json:"result"`
-	Message string `json:"message"`
+// Backend é a parte do nó usada para montar e transmitir transações. *client.GrpcClient
+// a implementa; os testes usam um backend falso que simula o contrato TRC-20.
+type Backend interface {
+	Transfer(from, toAddress string, amount int64) (*api.TransactionExtention, error)
+	TriggerContract(from, contractAddress, method, jsonString string,
+		feeLimit, tAmount int64, tTokenID string, tTokenAmount int64) (*api.TransactionExtention, error)
+	EstimateEnergy(from, contractAddress, method, jsonString string,
+		tAmount int64, tTokenID string, tTokenAmount int64) (*api.EstimateEnergyMessage, error)
+	UpdateHash(tx *api.TransactionExtention) error
+	Broadcast(tx *core.Transaction) (*api.Return, error)
 }
 
+// Preços da rede quando a carteira não tem banda nem energia em stake: a taxa é queimada em SUN
+const (
+	sunPerBandwidthByte = 1000
+	sunPerEnergy        = 420
+	// assinatura, resultado e o memo com o UUID local somados ao tamanho da transação montada
+	bandwidthOverhead = 65 + 64 + 36
+)
+
+var _ domain.BlockchainClient = (*TronClient)(nil)
+
 type TronClient struct {
-	grpcClient  *client.GrpcClient
-	privateKey  *ecdsa.PrivateKey
+	grpcClient *client.GrpcClient
+	backend    Backend
+	signer     domain.Signer
+	// fromAddress, quando definido, fixa a carteira esperada: um signer com outra chave é recusado
 	fromAddress string
 }
 
-func NewTronClient() domain.BlockchainClient {
+// NewTronClient conecta ao nó; as transações são assinadas pelo signer, que pode estar em
+// outro processo, e saem do endereço da chave ativa dele
+func NewTronClient(s domain.Signer) *TronClient {
 	grpcCli := client.NewGrpcClient(os.Getenv("TRON_GRPC_URL"))
 	if err := grpcCli.Start(grpc.WithTransportCredentials(insecure.NewCredentials())); err != nil {
 		log.Fatalf("❌ Erro ao conectar gRPC TRON: %v", err)
 	}
-	pkHex := os.Getenv("TRON_PRIVATE_KEY")
-	pk, err := crypto.HexToECDSA(pkHex) // HexToECDSA parseia chave sem 0x
-	if err != nil {
-		log.Fatalf("❌ TRON_PRIVATE_KEY inválida: %v", err)
-	}
 	return &TronClient{
 		grpcClient:  grpcCli,
-		privateKey:  pk,
+		backend:     grpcCli,
+		signer:      s,
 		fromAddress: os.Getenv("TRON_FROM_ADDR"),
 	}
 }
 
+// sender devolve o endereço da chave ativa do signer; consultado a cada envio para seguir rotações
+func (t *TronClient) sender() (string, error) {
+	pub, err := t.signer.PublicKey()
+	if err != nil {
+		return "", fmt.Errorf("erro ao consultar signer: %w", err)
+	}
+	derived := AddressFromPubKey(pub)
+	if t.fromAddress != "" && derived != t.fromAddress {
+		return "", fmt.Errorf("chave do signer (%s) não pertence a %s", derived, t.fromAddress)
+	}
+	return derived, nil
+}
+
+// ActiveAddress devolve o endereço da chave ativa do signer, recusando-o se TRON_FROM_ADDR fixar
+// outro; a subida usa para não aceitar saques que falhariam todos no envio
+func (t *TronClient) ActiveAddress() (string, error) {
+	return t.sender()
+}
+
 func AddressFromPubKey(pub *ecdsa.PublicKey) string {
 	uncompressed := crypto.FromECDSAPub(pub)   // 0x04‖X‖Y :contentReference[oaicite:9]{index=9}
 	hash := crypto.Keccak256(uncompressed[1:]) // Keccak256 nas coordenadas :contentReference[oaicite:10]{index=10}
@@ -60,72 +96,186 @@ func AddressFromPubKey(pub *ecdsa.PublicKey) string {
 	return base58.Encode(append(payload, h2[:4]...)) // Base58Check :contentReference[oaicite:11]{index=11}
 }
 
+// ValidateAddress confere o endereço sem consultar o nó; veja ValidateTronAddress
+func (t *TronClient) ValidateAddress(addr string) error {
+	return ValidateTronAddress(addr)
+}
+
+// EstimateFee monta a transação sem assinar e calcula a banda (e a energia, no TRC-20) que
+// ela consome, cobrada em TRX quando a carteira não tem recursos em stake
+func (t *TronClient) EstimateFee(tx domain.BlockchainTransaction, asset domain.Asset) (domain.Money, error) {
+	from, err := t.sender()
+	if err != nil {
+		return domain.Money{}, err
+	}
+
+	var (
+		extTx  *api.TransactionExtention
+		energy int64
+	)
+	if asset.IsNative() {
+		extTx, err = t.backend.Transfer(from, tx.ToAddress, tx.Amount)
+	} else {
+		params := trc20TransferParams(tx)
+		estimate, estErr := t.backend.EstimateEnergy(from, asset.Contract, trc20TransferMethod, params, 0, "", 0)
+		if estErr != nil {
+			return domain.Money{}, fmt.Errorf("erro ao estimar energia: %w", estErr)
+		}
+		energy = estimate.GetEnergyRequired()
+		extTx, err = t.backend.TriggerContract(from, asset.Contract, trc20TransferMethod, params, asset.FeeLimit, 0, "", 0)
+	}
+	if err != nil {
+		return domain.Money{}, fmt.Errorf("erro ao montar transação: %w", err)
+	}
+
+	bandwidth := int64(proto.Size(extTx.GetTransaction()) + bandwidthOverhead)
+	return domain.NewMoney(bandwidth*sunPerBandwidthByte+energy*sunPerEnergy, "TRX"), nil
+}
+
+// Send envia TRX nativo ou chama o contrato TRC-20 do ativo
+func (t *TronClient) Send(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	if asset.IsNative() {
+		return t.SendSignedTRX(tx, transactionID)
+	}
+	return t.SendSignedTRC20(tx, asset, transactionID)
+}
+
 func (t *TronClient) SendSignedTRX(tx domain.BlockchainTransaction, transactionID string) (*domain.BlockchainTxResult, error) {
 	log.Println("🚀 Iniciando envio TRX (Shasta)")
 
-	derived := AddressFromPubKey(&t.privateKey.PublicKey)
-	if derived != t.fromAddress {
-		return nil, fmt.Errorf("chave privada não pertence a %s", t.fromAddress)
+	from, err := t.sender()
+	if err != nil {
+		return nil, err
 	}
 
 	// 1. Cria a transação inicial via gRPC (Transfer)
-	extTx, err := t.grpcClient.Transfer(t.fromAddress, tx.ToAddress, tx.Amount)
+	extTx, err := t.backend.Transfer(from, tx.ToAddress, tx.Amount)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar transação: %w", err)
 	}
 
-	// 2. Injeta o ID local (UUID) no campo raw_data.data para tornar o payload único
+	result := &domain.BlockchainTxResult{
+		FromAddress: from,
+		ToAddress:   tx.ToAddress,
+		Amount:      domain.NewMoney(tx.Amount, "TRX"),
+	}
+	if err := t.signAndBroadcast(extTx, transactionID, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// signAndBroadcast grava o ID local no memo, assina e transmite, preenchendo o txID on-chain em
+// result. Uma falha no transporte depois da assinatura vem como *domain.BroadcastError.
+func (t *TronClient) signAndBroadcast(extTx *api.TransactionExtention, transactionID string, result *domain.BlockchainTxResult) error {
+	// 1. Injeta o ID local (UUID) no campo raw_data.data para tornar o payload único
 	extTx.Transaction.RawData.Data = []byte(transactionID) // raw_data.data é campo de memo :contentReference[oaicite:3]{index=3}
 
-	// 3. Recalcula o hash (txID) após modificar raw_data
-	if err := t.grpcClient.UpdateHash(extTx); err != nil {
-		return nil, fmt.Errorf("falha ao atualizar hash após injetar ID: %w", err)
+	// 2. Recalcula o hash (txID) após modificar raw_data
+	if err := t.backend.UpdateHash(extTx); err != nil {
+		return fmt.Errorf("falha ao atualizar hash após injetar ID: %w", err)
 	}
 
-	// 4. Serializa o raw_data já atualizado
+	// 3. Serializa o raw_data já atualizado
 	rawBytes, err := proto.Marshal(extTx.Transaction.GetRawData())
 	if err != nil {
-		return nil, fmt.Errorf("erro ao serializar raw_data: %w", err)
+		return fmt.Errorf("erro ao serializar raw_data: %w", err)
 	}
 
-	// 5. Calcula SHA-256 e gera a assinatura
+	// 4. Calcula SHA-256 e pede a assinatura ao signer
 	h := sha256.Sum256(rawBytes) // protocolo TRON usa SHA-256 :contentReference[oaicite:4]{index=4}
-	sig, err := crypto.Sign(h[:], t.privateKey)
+	sig, err := t.signer.SignHash(h[:])
 	if err != nil {
-		return nil, fmt.Errorf("erro ao assinar: %w", err)
+		return fmt.Errorf("erro ao assinar: %w", err)
 	}
 	extTx.Transaction.Signature = append(extTx.Transaction.Signature, sig)
 
-	// 6. Transmite a transação para o fullnode
-	res, err := t.grpcClient.Broadcast(extTx.Transaction)
+	result.TxID = fmt.Sprintf("%x", extTx.GetTxid())
+
+	// 5. Transmite a transação para o fullnode. Sem resposta, ela pode ter chegado à rede, e
+	// DUP_TRANSACTION_ERROR diz que chegou; só uma recusa explícita garante que não foi enviada
+	res, err := t.backend.Broadcast(extTx.Transaction)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao transmitir TX: %w", err)
+		return &domain.BroadcastError{Result: result, Err: fmt.Errorf("erro ao transmitir TX: %w", err)}
 	}
 	if !res.Result {
-		return nil, fmt.Errorf("falha no broadcast: %s", res.String())
+		err := fmt.Errorf("falha no broadcast: %s", res.String())
+		if res.GetCode() == api.Return_DUP_TRANSACTION_ERROR {
+			return &domain.BroadcastError{Result: result, Err: err}
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetTransactionInfo consulta a transação pelo txID; um TransactionInfo vazio indica
+// que o nó ainda não a incluiu em um bloco.
+func (t *TronClient) GetTransactionInfo(txID string) (*domain.BlockchainTxInfo, error) {
+	id, err := hex.DecodeString(txID)
+	if err != nil {
+		return nil, fmt.Errorf("txID inválido %q: %w", txID, err)
 	}
 
-	// 7. Retorna o resultado, incluindo o novo txID
-	txID := fmt.Sprintf("%x", extTx.GetTxid())
-	return &domain.BlockchainTxResult{
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	info, err := t.grpcClient.Client.GetTransactionInfoById(ctx, &api.BytesMessage{Value: id})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar transação: %w", err)
+	}
+	if !bytes.Equal(info.GetId(), id) {
+		return nil, domain.ErrBlockchainTxNotFound
+	}
+
+	// chamadas de contrato (TRC-20) que reverteram ou ficaram sem energia têm o recibo != SUCCESS
+	receipt := info.GetReceipt().GetResult()
+	result := &domain.BlockchainTxInfo{
 		TxID:        txID,
-		FromAddress: t.fromAddress,
-		ToAddress:   tx.ToAddress,
-		Amount:      float64(tx.Amount) / 1e6,
-	}, nil
+		BlockNumber: info.GetBlockNumber(),
+		Success: info.GetResult() == core.TransactionInfo_SUCESS &&
+			(receipt == core.Transaction_Result_DEFAULT || receipt == core.Transaction_Result_SUCCESS),
+	}
+	if !result.Success {
+		result.Reason = string(info.GetResMessage())
+		if result.Reason == "" {
+			result.Reason = receipt.String()
+		}
+	}
+	return result, nil
 }
 
-func ValidateTronAddress(address string) (bool, error) {
-	url := os.Getenv("TRON_URL") + "/wallet/validateaddress"
-	b, _ := json.Marshal(validateRequest{Address: address})
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(b))
+func (t *TronClient) GetLatestBlockNumber() (int64, error) {
+	block, err := t.grpcClient.GetNowBlock()
 	if err != nil {
-		return false, fmt.Errorf("erro ao validar endereço: %w", err)
+		return 0, err
 	}
-	defer resp.Body.Close()
-	var result validateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false, fmt.Errorf("erro ao decodificar validação: %w", err)
+	return block.GetBlockHeader().GetRawData().GetNumber(), nil
+}
+
+// GetBlock lê o bloco e extrai as transferências nativas de TRX (TransferContract) e as
+// chamadas transfer(address,uint256) aos contratos TRC-20 registrados (TriggerSmartContract)
+func (t *TronClient) GetBlock(number int64) (*domain.Block, error) {
+	ext, err := t.grpcClient.GetBlockByNum(number)
+	if err != nil {
+		return nil, err
+	}
+
+	block := &domain.Block{
+		Number:     ext.GetBlockHeader().GetRawData().GetNumber(),
+		Hash:       hex.EncodeToString(ext.GetBlockid()),
+		ParentHash: hex.EncodeToString(ext.GetBlockHeader().GetRawData().GetParentHash()),
 	}
-	return result.Result, nil
+
+	for _, txe := range ext.GetTransactions() {
+		transfer, err := transferFromTransaction(txe)
+		if err != nil {
+			return nil, fmt.Errorf("bloco %d: %w", number, err)
+		}
+		if transfer != nil {
+			block.Transfers = append(block.Transfers, *transfer)
+		}
+	}
+
+	return block, nil
 }
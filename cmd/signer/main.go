@@ -0,0 +1,92 @@
+// Processo signer: destranca o keystore e atende assinaturas pelo socket Unix. A API e os
+// workers usam SIGNER_SOCKET e nunca carregam a chave.
+//
+//	go run ./cmd/signer -init     # cria o keystore com a primeira chave
+//	go run ./cmd/signer -rotate   # gera uma nova chave ativa e sai; vale após reiniciar o signer
+//	go run ./cmd/signer           # atende em SIGNER_SOCKET
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/joho/godotenv"
+
+	"github.com/gabrielksneiva/go-financial-transactions/client"
+	"github.com/gabrielksneiva/go-financial-transactions/signer"
+)
+
+func main() {
+	_ = godotenv.Load(".env")
+
+	dir := flag.String("keystore", os.Getenv("KEYSTORE_DIR"), "diretório do keystore")
+	socket := flag.String("socket", os.Getenv("SIGNER_SOCKET"), "caminho do socket Unix")
+	initKeystore := flag.Bool("init", false, "cria o keystore com uma nova chave e sai")
+	rotate := flag.Bool("rotate", false, "gera uma nova chave ativa e sai")
+	flag.Parse()
+
+	passphrase := os.Getenv("KEYSTORE_PASSPHRASE")
+	if *dir == "" || passphrase == "" {
+		log.Fatal("❌ KEYSTORE_DIR e KEYSTORE_PASSPHRASE são obrigatórios")
+	}
+
+	var (
+		ks  *signer.Keystore
+		err error
+	)
+	if *initKeystore {
+		ks, err = signer.CreateKeystore(*dir, passphrase)
+	} else {
+		ks, err = signer.OpenKeystore(*dir, passphrase)
+	}
+	if err != nil {
+		log.Fatalf("❌ Erro ao abrir keystore: %v", err)
+	}
+
+	if *rotate {
+		if _, err := ks.Rotate(); err != nil {
+			log.Fatalf("❌ Erro ao rotacionar chave: %v", err)
+		}
+	}
+	address := printAddresses(ks)
+	if *rotate {
+		// o processo que atende o socket (ou a API com KEYSTORE_DIR) segue com a chave que destrancou
+		fmt.Printf("🔁 Próximos passos: abasteça o endereço novo, defina TRON_FROM_ADDR=%s e reinicie o signer e a API\n", address)
+	}
+	if *initKeystore || *rotate {
+		return
+	}
+	if from := os.Getenv("TRON_FROM_ADDR"); from != "" && from != address {
+		log.Fatalf("❌ TRON_FROM_ADDR (%s) não é o endereço da chave ativa (%s); atualize-o após a rotação", from, address)
+	}
+
+	if *socket == "" {
+		log.Fatal("❌ SIGNER_SOCKET é obrigatório")
+	}
+	listener, err := signer.ListenUnix(*socket)
+	if err != nil {
+		log.Fatalf("❌ Erro ao abrir socket: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("🔐 Signer atendendo em %s", *socket)
+	if err := signer.Serve(ctx, listener, ks); err != nil {
+		log.Fatalf("❌ Signer: %v", err)
+	}
+}
+
+// printAddresses mostra os endereços da chave ativa, que precisam ser abastecidos, e devolve o TRON
+func printAddresses(ks *signer.Keystore) string {
+	pub, _ := ks.PublicKey()
+	tron := client.AddressFromPubKey(pub)
+	fmt.Printf("🔑 Chave ativa\n   TRON:     %s\n   Ethereum: %s\n", tron, crypto.PubkeyToAddress(*pub).Hex())
+	return tron
+}
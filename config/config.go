@@ -1,18 +1,76 @@
 package config
 
+import "time"
+
 type Config struct {
-	APIPort      string
-	FrontendPort string
-	KafkaBroker  string
-	KafkaTopic   string
-	KafkaGroupID string
-	DBHost       string
-	DBPort       string
-	DBUser       string
-	DBPassword   string
-	DBName       string
-	RedisHost    string
-	RedisDB      int
-	JwtSecret    string
-	TronWallet   string
+	APIPort       string
+	FrontendPort  string
+	KafkaBroker   string
+	KafkaTopic    string
+	KafkaGroupID  string
+	KafkaDLQTopic string
+	DBHost        string
+	DBPort        string
+	DBUser        string
+	DBPassword    string
+	DBName        string
+	RedisHost     string
+	RedisDB       int
+	JwtSecret     string
+	TronWallet    string
+
+	LedgerCheckInterval time.Duration
+	IdempotencyTTL      time.Duration
+	// WithdrawalAddressCooldown é o tempo até um destino de saque recém-cadastrado poder ser usado
+	WithdrawalAddressCooldown time.Duration
+	// ApprovalThresholds lista por moeda o valor a partir do qual um saque exige aprovação ("TRX:50000")
+	ApprovalThresholds string
+	// FeeRulesFile é o JSON com as regras de tarifa de saques e transferências; vazio não cobra tarifa
+	FeeRulesFile string
+	// LimitsFile é o JSON com os tetos de saque e depósito por papel e nível de KYC; vazio não limita
+	LimitsFile string
+	// RateLimits são as cotas por classe de chave (ip, user, endpoint, transaction), ex.: "ip:300/1m"
+	RateLimits string
+
+	RetryMaxAttempts    int
+	RetryInitialBackoff time.Duration
+	RetryMaxBackoff     time.Duration
+
+	WorkerPoolSize     int
+	WorkerDrainTimeout time.Duration
+
+	OutboxPollInterval time.Duration
+	OutboxBatchSize    int
+
+	// SchedulerLeaseTTL é quanto vale a liderança do scheduler sem renovação; maior que o intervalo
+	SchedulerPollInterval time.Duration
+	SchedulerLeaseTTL     time.Duration
+
+	// profundidade exigida antes de liquidar um saque, por rede
+	TronConfirmations        int
+	EthConfirmations         int
+	ConfirmationPollInterval time.Duration
+	ConfirmationDropTimeout  time.Duration
+
+	DepositXpub         string
+	DepositAddressFile  string
+	DepositScanStart    int
+	DepositReorgDepth   int
+	DepositScanInterval time.Duration
+
+	UsdtContract     string
+	Trc20FeeLimit    int64
+	Trc20EnergyLimit int64
+
+	EthRPCURL    string
+	UsdcContract string
+
+	// TronValidateOnline soma à validação offline da carteira uma consulta ao nó em TronURL
+	TronURL            string
+	TronValidateOnline bool
+
+	SignerSocket       string
+	KeystoreDir        string
+	KeystorePassphrase string
+	TronPrivateKey     string
 }
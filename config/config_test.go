@@ -3,8 +3,10 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/gabrielksneiva/go-financial-transactions/config"
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,3 +51,59 @@ func TestLoadConfig(t *testing.T) {
 	assert.Equal(t, 2, cfg.RedisDB)
 	assert.Equal(t, "localhost", cfg.DBHost)
 }
+
+func TestGetInt(t *testing.T) {
+	os.Setenv("TEST_INT", "7")
+	defer os.Unsetenv("TEST_INT")
+
+	assert.Equal(t, 7, config.GetInt("TEST_INT", 3))
+	assert.Equal(t, 3, config.GetInt("UNDEFINED_INT", 3))
+}
+
+func TestGetDuration(t *testing.T) {
+	os.Setenv("TEST_DURATION", "90s")
+	defer os.Unsetenv("TEST_DURATION")
+
+	assert.Equal(t, 90*time.Second, config.GetDuration("TEST_DURATION", time.Minute))
+	assert.Equal(t, time.Minute, config.GetDuration("UNDEFINED_DURATION", time.Minute))
+}
+
+func TestSetupSigner(t *testing.T) {
+	_, err := config.SetupSigner(config.Config{})
+	assert.ErrorContains(t, err, "nenhum signer configurado")
+
+	s, err := config.SetupSigner(config.Config{TronPrivateKey: "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"})
+	assert.NoError(t, err)
+	pub, err := s.PublicKey()
+	assert.NoError(t, err)
+	assert.NotNil(t, pub)
+}
+
+func TestParseMoneyList(t *testing.T) {
+	thresholds, err := config.ParseMoneyList(" TRX:50000, trx:1.5 ,")
+	assert.NoError(t, err)
+	assert.Len(t, thresholds, 1)
+	assert.Equal(t, int64(1_500000), thresholds["TRX"].Units)
+
+	empty, err := config.ParseMoneyList("")
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = config.ParseMoneyList("TRX=50000")
+	assert.Error(t, err)
+	_, err = config.ParseMoneyList("DOGE:1")
+	assert.Error(t, err)
+}
+
+func TestParseRateLimits(t *testing.T) {
+	limits, err := config.ParseRateLimits(" ip:300/1m, transaction:10/1m ,endpoint:5/10s")
+	assert.NoError(t, err)
+	assert.Len(t, limits, 3)
+	assert.Equal(t, domain.RateLimit{Limit: 300, Period: time.Minute}, limits["ip"])
+	assert.Equal(t, 2*time.Second, limits["endpoint"].Interval())
+
+	for _, invalid := range []string{"ip=300/1m", "ip:300", "ip:0/1m", "ip:300/forever", "session:5/1m"} {
+		_, err := config.ParseRateLimits(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -3,30 +3,40 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gabrielksneiva/go-financial-transactions/api"
+	"github.com/gabrielksneiva/go-financial-transactions/client"
+	"github.com/gabrielksneiva/go-financial-transactions/consumer"
 	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/fees"
+	"github.com/gabrielksneiva/go-financial-transactions/limits"
 	"github.com/gabrielksneiva/go-financial-transactions/producer"
 	"github.com/gabrielksneiva/go-financial-transactions/repositories"
 	"github.com/gabrielksneiva/go-financial-transactions/services"
 	s "github.com/gabrielksneiva/go-financial-transactions/services"
+	"github.com/gabrielksneiva/go-financial-transactions/signer"
+	"github.com/gabrielksneiva/go-financial-transactions/wallet"
 
 	"github.com/joho/godotenv"
 	"gorm.io/gorm"
 )
 
 type AppResources struct {
-	DB            *gorm.DB
-	KafkaWriter   *producer.KafkaWriter
-	API           *api.App
-	TransactionCh chan d.Transaction
-	CancelFunc    context.CancelFunc
-	Context       context.Context
-	Config        Config
+	DB               *gorm.DB
+	KafkaWriter      *producer.KafkaWriter
+	DeadLetterWriter *producer.DeadLetterWriter
+	API              *api.App
+	TransactionCh    chan d.Transaction
+	CancelFunc       context.CancelFunc
+	Context          context.Context
+	Config           Config
 }
 
 func LoadConfig() Config {
@@ -42,20 +52,69 @@ func LoadConfig() Config {
 	}
 
 	return Config{
-		APIPort:      GetEnv("API_PORT", "8080"),
-		FrontendPort: GetEnv("FRONTEND_PORT", "4000"),
-		KafkaBroker:  os.Getenv("KAFKA_BROKER"),
-		KafkaTopic:   os.Getenv("KAFKA_TOPIC"),
-		KafkaGroupID: os.Getenv("KAFKA_GROUP_ID"),
-		DBHost:       os.Getenv("DB_HOST"),
-		DBPort:       os.Getenv("DB_PORT"),
-		DBUser:       os.Getenv("DB_USER"),
-		DBPassword:   os.Getenv("DB_PASSWORD"),
-		DBName:       os.Getenv("DB_NAME"),
-		RedisHost:    os.Getenv("REDIS_HOST"),
-		RedisDB:      redisDB,
-		JwtSecret:    os.Getenv("JWT_SECRET"),
-		TronWallet:   os.Getenv("TRON_FROM_ADDR"),
+		APIPort:       GetEnv("API_PORT", "8080"),
+		FrontendPort:  GetEnv("FRONTEND_PORT", "4000"),
+		KafkaBroker:   os.Getenv("KAFKA_BROKER"),
+		KafkaTopic:    os.Getenv("KAFKA_TOPIC"),
+		KafkaGroupID:  os.Getenv("KAFKA_GROUP_ID"),
+		KafkaDLQTopic: os.Getenv("KAFKA_DLQ_TOPIC"),
+		DBHost:        os.Getenv("DB_HOST"),
+		DBPort:        os.Getenv("DB_PORT"),
+		DBUser:        os.Getenv("DB_USER"),
+		DBPassword:    os.Getenv("DB_PASSWORD"),
+		DBName:        os.Getenv("DB_NAME"),
+		RedisHost:     os.Getenv("REDIS_HOST"),
+		RedisDB:       redisDB,
+		JwtSecret:     os.Getenv("JWT_SECRET"),
+		TronWallet:    os.Getenv("TRON_FROM_ADDR"),
+
+		LedgerCheckInterval: GetDuration("LEDGER_CHECK_INTERVAL", time.Hour),
+		IdempotencyTTL:      GetDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+
+		WithdrawalAddressCooldown: GetDuration("WITHDRAWAL_ADDRESS_COOLDOWN", 24*time.Hour),
+		ApprovalThresholds:        GetEnv("WITHDRAWAL_APPROVAL_THRESHOLDS", ""),
+		FeeRulesFile:              GetEnv("FEE_RULES_FILE", ""),
+		RateLimits:                GetEnv("RATE_LIMITS", "transaction:10/1m"),
+		LimitsFile:                GetEnv("LIMITS_FILE", ""),
+
+		RetryMaxAttempts:    GetInt("RETRY_MAX_ATTEMPTS", 5),
+		RetryInitialBackoff: GetDuration("RETRY_INITIAL_BACKOFF", 500*time.Millisecond),
+		RetryMaxBackoff:     GetDuration("RETRY_MAX_BACKOFF", 30*time.Second),
+
+		WorkerPoolSize:     GetInt("WORKER_POOL_SIZE", 4),
+		WorkerDrainTimeout: GetDuration("WORKER_DRAIN_TIMEOUT", 30*time.Second),
+
+		OutboxPollInterval: GetDuration("OUTBOX_POLL_INTERVAL", time.Second),
+		OutboxBatchSize:    GetInt("OUTBOX_BATCH_SIZE", 100),
+
+		SchedulerPollInterval: GetDuration("SCHEDULER_POLL_INTERVAL", 30*time.Second),
+		SchedulerLeaseTTL:     GetDuration("SCHEDULER_LEASE_TTL", time.Minute),
+
+		TronConfirmations:        GetInt("TRON_CONFIRMATIONS", 19),
+		EthConfirmations:         GetInt("ETH_CONFIRMATIONS", 12),
+		ConfirmationPollInterval: GetDuration("CONFIRMATION_POLL_INTERVAL", 3*time.Second),
+		ConfirmationDropTimeout:  GetDuration("CONFIRMATION_DROP_TIMEOUT", 10*time.Minute),
+
+		DepositXpub:         GetEnv("DEPOSIT_XPUB", ""),
+		DepositAddressFile:  GetEnv("DEPOSIT_ADDRESS_FILE", ""),
+		DepositScanStart:    GetInt("DEPOSIT_SCAN_START", 0),
+		DepositReorgDepth:   GetInt("DEPOSIT_REORG_DEPTH", 20),
+		DepositScanInterval: GetDuration("DEPOSIT_SCAN_INTERVAL", 3*time.Second),
+
+		UsdtContract:     GetEnv("USDT_CONTRACT", ""),
+		Trc20FeeLimit:    int64(GetInt("TRC20_FEE_LIMIT", 30_000000)),
+		Trc20EnergyLimit: int64(GetInt("TRC20_ENERGY_LIMIT", 0)),
+
+		EthRPCURL:    GetEnv("ETH_RPC_URL", ""),
+		UsdcContract: GetEnv("USDC_CONTRACT", ""),
+
+		TronURL:            GetEnv("TRON_URL", ""),
+		TronValidateOnline: GetEnv("TRON_VALIDATE_ONLINE", "false") == "true",
+
+		SignerSocket:       GetEnv("SIGNER_SOCKET", ""),
+		KeystoreDir:        GetEnv("KEYSTORE_DIR", ""),
+		KeystorePassphrase: os.Getenv("KEYSTORE_PASSPHRASE"),
+		TronPrivateKey:     os.Getenv("TRON_PRIVATE_KEY"),
 	}
 }
 
@@ -66,6 +125,102 @@ func GetEnv(key, defaultVal string) string {
 	return defaultVal
 }
 
+func GetDuration(key string, defaultVal time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		log.Fatalf("❌ Erro ao converter %s para duração: %v", key, err)
+	}
+	return d
+}
+
+func GetInt(key string, defaultVal int) int {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		log.Fatalf("❌ Erro ao converter %s para inteiro: %v", key, err)
+	}
+	return i
+}
+
+// SetupSigner escolhe quem assina os envios: o processo signer pelo socket (a chave fica fora
+// deste processo), o keystore cifrado destrancado aqui ou, legado, a chave em TRON_PRIVATE_KEY
+func SetupSigner(cfg Config) (d.Signer, error) {
+	switch {
+	case cfg.SignerSocket != "":
+		return signer.Dial(cfg.SignerSocket)
+	case cfg.KeystoreDir != "":
+		return signer.OpenKeystore(cfg.KeystoreDir, cfg.KeystorePassphrase)
+	case cfg.TronPrivateKey != "":
+		log.Println("⚠️ TRON_PRIVATE_KEY mantém a chave em texto puro; prefira KEYSTORE_DIR ou SIGNER_SOCKET")
+		return signer.FromHex(cfg.TronPrivateKey)
+	}
+	return nil, errors.New("nenhum signer configurado: defina SIGNER_SOCKET, KEYSTORE_DIR ou TRON_PRIVATE_KEY")
+}
+
+// ParseMoneyList lê pares MOEDA:valor separados por vírgula ("TRX:50000,USDT:10000"); as moedas
+// precisam estar registradas para que o valor seja convertido nas casas decimais delas
+func ParseMoneyList(value string) (map[string]d.Money, error) {
+	out := make(map[string]d.Money)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		currency, amount, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("esperado MOEDA:valor, recebido %q", item)
+		}
+		money, err := d.ParseMoney(amount, currency)
+		if err != nil {
+			return nil, err
+		}
+		out[money.Currency] = money
+	}
+	return out, nil
+}
+
+// ParseRateLimits lê pares classe:limite/período separados por vírgula ("ip:300/1m,transaction:10/1m");
+// classes fora da lista ficam sem limite
+func ParseRateLimits(value string) (map[string]d.RateLimit, error) {
+	out := make(map[string]d.RateLimit)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		class, rate, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("esperado classe:limite/período, recebido %q", item)
+		}
+		switch class {
+		case d.RateLimitIP, d.RateLimitUser, d.RateLimitEndpoint, d.RateLimitTransaction:
+		default:
+			return nil, fmt.Errorf("classe de rate limit desconhecida %q", class)
+		}
+		limit, period, ok := strings.Cut(rate, "/")
+		if !ok {
+			return nil, fmt.Errorf("esperado classe:limite/período, recebido %q", item)
+		}
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("limite inválido em %q", item)
+		}
+		p, err := time.ParseDuration(period)
+		if err != nil || p <= 0 {
+			return nil, fmt.Errorf("período inválido em %q", item)
+		}
+		out[class] = d.RateLimit{Limit: n, Period: p}
+	}
+	return out, nil
+}
+
 func SetupApplication() *AppResources {
 	fmt.Println("🚀 Initializing dependencies...")
 
@@ -77,28 +232,138 @@ func SetupApplication() *AppResources {
 		panic("❌ Failed to connect to database")
 	}
 
-	redisClient := repositories.InitRedis(cfg.RedisHost, cfg.RedisDB)
-	rateLimiter := repositories.NewRedisRateLimiter(redisClient)
+	// O USDT só é aceito em saques e reconhecido pelo scanner com o contrato configurado
+	if cfg.UsdtContract != "" {
+		d.RegisterAsset(d.Asset{
+			Symbol:      "USDT",
+			Decimals:    6,
+			Contract:    cfg.UsdtContract,
+			FeeLimit:    cfg.Trc20FeeLimit,
+			EnergyLimit: cfg.Trc20EnergyLimit,
+		})
+	}
+
+	// ETH e ERC-20 só existem com um nó Ethereum configurado. O saldo em ETH guarda gwei
+	// (9 casas): com 18 casas um int64 não passaria de 9,2 ETH
+	if cfg.EthRPCURL != "" {
+		d.RegisterAsset(d.Asset{Symbol: "ETH", Decimals: 9, OnchainDecimals: 18, Chain: d.ChainEthereum})
+		if cfg.UsdcContract != "" {
+			d.RegisterAsset(d.Asset{Symbol: "USDC", Decimals: 6, Chain: d.ChainEthereum, Contract: cfg.UsdcContract})
+		}
+	}
+
+	rateLimits, err := ParseRateLimits(cfg.RateLimits)
+	if err != nil {
+		log.Fatalf("❌ Erro ao ler RATE_LIMITS: %v", err)
+	}
+	// Sem Redis as cotas ficam na memória: só vale com uma única instância da API
+	var rateLimiter d.RateLimiter
+	if cfg.RedisHost != "" {
+		rateLimiter = repositories.NewRedisRateLimiter(repositories.InitRedis(cfg.RedisHost, cfg.RedisDB), rateLimits)
+	} else {
+		log.Println("⚠️ REDIS_HOST vazio: rate limit em memória, por instância")
+		rateLimiter = repositories.NewMemoryRateLimiter(rateLimits)
+	}
 
 	kafkaWriter := producer.NewKafkaWriter(cfg.KafkaBroker, cfg.KafkaTopic)
 
 	repo := repositories.NewGormRepository(db)
-	deposit := s.NewDepositService(repo, repo, kafkaWriter, rateLimiter)
-	withdraw := s.NewWithdrawService(repo, repo, kafkaWriter, rateLimiter)
+	deposit := s.NewDepositService(repo, repo, repo, rateLimiter)
+	withdraw := s.NewWithdrawService(repo, repo, repo, rateLimiter)
 	statement := s.NewStatementService(repo, repo)
+	transfers := s.NewTransferService(repo, repo, repo, rateLimiter)
 	userService := services.NewUserService(repo)
+	if cfg.TronValidateOnline && cfg.TronURL != "" {
+		userService.Addresses = client.NewNodeAddressValidator(cfg.TronURL)
+	}
+
+	// Sem tópico de dead-letter configurado, mensagens com falha travam a partição em vez de serem descartadas
+	var deadLetterWriter *producer.DeadLetterWriter
+	var deadLetters *services.DeadLetterService
+	if cfg.KafkaDLQTopic != "" {
+		deadLetterWriter = producer.NewDeadLetterWriter(cfg.KafkaBroker, cfg.KafkaDLQTopic)
+		deadLetters = s.NewDeadLetterService(consumer.NewKafkaDeadLetterQueue(cfg.KafkaBroker, cfg.KafkaDLQTopic), kafkaWriter)
+	}
+
+	// A xpub da conta deriva os endereços sem chave privada; a lista de endereços fica como
+	// alternativa. Sem nenhuma das duas o depósito on-chain fica desligado e só /api/deposit credita saldo
+	var depositAddresses *services.DepositAddressService
+	switch {
+	case cfg.DepositXpub != "":
+		deriver, err := wallet.NewDeriver(cfg.DepositXpub)
+		if err != nil {
+			log.Fatalf("❌ Erro ao carregar DEPOSIT_XPUB: %v", err)
+		}
+		depositAddresses = s.NewDepositAddressService(repo, deriver)
+	case cfg.DepositAddressFile != "":
+		addresses, err := client.LoadAddressList(cfg.DepositAddressFile)
+		if err != nil {
+			log.Fatalf("❌ Erro ao carregar endereços de depósito: %v", err)
+		}
+		depositAddresses = s.NewDepositAddressService(repo, addresses)
+	}
+
+	// Destinos de saque são validados offline pela rede do ativo; Ethereum só com o driver ligado
+	addressValidators := map[string]d.AddressValidator{d.ChainTron: client.TronAddressValidator{}}
+	if cfg.EthRPCURL != "" {
+		addressValidators[d.ChainEthereum] = client.EVMAddressValidator{}
+	}
+	withdrawalAddresses := s.NewWithdrawalAddressService(repo, repo, addressValidators, cfg.WithdrawalAddressCooldown)
+	withdraw.Destinations = repo
+
+	// Saques a partir do limite da moeda esperam um admin; sem limites o fluxo de aprovação fica desligado
+	approvalThresholds, err := ParseMoneyList(cfg.ApprovalThresholds)
+	if err != nil {
+		log.Fatalf("❌ Erro ao ler WITHDRAWAL_APPROVAL_THRESHOLDS: %v", err)
+	}
+	withdraw.Approvals = repo
+	withdraw.ApprovalThresholds = approvalThresholds
+	approvals := s.NewApprovalService(repo, repo)
+
+	// As tarifas dependem dos ativos registrados acima: valores fixos são lidos na moeda da regra
+	if cfg.FeeRulesFile != "" {
+		rules, err := fees.LoadRules(cfg.FeeRulesFile)
+		if err != nil {
+			log.Fatalf("❌ Erro ao ler FEE_RULES_FILE: %v", err)
+		}
+		engine, err := fees.NewEngine(rules, repo)
+		if err != nil {
+			log.Fatalf("❌ Erro nas regras de tarifa: %v", err)
+		}
+		withdraw.Fees = engine
+		transfers.Fees = engine
+	}
+
+	// Os tetos também dependem dos ativos registrados; sem arquivo não há tetos de valor
+	var limitService *services.LimitService
+	if cfg.LimitsFile != "" {
+		rules, err := limits.LoadRules(cfg.LimitsFile)
+		if err != nil {
+			log.Fatalf("❌ Erro ao ler LIMITS_FILE: %v", err)
+		}
+		policy, err := limits.NewPolicy(rules, repo, repo)
+		if err != nil {
+			log.Fatalf("❌ Erro nas regras de limite: %v", err)
+		}
+		limitService = s.NewLimitService(policy, repo)
+		deposit.Limits = limitService
+		withdraw.Limits = limitService
+	}
+
+	schedules := s.NewScheduleService(repo, withdraw, transfers)
 
-	apiApp := api.NewApp(deposit, withdraw, statement, userService)
+	apiApp := api.NewApp(deposit, withdraw, statement, userService, deadLetters, depositAddresses, withdrawalAddresses, approvals, transfers, schedules, limitService, rateLimiter, repo, cfg.IdempotencyTTL)
 
 	transactions := make(chan d.Transaction, 100)
 
 	return &AppResources{
-		DB:            db,
-		KafkaWriter:   kafkaWriter,
-		API:           apiApp,
-		TransactionCh: transactions,
-		Context:       ctx,
-		CancelFunc:    cancel,
-		Config:        cfg,
+		DB:               db,
+		KafkaWriter:      kafkaWriter,
+		DeadLetterWriter: deadLetterWriter,
+		API:              apiApp,
+		TransactionCh:    transactions,
+		Context:          ctx,
+		CancelFunc:       cancel,
+		Config:           cfg,
 	}
 }
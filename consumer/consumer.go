@@ -5,48 +5,153 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"time"
 
 	d "github.com/gabrielksneiva/go-financial-transactions/domain"
 
 	"github.com/segmentio/kafka-go"
 )
 
+// Espera entre leituras com erro, dobrando a cada falha seguida
+const (
+	initialFetchBackoff = 100 * time.Millisecond
+	maxFetchBackoff     = 10 * time.Second
+)
+
 // consumer/consumer.go
-func InitConsumerWithReader(ctx context.Context, ch chan<- d.Transaction, reader KafkaReader) {
+// InitConsumerWithReader lê mensagens com FetchMessage, entrega aos workers e só
+// confirma o offset quando o ack correspondente chega (processamento at-least-once).
+// As novas tentativas ficam com o worker; um ack com falha significa que elas se esgotaram,
+// então a mensagem vai para o dead-letter e o offset avança. Sem dead-letter, a partição fica travada e a
+// leitura para quando MaxInFlight mensagens aguardam confirmação.
+func InitConsumerWithReader(ctx context.Context, ch chan<- d.TransactionJob, acks <-chan d.JobAck, reader KafkaReader, dlq DeadLetterPublisher) {
 	defer reader.Close()
 
+	tracker := newOffsetTracker(MaxInFlight)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		commitLoop(ctx, reader, acks, tracker, dlq)
+	}()
+	defer func() { <-done }()
+
+	fetchBackoff := initialFetchBackoff
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("📥 Consumer encerrado (ctx.Done).")
 			return
 		default:
-			msg, err := reader.ReadMessage(ctx)
+			if !tracker.reserve(ctx) {
+				log.Println("📥 Consumer encerrado (ctx.Done).")
+				return
+			}
+
+			msg, err := reader.FetchMessage(ctx)
 			if err != nil {
+				tracker.release()
 				if errors.Is(err, context.Canceled) {
-					log.Println("📥 Consumer encerrado (ReadMessage context canceled).")
+					log.Println("📥 Consumer encerrado (FetchMessage context canceled).")
 					return
 				}
-				log.Printf("Erro ao ler mensagem: %v", err)
+				log.Printf("Erro ao ler mensagem: %v (nova tentativa em %s)", err, fetchBackoff)
+				select {
+				case <-time.After(fetchBackoff):
+				case <-ctx.Done():
+					log.Println("📥 Consumer encerrado (ctx.Done).")
+					return
+				}
+				fetchBackoff = min(2*fetchBackoff, maxFetchBackoff)
 				continue
 			}
-			var tx d.Transaction
-			if err := json.Unmarshal(msg.Value, &tx); err != nil {
-				log.Printf("Erro ao deserializar JSON: %v", err)
+			fetchBackoff = initialFetchBackoff
+
+			tracker.track(msg)
+
+			job, err := jobFor(msg)
+			if err != nil {
+				// JSON inválido nunca vai ser processado: vai direto para o dead-letter
+				log.Printf("Erro ao deserializar JSON (offset %d): %v", msg.Offset, err)
+				commit(ctx, reader, deadLetter(ctx, dlq, tracker, msg, err, 1))
 				continue
 			}
-			ch <- tx
+
+			select {
+			case ch <- job:
+			case <-ctx.Done():
+				log.Println("📥 Consumer encerrado (ctx.Done).")
+				return
+			}
 		}
 	}
-
 }
 
 // consumer/consumer.go
-func InitConsumer(ctx context.Context, ch chan<- d.Transaction, kafkaBroker, kafkaTopic, kafkaGroupID string) {
+func InitConsumer(ctx context.Context, ch chan<- d.TransactionJob, acks <-chan d.JobAck, kafkaBroker, kafkaTopic, kafkaGroupID string, dlq DeadLetterPublisher) {
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers: []string{kafkaBroker},
 		Topic:   kafkaTopic,
 		GroupID: kafkaGroupID,
 	})
-	InitConsumerWithReader(ctx, ch, reader)
+	InitConsumerWithReader(ctx, ch, acks, reader, dlq)
+}
+
+func jobFor(msg kafka.Message) (d.TransactionJob, error) {
+	var tx d.Transaction
+	if err := json.Unmarshal(msg.Value, &tx); err != nil {
+		return d.TransactionJob{}, err
+	}
+	return d.TransactionJob{Transaction: tx, Partition: msg.Partition, Offset: msg.Offset}, nil
+}
+
+func commitLoop(ctx context.Context, reader KafkaReader, acks <-chan d.JobAck, tracker *offsetTracker, dlq DeadLetterPublisher) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ack := <-acks:
+			if ack.Err == nil {
+				commit(ctx, reader, tracker.ack(ack.Partition, ack.Offset, nil))
+				continue
+			}
+
+			msg, ok := tracker.message(ack.Partition, ack.Offset)
+			if !ok {
+				continue
+			}
+			commit(ctx, reader, deadLetter(ctx, dlq, tracker, msg, ack.Err, max(ack.Attempts, 1)))
+		}
+	}
+}
+
+type DeadLetterPublisher interface {
+	PublishDeadLetter(ctx context.Context, msg kafka.Message, cause error, attempts int) error
+}
+
+// deadLetter publica a mensagem no dead-letter e libera o offset; se não houver
+// dead-letter ou a publicação falhar, a mensagem fica marcada como falha e trava a partição.
+func deadLetter(ctx context.Context, dlq DeadLetterPublisher, tracker *offsetTracker, msg kafka.Message, cause error, attempts int) *kafka.Message {
+	if dlq == nil {
+		log.Printf("⚠️ Consumer: transação na partição %d offset %d não foi persistida, offset não será confirmado: %v",
+			msg.Partition, msg.Offset, cause)
+		return tracker.ack(msg.Partition, msg.Offset, cause)
+	}
+
+	if err := dlq.PublishDeadLetter(ctx, msg, cause, attempts); err != nil {
+		log.Printf("❌ Consumer: erro ao enviar offset %d da partição %d para o dead-letter: %v", msg.Offset, msg.Partition, err)
+		return tracker.ack(msg.Partition, msg.Offset, err)
+	}
+
+	log.Printf("☠️ Consumer: offset %d da partição %d enviado ao dead-letter após %d tentativa(s): %v",
+		msg.Offset, msg.Partition, attempts, cause)
+	return tracker.ack(msg.Partition, msg.Offset, nil)
+}
+
+func commit(ctx context.Context, reader KafkaReader, msg *kafka.Message) {
+	if msg == nil {
+		return
+	}
+	if err := reader.CommitMessages(ctx, *msg); err != nil {
+		log.Printf("❌ Consumer: erro ao confirmar offset %d da partição %d: %v", msg.Offset, msg.Partition, err)
+	}
 }
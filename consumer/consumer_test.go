@@ -17,16 +17,27 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+func messageFor(t *testing.T, id string, offset int64) kafka.Message {
+	data, err := json.Marshal(domain.Transaction{ID: id, UserID: 1, Amount: domain.NewMoney(1, "TRX"), Type: "deposit"})
+	assert.NoError(t, err)
+	return kafka.Message{Value: data, Offset: offset}
+}
+
+func offsetIs(offset int64) interface{} {
+	return mock.MatchedBy(func(msg kafka.Message) bool { return msg.Offset == offset })
+}
+
 func TestInitConsumerWithReader_Success(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ch := make(chan domain.Transaction, 1)
+	ch := make(chan domain.TransactionJob, 1)
+	acks := make(chan domain.JobAck, 1)
 
 	tx := domain.Transaction{
 		ID:     "tx-123",
 		UserID: uint(1),
-		Amount: 200.0,
+		Amount: domain.NewMoney(200_000000, "TRX"),
 		Type:   "deposit",
 	}
 
@@ -36,45 +47,143 @@ func TestInitConsumerWithReader_Success(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	readerMock.On("ReadMessage", mock.Anything).Once().Return(kafka.Message{Value: data}, nil)
-	readerMock.On("ReadMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	committed := make(chan struct{})
+	readerMock.On("FetchMessage", mock.Anything).Once().Return(kafka.Message{Value: data, Offset: 7}, nil)
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled).Maybe()
+	readerMock.On("CommitMessages", mock.Anything, offsetIs(7)).Once().Return(nil).
+		Run(func(args mock.Arguments) { close(committed) })
 	readerMock.On("Close").Return(nil)
 
 	go func() {
 		defer wg.Done()
-		consumer.InitConsumerWithReader(ctx, ch, readerMock)
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, nil)
 	}()
 
 	select {
 	case received := <-ch:
-		assert.Equal(t, tx.ID, received.ID)
-		assert.Equal(t, tx.UserID, received.UserID)
-		assert.Equal(t, tx.Amount, received.Amount)
-		cancel()
+		assert.Equal(t, tx.ID, received.Transaction.ID)
+		assert.Equal(t, tx.UserID, received.Transaction.UserID)
+		assert.Equal(t, tx.Amount, received.Transaction.Amount)
+		assert.Equal(t, int64(7), received.Offset)
+
+		// o offset só é confirmado depois do ack do worker
+		readerMock.AssertNotCalled(t, "CommitMessages", mock.Anything, mock.Anything)
+		acks <- domain.JobAck{Partition: received.Partition, Offset: received.Offset}
 	case <-time.After(time.Second):
 		t.Fatal("timeout esperando transação")
 	}
 
+	select {
+	case <-committed:
+	case <-time.After(time.Second):
+		t.Fatal("timeout esperando commit")
+	}
+
+	cancel()
 	wg.Wait()
 	readerMock.AssertExpectations(t)
 }
 
+func TestInitConsumerWithReader_FailedAckIsNotCommitted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan domain.TransactionJob, 2)
+	acks := make(chan domain.JobAck, 2)
+	readerMock := new(mocks.KafkaReader)
+
+	readerMock.On("FetchMessage", mock.Anything).Once().Return(messageFor(t, "tx-1", 1), nil)
+	readerMock.On("FetchMessage", mock.Anything).Once().Return(messageFor(t, "tx-2", 2), nil)
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	readerMock.On("Close").Return(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, nil)
+	}()
+
+	first, second := <-ch, <-ch
+	acks <- domain.JobAck{Offset: first.Offset, Err: errors.New("db down")}
+	acks <- domain.JobAck{Offset: second.Offset}
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	// offset 2 foi concluído, mas o 1 falhou: nada pode ser confirmado
+	readerMock.AssertNotCalled(t, "CommitMessages", mock.Anything, mock.Anything)
+}
+
+func TestInitConsumerWithReader_OutOfOrderAcks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan domain.TransactionJob, 3)
+	acks := make(chan domain.JobAck)
+	readerMock := new(mocks.KafkaReader)
+
+	var mu sync.Mutex
+	var commits []int64
+	for i := int64(1); i <= 3; i++ {
+		readerMock.On("FetchMessage", mock.Anything).Once().Return(messageFor(t, "tx", i), nil)
+	}
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	readerMock.On("CommitMessages", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) {
+			mu.Lock()
+			defer mu.Unlock()
+			commits = append(commits, args.Get(1).(kafka.Message).Offset)
+		})
+	readerMock.On("Close").Return(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, nil)
+	}()
+
+	for i := 0; i < 3; i++ {
+		<-ch
+	}
+
+	acks <- domain.JobAck{Offset: 2}
+	acks <- domain.JobAck{Offset: 1}
+	acks <- domain.JobAck{Offset: 3}
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int64{2, 3}, commits)
+}
+
 func TestInitConsumerWithReader_InvalidJSON(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ch := make(chan domain.Transaction, 1)
+	ch := make(chan domain.TransactionJob, 1)
+	acks := make(chan domain.JobAck)
 	readerMock := new(mocks.KafkaReader)
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	readerMock.On("ReadMessage", mock.Anything).Once().Return(kafka.Message{Value: []byte("invalid-json")}, nil)
-	readerMock.On("ReadMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	dlqMock := new(mocks.DeadLetterPublisher)
+
+	readerMock.On("FetchMessage", mock.Anything).Once().Return(kafka.Message{Value: []byte("invalid-json"), Offset: 3}, nil)
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	readerMock.On("CommitMessages", mock.Anything, offsetIs(3)).Return(nil)
 	readerMock.On("Close").Return(nil)
+	// JSON inválido não é reprocessado: vai direto para o dead-letter na primeira tentativa
+	dlqMock.On("PublishDeadLetter", mock.Anything, offsetIs(3), mock.Anything, 1).Return(nil)
 
 	go func() {
 		defer wg.Done()
-		consumer.InitConsumerWithReader(ctx, ch, readerMock)
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, dlqMock)
 	}()
 
 	time.Sleep(200 * time.Millisecond)
@@ -89,29 +198,173 @@ func TestInitConsumerWithReader_InvalidJSON(t *testing.T) {
 	}
 
 	readerMock.AssertExpectations(t)
+	dlqMock.AssertExpectations(t)
 }
 
 func TestInitConsumerWithReader_ReadError(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ch := make(chan domain.Transaction, 1)
+	ch := make(chan domain.TransactionJob, 1)
+	acks := make(chan domain.JobAck)
 	readerMock := new(mocks.KafkaReader)
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	readerMock.On("ReadMessage", mock.Anything).Once().Return(kafka.Message{}, errors.New("read error"))
-	readerMock.On("ReadMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	var mu sync.Mutex
+	var calls []time.Time
+	record := func(args mock.Arguments) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, time.Now())
+	}
+	readerMock.On("FetchMessage", mock.Anything).Twice().Return(kafka.Message{}, errors.New("read error")).Run(record)
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled).Run(record)
 	readerMock.On("Close").Return(nil)
 
 	go func() {
 		defer wg.Done()
-		consumer.InitConsumerWithReader(ctx, ch, readerMock)
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, nil)
 	}()
 
-	time.Sleep(200 * time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
 	cancel()
 	wg.Wait()
 
 	readerMock.AssertExpectations(t)
+
+	// cada erro seguido dobra a espera antes da próxima leitura
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, calls, 3) {
+		assert.GreaterOrEqual(t, calls[1].Sub(calls[0]), 100*time.Millisecond)
+		assert.GreaterOrEqual(t, calls[2].Sub(calls[1]), 200*time.Millisecond)
+	}
+}
+
+func TestInitConsumerWithReader_StuckPartitionPausesFetching(t *testing.T) {
+	defer func(limit int) { consumer.MaxInFlight = limit }(consumer.MaxInFlight)
+	consumer.MaxInFlight = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan domain.TransactionJob, 10)
+	acks := make(chan domain.JobAck, 10)
+	readerMock := new(mocks.KafkaReader)
+
+	var offset int64
+	var mu sync.Mutex
+	readerMock.On("FetchMessage", mock.Anything).Return(func(ctx context.Context) (kafka.Message, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		offset++
+		return messageFor(t, "tx", offset), nil
+	})
+	readerMock.On("Close").Return(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, nil)
+	}()
+
+	// sem dead-letter, a falha do offset 1 trava o commit da partição
+	first, second := <-ch, <-ch
+	acks <- domain.JobAck{Offset: first.Offset, Err: errors.New("db down")}
+	acks <- domain.JobAck{Offset: second.Offset}
+
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	readerMock.AssertNumberOfCalls(t, "FetchMessage", 2)
+	readerMock.AssertNotCalled(t, "CommitMessages", mock.Anything, mock.Anything)
+}
+
+func TestInitConsumerWithReader_FailedAckGoesToDeadLetter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan domain.TransactionJob, 1)
+	acks := make(chan domain.JobAck, 1)
+	readerMock := new(mocks.KafkaReader)
+	dlqMock := new(mocks.DeadLetterPublisher)
+
+	committed := make(chan struct{})
+	readerMock.On("FetchMessage", mock.Anything).Once().Return(messageFor(t, "tx-poison", 8), nil)
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	readerMock.On("CommitMessages", mock.Anything, offsetIs(8)).Once().Return(nil).
+		Run(func(args mock.Arguments) { close(committed) })
+	readerMock.On("Close").Return(nil)
+	dlqMock.On("PublishDeadLetter", mock.Anything, offsetIs(8), mock.MatchedBy(func(err error) bool {
+		return err.Error() == "db down"
+	}), 2).Once().Return(nil)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, dlqMock)
+	}()
+
+	// o worker já fez as novas tentativas: o ack com falha vai direto para o dead-letter
+	job := <-ch
+	acks <- domain.JobAck{Offset: job.Offset, Err: errors.New("db down"), Attempts: 2}
+
+	// depois do dead-letter o offset avança
+	select {
+	case <-committed:
+	case <-time.After(time.Second):
+		t.Fatal("timeout esperando commit")
+	}
+
+	// e a mensagem não é reentregue fora da fila do usuário
+	select {
+	case job := <-ch:
+		t.Fatalf("mensagem reentregue: %s", job.Transaction.ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	wg.Wait()
+	dlqMock.AssertExpectations(t)
+}
+
+func TestInitConsumerWithReader_DeadLetterFailureStallsPartition(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan domain.TransactionJob, 1)
+	acks := make(chan domain.JobAck, 1)
+	readerMock := new(mocks.KafkaReader)
+	dlqMock := new(mocks.DeadLetterPublisher)
+
+	published := make(chan struct{})
+	readerMock.On("FetchMessage", mock.Anything).Once().Return(messageFor(t, "tx-stuck", 4), nil)
+	readerMock.On("FetchMessage", mock.Anything).Return(kafka.Message{}, context.Canceled)
+	readerMock.On("Close").Return(nil)
+	dlqMock.On("PublishDeadLetter", mock.Anything, mock.Anything, mock.Anything, 1).Return(errors.New("broker down")).
+		Run(func(args mock.Arguments) { close(published) })
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		consumer.InitConsumerWithReader(ctx, ch, acks, readerMock, dlqMock)
+	}()
+
+	job := <-ch
+	acks <- domain.JobAck{Offset: job.Offset, Err: errors.New("db down")}
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("timeout esperando dead-letter")
+	}
+
+	cancel()
+	wg.Wait()
+	readerMock.AssertNotCalled(t, "CommitMessages", mock.Anything, mock.Anything)
 }
@@ -0,0 +1,164 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const deadLetterReadTimeout = 10 * time.Second
+
+// KafkaDeadLetterQueue lê o tópico de dead-letter diretamente das partições, sem
+// consumer group, para que a listagem não mova offsets de ninguém.
+type KafkaDeadLetterQueue struct {
+	broker string
+	topic  string
+}
+
+func NewKafkaDeadLetterQueue(broker, topic string) *KafkaDeadLetterQueue {
+	return &KafkaDeadLetterQueue{broker: broker, topic: topic}
+}
+
+// ListDeadLetters retorna até limit mensagens mais recentes de cada partição
+func (q *KafkaDeadLetterQueue) ListDeadLetters(ctx context.Context, limit int) ([]d.DeadLetter, error) {
+	conn, err := kafka.DialContext(ctx, "tcp", q.broker)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	partitions, err := conn.ReadPartitions(q.topic)
+	if err != nil {
+		return nil, err
+	}
+
+	letters := []d.DeadLetter{}
+	for _, p := range partitions {
+		msgs, err := q.readPartition(ctx, p.ID, limit)
+		if err != nil {
+			return nil, fmt.Errorf("partition %d: %w", p.ID, err)
+		}
+		for _, msg := range msgs {
+			letters = append(letters, ParseDeadLetter(msg))
+		}
+	}
+
+	return letters, nil
+}
+
+func (q *KafkaDeadLetterQueue) GetDeadLetter(ctx context.Context, partition int, offset int64) (*d.DeadLetter, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", q.broker, q.topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return nil, err
+	}
+	if offset < first || offset >= last {
+		return nil, d.ErrDeadLetterNotFound
+	}
+
+	msgs, err := readRange(conn, offset, offset+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 || msgs[0].Offset != offset {
+		return nil, d.ErrDeadLetterNotFound
+	}
+
+	letter := ParseDeadLetter(msgs[0])
+	return &letter, nil
+}
+
+func (q *KafkaDeadLetterQueue) readPartition(ctx context.Context, partition, limit int) ([]kafka.Message, error) {
+	conn, err := kafka.DialLeader(ctx, "tcp", q.broker, q.topic, partition)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	first, last, err := conn.ReadOffsets()
+	if err != nil {
+		return nil, err
+	}
+
+	start := last - int64(limit)
+	if start < first {
+		start = first
+	}
+	return readRange(conn, start, last)
+}
+
+// readRange lê as mensagens no intervalo [from, to) de uma conexão com o líder da partição
+func readRange(conn *kafka.Conn, from, to int64) ([]kafka.Message, error) {
+	if from >= to {
+		return nil, nil
+	}
+
+	if _, err := conn.Seek(from, kafka.SeekAbsolute); err != nil {
+		return nil, err
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(deadLetterReadTimeout)); err != nil {
+		return nil, err
+	}
+
+	// um batch termina no fim do que o broker devolveu num fetch, que pode ser antes de to;
+	// fechar o batch avança a conexão e o próximo continua de onde ele parou
+	var msgs []kafka.Message
+	for {
+		batch := conn.ReadBatch(1, 10e6)
+		for {
+			msg, err := batch.ReadMessage()
+			if err != nil {
+				break
+			}
+			msgs = append(msgs, msg)
+			if msg.Offset >= to-1 {
+				batch.Close()
+				return msgs, nil
+			}
+		}
+		// Close devolve o erro de leitura do batch, exceto o io.EOF do fim dele
+		if err := batch.Close(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ParseDeadLetter converte a mensagem do tópico de dead-letter, lendo os headers gravados pelo DeadLetterWriter
+func ParseDeadLetter(msg kafka.Message) d.DeadLetter {
+	letter := d.DeadLetter{
+		Partition: msg.Partition,
+		Offset:    msg.Offset,
+		Key:       string(msg.Key),
+		Value:     string(msg.Value),
+	}
+
+	for _, h := range msg.Headers {
+		value := string(h.Value)
+		switch h.Key {
+		case d.HeaderDLQError:
+			letter.Error = value
+		case d.HeaderDLQAttempts:
+			letter.Attempts, _ = strconv.Atoi(value)
+		case d.HeaderDLQOriginalTopic:
+			letter.OriginalTopic = value
+		case d.HeaderDLQOriginalPartition:
+			letter.OriginalPartition, _ = strconv.Atoi(value)
+		case d.HeaderDLQOriginalOffset:
+			letter.OriginalOffset, _ = strconv.ParseInt(value, 10, 64)
+		case d.HeaderDLQFailedAt:
+			letter.FailedAt, _ = time.Parse(time.RFC3339, value)
+		}
+	}
+
+	return letter
+}
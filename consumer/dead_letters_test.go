@@ -0,0 +1,51 @@
+package consumer_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/consumer"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/producer"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestParseDeadLetter_RoundTrip(t *testing.T) {
+	writerMock := new(mocks.WriterInterface)
+	var written kafka.Message
+	writerMock.On("WriteMessages", mock.Anything, mock.Anything).Return(nil).
+		Run(func(args mock.Arguments) { written = args.Get(1).(kafka.Message) })
+
+	original := kafka.Message{
+		Topic:     "transacoes",
+		Partition: 2,
+		Offset:    42,
+		Key:       []byte("key-1"),
+		Value:     []byte(`{"id":"tx-1"}`),
+	}
+
+	dlq := producer.NewDeadLetterWriterWithMock(writerMock)
+	err := dlq.PublishDeadLetter(context.Background(), original, errors.New("db down"), 5)
+	assert.NoError(t, err)
+
+	// o tópico de dead-letter atribui novos partição/offset
+	written.Partition = 0
+	written.Offset = 7
+
+	letter := consumer.ParseDeadLetter(written)
+	assert.Equal(t, 0, letter.Partition)
+	assert.Equal(t, int64(7), letter.Offset)
+	assert.Equal(t, "key-1", letter.Key)
+	assert.Equal(t, `{"id":"tx-1"}`, letter.Value)
+	assert.Equal(t, "db down", letter.Error)
+	assert.Equal(t, 5, letter.Attempts)
+	assert.Equal(t, "transacoes", letter.OriginalTopic)
+	assert.Equal(t, 2, letter.OriginalPartition)
+	assert.Equal(t, int64(42), letter.OriginalOffset)
+	assert.WithinDuration(t, time.Now(), letter.FailedAt, time.Minute)
+}
@@ -0,0 +1,104 @@
+package consumer
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MaxInFlight limita as mensagens lidas e ainda não confirmadas. Quando uma mensagem com
+// falha trava o commit da partição, a leitura para ao atingir o limite em vez de acumular
+// offsets em memória indefinidamente.
+var MaxInFlight = 1000
+
+type trackedMessage struct {
+	msg    kafka.Message
+	done   bool
+	failed bool
+}
+
+// offsetTracker guarda, por partição, as mensagens entregues aos workers em ordem de offset.
+// Como os acks podem chegar fora de ordem, só o maior prefixo contíguo concluído é confirmado.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int][]*trackedMessage
+	// uma vaga por mensagem rastreada, devolvida quando ela sai do prefixo confirmado
+	slots chan struct{}
+}
+
+func newOffsetTracker(limit int) *offsetTracker {
+	if limit < 1 {
+		limit = 1
+	}
+	return &offsetTracker{partitions: make(map[int][]*trackedMessage), slots: make(chan struct{}, limit)}
+}
+
+// reserve espera uma vaga para a próxima mensagem; retorna false se ctx terminar antes
+func (t *offsetTracker) reserve(ctx context.Context) bool {
+	select {
+	case t.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	log.Printf("⏸️ Consumer: %d mensagens aguardando confirmação, leitura pausada", cap(t.slots))
+	select {
+	case t.slots <- struct{}{}:
+		log.Println("▶️ Consumer: leitura retomada")
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release devolve a vaga de uma leitura que não resultou em mensagem rastreada
+func (t *offsetTracker) release() {
+	<-t.slots
+}
+
+func (t *offsetTracker) track(msg kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.partitions[msg.Partition] = append(t.partitions[msg.Partition], &trackedMessage{msg: msg})
+}
+
+// message retorna a mensagem original rastreada no offset
+func (t *offsetTracker) message(partition int, offset int64) (kafka.Message, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, m := range t.partitions[partition] {
+		if m.msg.Offset == offset {
+			return m.msg, true
+		}
+	}
+	return kafka.Message{}, false
+}
+
+// ack marca o offset como concluído e retorna a última mensagem que pode ser confirmada,
+// ou nil se nada avançou. Uma mensagem com falha trava a partição até o reprocessamento.
+func (t *offsetTracker) ack(partition int, offset int64, err error) *kafka.Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	queue := t.partitions[partition]
+	for _, m := range queue {
+		if m.msg.Offset == offset {
+			m.done = true
+			m.failed = err != nil
+			break
+		}
+	}
+
+	var last *kafka.Message
+	for len(queue) > 0 && queue[0].done && !queue[0].failed {
+		last = &queue[0].msg
+		queue = queue[1:]
+		<-t.slots
+	}
+	t.partitions[partition] = queue
+
+	return last
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/segmentio/kafka-go"
 )
 
+// KafkaReader usa FetchMessage + CommitMessages para que o offset só seja
+// confirmado depois que o worker persistir a transação
 type KafkaReader interface {
-	ReadMessage(context.Context) (kafka.Message, error)
+	FetchMessage(context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
 	Close() error
 }
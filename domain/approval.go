@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	ApprovalDecisionApproved = "approved"
+	ApprovalDecisionRejected = "rejected"
+)
+
+var (
+	ErrInsufficientFunds   = errors.New("insufficient funds")
+	ErrTransactionNotFound = errors.New("transaction not found")
+	// ErrSelfApproval impede que o admin decida sobre um saque pedido por ele mesmo
+	ErrSelfApproval = errors.New("approver must be different from the requester")
+)
+
+// ApprovalDecision é a trilha de auditoria das decisões sobre saques que exigem aprovação
+type ApprovalDecision struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	TransactionID string    `gorm:"index" json:"transaction_id"`
+	RequesterID   uint      `json:"requester_id"`
+	ApproverID    uint      `json:"approver_id"`
+	Decision      string    `json:"decision"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type ApprovalRepository interface {
+	// HoldForApproval grava o saque em AWAITING_APPROVAL retirando o valor do saldo do usuário;
+	// retorna ErrInsufficientFunds se o saldo não cobre o valor
+	HoldForApproval(tx Transaction) error
+	// ApproveWithdrawal marca o saque como APPROVED e o publica pelo outbox; o valor segue retido
+	// até o worker processá-lo
+	ApproveWithdrawal(txID string, approverID uint, reason string) (*Transaction, error)
+	// RejectWithdrawal marca o saque como REJECTED e devolve o valor retido ao usuário
+	RejectWithdrawal(txID string, approverID uint, reason string) (*Transaction, error)
+	ListApprovalDecisions(txID string) ([]ApprovalDecision, error)
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// Asset descreve como uma moeda é movimentada on-chain. Chain indica a rede (TRON quando
+// vazio); Contract vazio indica a moeda nativa da rede, as demais são tokens TRC-20/ERC-20
+// transferidos por chamada de contrato.
+type Asset struct {
+	Symbol   string
+	Decimals int
+	Chain    string
+	Contract string
+	// OnchainDecimals são as casas decimais do ativo na rede, quando diferentes das do saldo
+	// (o ETH tem 18 casas, mas o saldo em int64 guarda gwei); 0 indica as mesmas de Decimals
+	OnchainDecimals int
+	// FeeLimit é o máximo de SUN que uma chamada ao contrato pode queimar pagando energia
+	FeeLimit int64
+	// EnergyLimit recusa o envio quando a energia estimada passa deste valor; 0 desliga a checagem
+	EnergyLimit int64
+}
+
+func (a Asset) IsNative() bool {
+	return a.Contract == ""
+}
+
+// ScaleToChain converte unidades do saldo para a menor unidade da rede (gwei → wei no ETH)
+func (a Asset) ScaleToChain(units int64) *big.Int {
+	v := big.NewInt(units)
+	if a.OnchainDecimals > a.Decimals {
+		v.Mul(v, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.OnchainDecimals-a.Decimals)), nil))
+	}
+	return v
+}
+
+// ScaleFromChain faz o caminho inverso, truncando o que não cabe nas casas do saldo
+func (a Asset) ScaleFromChain(v *big.Int) (int64, error) {
+	out := new(big.Int).Set(v)
+	if a.OnchainDecimals > a.Decimals {
+		out.Quo(out, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.OnchainDecimals-a.Decimals)), nil))
+	}
+	if !out.IsInt64() {
+		return 0, fmt.Errorf("%w: %s %s", ErrInvalidAmount, v, a.Symbol)
+	}
+	return out.Int64(), nil
+}
+
+var ErrAssetNotSupported = errors.New("asset not supported on-chain")
+
+var (
+	assetsMu sync.RWMutex
+	assets   = map[string]Asset{
+		"TRX": {Symbol: "TRX", Decimals: 6, Chain: ChainTron},
+	}
+)
+
+// RegisterAsset habilita o envio e o recebimento on-chain da moeda; chamado na inicialização
+func RegisterAsset(a Asset) {
+	a.Symbol = strings.ToUpper(a.Symbol)
+	if a.Chain == "" {
+		a.Chain = ChainTron
+	}
+
+	assetsMu.Lock()
+	defer assetsMu.Unlock()
+	assets[a.Symbol] = a
+	currencyDecimals[a.Symbol] = a.Decimals
+}
+
+// LookupAsset devolve a configuração on-chain da moeda
+func LookupAsset(symbol string) (Asset, error) {
+	assetsMu.RLock()
+	defer assetsMu.RUnlock()
+
+	a, ok := assets[strings.ToUpper(symbol)]
+	if !ok {
+		return Asset{}, fmt.Errorf("%w: %s", ErrAssetNotSupported, symbol)
+	}
+	return a, nil
+}
+
+// AssetByContract encontra o token pelo endereço do contrato na rede
+func AssetByContract(chain, contract string) (Asset, bool) {
+	assetsMu.RLock()
+	defer assetsMu.RUnlock()
+
+	for _, a := range assets {
+		if !a.IsNative() && a.Chain == chain && a.Contract == contract {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	ChainTron     = "tron"
+	ChainEthereum = "ethereum"
+)
+
+var (
+	ErrChainNotSupported = errors.New("chain not supported")
+	ErrInvalidAddress    = errors.New("invalid address")
+)
+
+// AddressValidator confere um endereço de carteira; retorna ErrInvalidAddress quando ele é inválido
+type AddressValidator interface {
+	ValidateAddress(address string) error
+}
+
+// ChainRegistry liga cada rede ao seu driver; a moeda do saque define o ativo e o ativo,
+// a rede em que ele é enviado.
+type ChainRegistry struct {
+	mu      sync.RWMutex
+	drivers map[string]BlockchainClient
+}
+
+func NewChainRegistry() *ChainRegistry {
+	return &ChainRegistry{drivers: map[string]BlockchainClient{}}
+}
+
+func (r *ChainRegistry) Register(chain string, driver BlockchainClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drivers[chain] = driver
+}
+
+func (r *ChainRegistry) Driver(chain string) (BlockchainClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	driver, ok := r.drivers[chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrChainNotSupported, chain)
+	}
+	return driver, nil
+}
+
+// ForCurrency devolve o ativo e o driver da rede em que ele é movimentado
+func (r *ChainRegistry) ForCurrency(currency string) (Asset, BlockchainClient, error) {
+	asset, err := LookupAsset(currency)
+	if err != nil {
+		return Asset{}, nil, err
+	}
+	driver, err := r.Driver(asset.Chain)
+	if err != nil {
+		return Asset{}, nil, err
+	}
+	return asset, driver, nil
+}
+
+// Chains lista as redes registradas em ordem alfabética
+func (r *ChainRegistry) Chains() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	chains := make([]string, 0, len(r.drivers))
+	for chain := range r.drivers {
+		chains = append(chains, chain)
+	}
+	sort.Strings(chains)
+	return chains
+}
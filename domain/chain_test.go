@@ -0,0 +1,52 @@
+package domain_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainRegistry_ForCurrency(t *testing.T) {
+	domain.RegisterAsset(domain.Asset{Symbol: "ETH", Decimals: 9, OnchainDecimals: 18, Chain: domain.ChainEthereum})
+
+	tron := new(mocks.BlockchainClient)
+	chains := domain.NewChainRegistry()
+	chains.Register(domain.ChainTron, tron)
+
+	asset, driver, err := chains.ForCurrency("trx")
+	assert.NoError(t, err)
+	assert.Equal(t, "TRX", asset.Symbol)
+	assert.Same(t, tron, driver)
+
+	// ativo conhecido, mas sem driver para a rede
+	_, _, err = chains.ForCurrency("ETH")
+	assert.ErrorIs(t, err, domain.ErrChainNotSupported)
+
+	_, _, err = chains.ForCurrency("BRL")
+	assert.ErrorIs(t, err, domain.ErrAssetNotSupported)
+
+	chains.Register(domain.ChainEthereum, new(mocks.BlockchainClient))
+	assert.Equal(t, []string{domain.ChainEthereum, domain.ChainTron}, chains.Chains())
+}
+
+func TestAsset_Scale(t *testing.T) {
+	eth := domain.Asset{Symbol: "ETH", Decimals: 9, OnchainDecimals: 18}
+
+	// 1,5 ETH em gwei → wei
+	assert.Equal(t, "1500000000000000000", eth.ScaleToChain(1_500000000).String())
+
+	units, err := eth.ScaleFromChain(big.NewInt(1_500000000_123456789))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1_500000000), units)
+
+	huge := new(big.Int).Lsh(big.NewInt(1), 100)
+	_, err = eth.ScaleFromChain(huge)
+	assert.ErrorIs(t, err, domain.ErrInvalidAmount)
+
+	usdt := domain.Asset{Symbol: "USDT", Decimals: 6}
+	assert.Equal(t, "12500000", usdt.ScaleToChain(12_500000).String())
+}
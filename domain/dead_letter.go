@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Headers gravados nas mensagens enviadas ao tópico de dead-letter
+const (
+	HeaderDLQError             = "dlq-error"
+	HeaderDLQAttempts          = "dlq-attempts"
+	HeaderDLQOriginalTopic     = "dlq-original-topic"
+	HeaderDLQOriginalPartition = "dlq-original-partition"
+	HeaderDLQOriginalOffset    = "dlq-original-offset"
+	HeaderDLQFailedAt          = "dlq-failed-at"
+)
+
+// DeadLetter é uma mensagem que esgotou as tentativas e foi movida para o tópico de dead-letter
+type DeadLetter struct {
+	Partition         int       `json:"partition"`
+	Offset            int64     `json:"offset"`
+	Key               string    `json:"key"`
+	Value             string    `json:"value"`
+	Error             string    `json:"error"`
+	Attempts          int       `json:"attempts"`
+	OriginalTopic     string    `json:"original_topic"`
+	OriginalPartition int       `json:"original_partition"`
+	OriginalOffset    int64     `json:"original_offset"`
+	FailedAt          time.Time `json:"failed_at"`
+}
+
+type DeadLetterQueue interface {
+	ListDeadLetters(ctx context.Context, limit int) ([]DeadLetter, error)
+	GetDeadLetter(ctx context.Context, partition int, offset int64) (*DeadLetter, error)
+}
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
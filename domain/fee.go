@@ -0,0 +1,17 @@
+package domain
+
+// FeeTransaction é a linha da tarifa no extrato, ligada pela LinkedTransactionID ao saque ou
+// à transferência que a gerou
+const FeeTransaction = "fee"
+
+// FeeQuote é a cotação mostrada antes do envio: Total é o que sai do saldo do usuário
+type FeeQuote struct {
+	Amount Money
+	Fee    Money
+	Total  Money
+}
+
+// FeeSchedule calcula a tarifa de uma transação do tipo txType (withdraw, transfer_out) do usuário
+type FeeSchedule interface {
+	Quote(userID uint, txType string, amount Money) (Money, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	LedgerAccountUser   = "user"
+	LedgerAccountSystem = "system"
+	// LedgerAccountHold é a reserva de um usuário; espelha domain.Balance.Held
+	LedgerAccountHold = "hold"
+)
+
+// LedgerAccount é uma conta do razão. Contas de usuário espelham domain.Balance;
+// contas de sistema (hot wallet, clearing, fees) são a contrapartida dos lançamentos.
+type LedgerAccount struct {
+	ID        uint   `gorm:"primaryKey"`
+	Code      string `gorm:"uniqueIndex"` // ex.: "user:42:TRX", "hold:42:TRX", "system:hot_wallet:TRX"
+	Type      string
+	UserID    *uint `gorm:"index"`
+	Balance   Money `gorm:"embedded;embeddedPrefix:balance_"` // cache da soma das postings
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// JournalEntry agrupa postings cuja soma é sempre zero
+type JournalEntry struct {
+	ID            string `gorm:"type:text;primaryKey"`
+	TransactionID string `gorm:"index"`
+	Description   string
+	Postings      []Posting
+	CreatedAt     time.Time
+}
+
+// Posting é um movimento com sinal em uma conta: positivo credita, negativo debita
+type Posting struct {
+	ID             uint   `gorm:"primaryKey"`
+	JournalEntryID string `gorm:"type:text;index"`
+	AccountID      uint   `gorm:"index"`
+	Amount         Money  `gorm:"embedded"`
+	CreatedAt      time.Time
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrLimitExceeded = errors.New("transaction limit exceeded")
+
+// Limits são os tetos de valor de um usuário numa moeda, em unidades mínimas; zero é sem teto.
+// Os períodos são o dia e o mês correntes em UTC
+type Limits struct {
+	Currency          string
+	MaxWithdrawal     int64 // por saque
+	DailyWithdrawal   int64
+	MonthlyWithdrawal int64
+	DailyDeposit      int64
+}
+
+// LimitUsage é o volume já usado nos períodos correntes; saques que falharam ou foram
+// rejeitados não contam
+type LimitUsage struct {
+	DailyWithdrawal   int64
+	MonthlyWithdrawal int64
+	DailyDeposit      int64
+}
+
+// LimitOverride substitui, para um usuário, os tetos do papel e do nível de KYC; campos nulos herdam
+type LimitOverride struct {
+	UserID            uint   `gorm:"primaryKey;autoIncrement:false"`
+	Currency          string `gorm:"primaryKey;type:varchar(10)"`
+	MaxWithdrawal     *int64
+	DailyWithdrawal   *int64
+	MonthlyWithdrawal *int64
+	DailyDeposit      *int64
+	UpdatedAt         time.Time
+}
+
+// LimitPolicy resolve os tetos em vigor para o usuário na moeda
+type LimitPolicy interface {
+	LimitsFor(userID uint, currency string) (Limits, error)
+}
+
+type LimitRepository interface {
+	// GetLimitOverride retorna nil quando o usuário não tem tetos próprios na moeda
+	GetLimitOverride(userID uint, currency string) (*LimitOverride, error)
+	SaveLimitOverride(o LimitOverride) error
+	DeleteLimitOverride(userID uint, currency string) error
+	GetLimitUsage(userID uint, currency string, now time.Time) (LimitUsage, error)
+	// SaveDepositWithinLimits grava como SaveTransactionWithOutbox, e SaveWithdrawalWithinLimits como
+	// SaveWithdrawalWithHold (ou HoldForApproval, para saques AWAITING_APPROVAL), somente se o
+	// volume do período com a transação couber em limits. A soma é feita com o saldo do usuário
+	// travado: pedidos simultâneos não passam juntos do teto
+	SaveDepositWithinLimits(tx Transaction, limits Limits) error
+	SaveWithdrawalWithinLimits(tx Transaction, limits Limits) error
+}
@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const DefaultCurrency = "TRX"
+
+// Casas decimais de cada moeda suportada (TRX: 1 TRX = 1_000_000 SUN; USDT TRC-20 também usa 6).
+// Protegido por assetsMu, já que RegisterAsset pode incluir novas moedas.
+var currencyDecimals = map[string]int{
+	"TRX":  6,
+	"USDT": 6,
+	"BRL":  2,
+}
+
+var (
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Money guarda valores monetários em unidades mínimas inteiras (ex.: SUN para TRX),
+// evitando os erros de arredondamento de float64.
+type Money struct {
+	Units    int64  `gorm:"column:amount;type:bigint;not null;default:0" json:"units"`
+	Currency string `gorm:"column:currency;type:varchar(10)" json:"currency"`
+}
+
+func NewMoney(units int64, currency string) Money {
+	return Money{Units: units, Currency: currency}
+}
+
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+// CurrencyDecimals retorna quantas casas decimais a moeda possui
+func CurrencyDecimals(currency string) (int, error) {
+	assetsMu.RLock()
+	dec, ok := currencyDecimals[strings.ToUpper(currency)]
+	assetsMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, currency)
+	}
+	return dec, nil
+}
+
+// ParseMoney converte uma string decimal ("0.29", "-10", "100.5") em Money sem perda de precisão
+func ParseMoney(value, currency string) (Money, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	dec, err := CurrencyDecimals(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	s := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasDot := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" || hasDot && fracPart == "" {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+	if len(fracPart) > dec {
+		return Money{}, fmt.Errorf("%w: %q has more than %d decimal places", ErrInvalidAmount, value, dec)
+	}
+	fracPart += strings.Repeat("0", dec-len(fracPart))
+
+	var units int64
+	for _, r := range intPart + fracPart {
+		if r < '0' || r > '9' {
+			return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+		}
+		if units > (math.MaxInt64-int64(r-'0'))/10 {
+			return Money{}, fmt.Errorf("%w: %q overflows", ErrInvalidAmount, value)
+		}
+		units = units*10 + int64(r-'0')
+	}
+
+	if negative {
+		units = -units
+	}
+	return Money{Units: units, Currency: currency}, nil
+}
+
+// String formata o valor como decimal exato, ex.: "0.290000"
+func (m Money) String() string {
+	dec, err := CurrencyDecimals(m.currency())
+	if err != nil || dec == 0 {
+		return fmt.Sprintf("%d", m.Units)
+	}
+
+	units := m.Units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	pow := int64(math.Pow10(dec))
+	return fmt.Sprintf("%s%d.%0*d", sign, units/pow, dec, units%pow)
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Units: m.Units + o.Units, Currency: m.currency()}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Units: m.Units - o.Units, Currency: m.currency()}, nil
+}
+
+func (m Money) Neg() Money {
+	return Money{Units: -m.Units, Currency: m.Currency}
+}
+
+// Cmp retorna -1, 0 ou 1 comparando m com o
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.Units < o.Units:
+		return -1
+	case m.Units > o.Units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (m Money) IsZero() bool     { return m.Units == 0 }
+func (m Money) IsPositive() bool { return m.Units > 0 }
+func (m Money) IsNegative() bool { return m.Units < 0 }
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.currency() != o.currency() {
+		return fmt.Errorf("%w: %s != %s", ErrCurrencyMismatch, m.currency(), o.currency())
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		in       string
+		currency string
+		units    int64
+	}{
+		{"0.29", "TRX", 290000},
+		{"100", "TRX", 100_000000},
+		{"100.5", "", 100_500000},
+		{"-10.000001", "TRX", -10_000001},
+		{"0.29", "BRL", 29},
+		{".5", "BRL", 50},
+	}
+
+	for _, c := range cases {
+		m, err := domain.ParseMoney(c.in, c.currency)
+		assert.NoError(t, err, c.in)
+		assert.Equal(t, c.units, m.Units, c.in)
+	}
+}
+
+func TestParseMoney_Invalid(t *testing.T) {
+	for _, in := range []string{"", "abc", "1.", "1.2.3", "1e3", "0.0000001", "99999999999999999999"} {
+		_, err := domain.ParseMoney(in, "TRX")
+		assert.ErrorIs(t, err, domain.ErrInvalidAmount, in)
+	}
+
+	_, err := domain.ParseMoney("1", "XYZ")
+	assert.ErrorIs(t, err, domain.ErrUnknownCurrency)
+}
+
+func TestMoney_String(t *testing.T) {
+	assert.Equal(t, "0.290000", domain.NewMoney(290000, "TRX").String())
+	assert.Equal(t, "-1.050000", domain.NewMoney(-1_050000, "TRX").String())
+	assert.Equal(t, "12.30", domain.NewMoney(1230, "BRL").String())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := domain.NewMoney(290000, "TRX")
+	b := domain.NewMoney(10000, "TRX")
+
+	sum, err := a.Add(b)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(300000), sum.Units)
+
+	diff, err := a.Sub(b)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(280000), diff.Units)
+	assert.Equal(t, 1, a.Cmp(b))
+
+	_, err = a.Add(domain.NewMoney(1, "BRL"))
+	assert.ErrorIs(t, err, domain.ErrCurrencyMismatch)
+}
+
+func TestMoney_JSONRoundTrip(t *testing.T) {
+	tx := domain.Transaction{ID: "tx-1", Amount: domain.NewMoney(290000, "TRX")}
+
+	data, err := json.Marshal(tx)
+	assert.NoError(t, err)
+
+	var decoded domain.Transaction
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, tx.Amount, decoded.Amount)
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// DepositAddress é o endereço TRON exclusivo de um usuário para depósitos on-chain.
+// Index é a posição do endereço na carteira de origem (ex.: índice de derivação).
+type DepositAddress struct {
+	UserID    uint   `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Address   string `gorm:"uniqueIndex" json:"address"`
+	Index     uint32 `gorm:"uniqueIndex" json:"index"`
+	CreatedAt time.Time
+}
+
+// AddressDeriver gera o endereço de depósito de um índice sem precisar da chave privada
+type AddressDeriver interface {
+	DeriveAddress(index uint32) (string, error)
+}
+
+// Block é um bloco da cadeia com as transferências de TRX e de tokens TRC-20 que ele contém
+type Block struct {
+	Number     int64
+	Hash       string
+	ParentHash string
+	Transfers  []Transfer
+}
+
+// Transfer é uma transferência de TRX (Currency vazio, Amount em SUN) ou de um token
+// TRC-20 registrado (Amount nas unidades mínimas do token)
+type Transfer struct {
+	TxID        string
+	FromAddress string
+	ToAddress   string
+	Amount      int64
+	Currency    string
+	Success     bool
+}
+
+// BlockSource é a parte do nó usada pelo scanner de depósitos
+type BlockSource interface {
+	GetLatestBlockNumber() (int64, error)
+	GetBlock(number int64) (*Block, error)
+}
+
+// ScannedBlock guarda o hash de cada bloco lido pelo scanner. O maior número é o cursor
+// do scanner; os últimos blocos permitem detectar reorganizações da cadeia.
+type ScannedBlock struct {
+	Number     int64  `gorm:"primaryKey;autoIncrement:false"`
+	Hash       string `gorm:"type:text"`
+	ParentHash string `gorm:"type:text"`
+	ScannedAt  time.Time
+}
+
+const (
+	OnchainDepositSeen     = "SEEN"
+	OnchainDepositCredited = "CREDITED"
+	OnchainDepositOrphaned = "ORPHANED"
+)
+
+// OnchainDeposit é uma transferência recebida em um endereço de depósito. Fica SEEN até ter
+// confirmações suficientes e então é creditada como uma transação de depósito.
+type OnchainDeposit struct {
+	TxHash        string `gorm:"primaryKey;type:text"`
+	UserID        uint   `gorm:"index"`
+	Address       string
+	Amount        Money `gorm:"embedded"`
+	BlockNumber   int64 `gorm:"index"`
+	BlockHash     string
+	Status        string `gorm:"index"`
+	TransactionID string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+var ErrDepositAddressNotFound = errors.New("deposit address not found")
+
+type DepositRepository interface {
+	GetDepositAddress(userID uint) (*DepositAddress, error)
+	// SaveDepositAddress não sobrescreve um endereço já atribuído ao usuário
+	SaveDepositAddress(addr DepositAddress) error
+	// UsersByDepositAddress devolve, dentre os endereços informados, os que pertencem a usuários
+	UsersByDepositAddress(addresses []string) (map[string]uint, error)
+
+	LastScannedBlock() (*ScannedBlock, error)
+	GetScannedBlock(number int64) (*ScannedBlock, error)
+	// RecordScannedBlock grava o bloco, seus depósitos e descarta hashes mais antigos que keepFrom
+	RecordScannedBlock(block ScannedBlock, deposits []OnchainDeposit, keepFrom int64) error
+	// RewindScan descarta os blocos acima de number e devolve os depósitos que ficaram órfãos
+	RewindScan(number int64) ([]OnchainDeposit, error)
+
+	ListSeenDeposits(maxBlock int64) ([]OnchainDeposit, error)
+	// CreditOnchainDeposit marca o depósito como CREDITED e grava a transação com o outbox atomicamente
+	CreditOnchainDeposit(dep OnchainDeposit, tx Transaction) error
+}
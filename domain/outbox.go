@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	OutboxStatusPending = "PENDING"
+	OutboxStatusSent    = "SENT"
+	// OutboxStatusInvalid marca uma mensagem que nunca poderá ser publicada, como um payload
+	// que não decodifica; o relay não a busca mais
+	OutboxStatusInvalid = "INVALID"
+)
+
+// OutboxMessage é gravado na mesma transação do banco que a Transaction e
+// publicado no Kafka depois, pelo relay do outbox.
+type OutboxMessage struct {
+	ID            uint   `gorm:"primaryKey"`
+	TransactionID string `gorm:"index"`
+	Payload       []byte
+	Status        string `gorm:"index;default:PENDING"`
+	Attempts      int
+	LastError     string
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+type OutboxRepository interface {
+	// SaveTransactionWithOutbox grava a transação como RECEIVED e a mensagem do outbox atomicamente
+	SaveTransactionWithOutbox(tx Transaction) error
+	// SaveWithdrawalWithHold reserva o valor do saque no saldo do usuário e grava como
+	// SaveTransactionWithOutbox, tudo na mesma transação; sem saldo disponível retorna ErrInsufficientFunds
+	SaveWithdrawalWithHold(tx Transaction) error
+	FetchPendingOutbox(limit int) ([]OutboxMessage, error)
+	MarkOutboxSent(id uint) error
+	MarkOutboxFailed(id uint, reason string) error
+	// MarkOutboxInvalid tira a mensagem da fila de vez, guardando o motivo para análise manual
+	MarkOutboxInvalid(id uint, reason string) error
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// Classes de chave do rate limiter: cada uma tem o próprio limite e as próprias contagens
+const (
+	RateLimitIP          = "ip"          // por IP de origem, em todas as rotas
+	RateLimitUser        = "user"        // por usuário autenticado
+	RateLimitEndpoint    = "endpoint"    // por usuário (ou IP) e rota
+	RateLimitTransaction = "transaction" // depósitos, saques e transferências por usuário
+)
+
+// RateLimit permite Limit requisições por Period, em rajada ou espaçadas: a cota volta aos poucos,
+// uma requisição a cada Period/Limit (GCRA)
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// Interval é o tempo para a cota recuperar uma requisição
+func (l RateLimit) Interval() time.Duration {
+	return l.Period / time.Duration(l.Limit)
+}
+
+// Result monta o resultado a partir de ResetAfter, o tempo até a cota estar cheia de novo
+func (l RateLimit) Result(allowed bool, resetAfter, retryAfter time.Duration) RateLimitResult {
+	remaining := 0
+	if interval := l.Interval(); interval > 0 && resetAfter < l.Period {
+		remaining = int((l.Period - resetAfter) / interval)
+	}
+	return RateLimitResult{
+		Allowed:    allowed,
+		Limit:      l.Limit,
+		Remaining:  remaining,
+		ResetAfter: resetAfter,
+		RetryAfter: retryAfter,
+	}
+}
+
+// RateLimitResult é o estado da cota depois da requisição; RetryAfter só é preenchido quando recusada
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitError carrega o resultado para a API devolver Retry-After; errors.Is(err, ErrRateLimited) vale
+type RateLimitError struct {
+	Result RateLimitResult
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v: retry after %s", ErrRateLimited, e.Result.RetryAfter.Round(time.Second))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"errors"
 	"time"
 )
 
@@ -17,32 +19,116 @@ type User struct {
 	Password      string
 	Role          string // Ex: "user" ou "admin"
 	WalletAddress string
+	KYCTier       int // nível de verificação do cadastro; define os tetos de valor junto com Role
 }
 
 type Transaction struct {
 	ID            string `gorm:"type:text;primaryKey"`
 	UserID        uint
 	User          User
-	Amount        float64
+	Amount        Money `gorm:"embedded"`
 	Timestamp     time.Time
 	Type          string
 	WalletAddress string
 	TxHash        string
-	Status        string `gorm:"default:PENDING"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	Fee           int64 // tarifa cobrada além de Amount, em unidades mínimas da mesma moeda
+	// carteira e nonce do envio em redes EVM, onde só o nonce diz se um envio sumido do nó
+	// ainda pode ser minerado
+	FromAddress string
+	Nonce       *uint64
+	// Transferências internas: o outro usuário, a outra perna e a mensagem do remetente
+	CounterpartyID      uint   `gorm:"index"`
+	LinkedTransactionID string `gorm:"index"`
+	Memo                string
+	Status              TransactionStatus `gorm:"default:RECEIVED"`
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// FeeAmount é a tarifa na moeda do valor
+func (t Transaction) FeeAmount() Money {
+	return NewMoney(t.Fee, t.Amount.Currency)
 }
 
+// Total é o que sai do saldo do usuário: o valor mais a tarifa
+func (t Transaction) Total() Money {
+	return NewMoney(t.Amount.Units+t.Fee, t.Amount.Currency)
+}
+
+// Balance é o saldo do usuário em uma moeda; cada moeda tem a sua linha.
+// Units é o saldo disponível e Held o valor reservado por saques ainda não enviados
 type Balance struct {
-	UserID uint `gorm:"primaryKey" json:"user_id"`
-	Amount float64
+	UserID   uint   `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	Currency string `gorm:"primaryKey;type:varchar(10)" json:"currency"`
+	Units    int64  `gorm:"column:amount;type:bigint;not null;default:0" json:"units"`
+	Held     int64  `gorm:"column:held;type:bigint;not null;default:0" json:"held"`
+}
+
+func NewBalance(userID uint, amount Money) Balance {
+	return Balance{UserID: userID, Currency: amount.currency(), Units: amount.Units}
+}
+
+func (b Balance) Amount() Money {
+	return NewMoney(b.Units, b.Currency)
+}
+
+func (b Balance) HeldAmount() Money {
+	return NewMoney(b.Held, b.Currency)
+}
+
+// TransactionJob é uma transação lida do Kafka aguardando processamento pelos workers.
+// Partition/Offset identificam a mensagem de origem para o commit manual.
+type TransactionJob struct {
+	Transaction Transaction
+	Partition   int
+	Offset      int64
+}
+
+// JobAck é devolvido pelo worker ao consumer quando termina um TransactionJob.
+// Err != nil indica que a transação não foi persistida mesmo após Attempts tentativas.
+type JobAck struct {
+	Partition int
+	Offset    int64
+	Err       error
+	Attempts  int
+}
+
+type IdempotencyRecord struct {
+	UserID        uint   `gorm:"primaryKey;autoIncrement:false"`
+	Key           string `gorm:"primaryKey;type:text"`
+	RequestHash   string
+	StatusCode    int
+	ResponseBody  []byte
+	TransactionID string
+	Completed     bool
+	// Enquanto não está Completed, a chave é da requisição com LeaseToken até LockedUntil;
+	// depois disso (ex.: o processo caiu no meio) outra requisição pode assumi-la
+	LeaseToken  string
+	LockedUntil time.Time
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
+
+const (
+	ProcessedOutcomeApplied  = "applied"
+	ProcessedOutcomeRejected = "rejected"
+)
+
+// ProcessedMessage registra as transações já tratadas pelos workers, tornando
+// a reentrega de uma mensagem do Kafka uma operação sem efeito.
+type ProcessedMessage struct {
+	TransactionID string `gorm:"primaryKey;type:text"`
+	Outcome       string
+	WorkerID      int
+	ProcessedAt   time.Time
 }
 
+// BlockchainTransaction é um envio em qualquer rede; Amount está nas unidades do saldo do
+// ativo e cada driver converte para a unidade da sua rede
 type BlockchainTransaction struct {
-	ToAddress   string `json:"to_address"`
-	FromAddress string `json:"owner_address"`
-	Amount      int64  `json:"amount"`
-	Visible     bool   `json:"visible"`
+	ToAddress   string
+	FromAddress string
+	Amount      int64
 	Timestamp   time.Time
 }
 
@@ -50,7 +136,45 @@ type BlockchainTxResult struct {
 	TxID        string
 	FromAddress string
 	ToAddress   string
-	Amount      float64
+	Amount      Money
+	// Nonce só é preenchido por redes com nonce por conta (EVM)
+	Nonce *uint64
+}
+
+// BlockchainTxInfo é o estado on-chain de uma transação já incluída em um bloco
+type BlockchainTxInfo struct {
+	TxID        string
+	BlockNumber int64
+	Success     bool
+	Reason      string
+}
+
+var (
+	ErrBlockchainTxNotFound = errors.New("transaction not found on-chain")
+	// ErrBlockchainTxPending indica que o nó conhece a transação, mas ela ainda não tem recibo
+	ErrBlockchainTxPending = errors.New("transaction pending on-chain")
+)
+
+// BroadcastError é devolvido por Send quando a transação já foi assinada e o envio ao nó falhou
+// sem uma recusa definitiva (timeout, conexão perdida): ela pode ter chegado à rede. Result traz
+// o hash e o nonce para que o resultado seja decidido on-chain, e não com um estorno imediato.
+type BroadcastError struct {
+	Result *BlockchainTxResult
+	Err    error
+}
+
+func (e *BroadcastError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BroadcastError) Unwrap() error {
+	return e.Err
+}
+
+// NonceTracker é implementado pelas redes em que uma transação não expira (EVM): um envio
+// que o nó não conhece ainda pode ser minerado até o nonce dele ser usado por outra transação
+type NonceTracker interface {
+	NonceConsumed(from string, nonce uint64) (bool, error)
 }
 
 type RedisClientInterface interface {
@@ -58,10 +182,15 @@ type RedisClientInterface interface {
 	Set(ctx context.Context, key string, value int) error
 	Incr(ctx context.Context, key string) (int, error)
 	Expire(ctx context.Context, key string, expiration time.Duration) error
+	// Eval roda um script Lua atomicamente e devolve o resultado como lista de inteiros
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) ([]int64, error)
 }
 
 type RateLimiter interface {
+	// CheckTransactionRateLimit consome a cota de transações do usuário; recusada, devolve *RateLimitError
 	CheckTransactionRateLimit(userID uint) error
+	// Allow consome uma requisição da chave na classe; classes sem limite configurado sempre passam
+	Allow(class, key string) (RateLimitResult, error)
 }
 
 type TransactionRepository interface {
@@ -69,12 +198,16 @@ type TransactionRepository interface {
 	GetByUser(userID uint) ([]Transaction, error)
 	GetTransactionsByUserID(userID uint) ([]Transaction, error)
 	UpdateTransactionHash(txID string, txHash string) error
-	UpdateTransactionStatus(txID string, status string) error // 👈 Novo método
+	// UpdateTransactionStatus só aplica transições válidas e falha se o status atual não for from
+	UpdateTransactionStatus(txID string, from, to TransactionStatus, reason string) error
+	GetStatusHistory(txID string) ([]TransactionStatusHistory, error)
+	ListTransactionsByStatus(status TransactionStatus, limit int) ([]Transaction, error)
 }
 
 type BalanceRepository interface {
 	UpdateBalance(tx Transaction) error
-	GetBalance(userID uint) (*Balance, error)
+	GetBalance(userID uint, currency string) (*Balance, error)
+	ListBalances(userID uint) ([]Balance, error)
 }
 
 type UserRepository interface {
@@ -84,6 +217,35 @@ type UserRepository interface {
 	Delete(email string) error
 }
 
+// IdempotencyRepository guarda a primeira resposta de cada Idempotency-Key por usuário
+type IdempotencyRepository interface {
+	// ReserveIdempotencyKey grava a chave se ela ainda não existir, estiver expirada ou com a
+	// reserva vencida. Retorna o registro existente e false quando a chave já estava reservada.
+	ReserveIdempotencyKey(rec IdempotencyRecord) (*IdempotencyRecord, bool, error)
+	// CompleteIdempotencyKey e ReleaseIdempotencyKey só alteram a chave se rec.LeaseToken
+	// ainda for o dono da reserva
+	CompleteIdempotencyKey(rec IdempotencyRecord) error
+	ReleaseIdempotencyKey(rec IdempotencyRecord) error
+}
+
+// Signer assina com a chave da carteira quente sem expor o material da chave a quem chama.
+// TRON e Ethereum usam a mesma curva (secp256k1); cada driver deriva o endereço da chave pública.
+type Signer interface {
+	PublicKey() (*ecdsa.PublicKey, error)
+	// SignHash assina um hash de 32 bytes e devolve a assinatura recuperável [R || S || V]
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// BlockchainClient é o driver de uma rede; o ChainRegistry escolhe o driver pelo ativo
 type BlockchainClient interface {
-	SendSignedTRX(tx BlockchainTransaction, transactionID string) (*BlockchainTxResult, error)
+	// ValidateAddress retorna ErrInvalidAddress quando o endereço não é da rede
+	ValidateAddress(address string) error
+	// EstimateFee devolve a taxa estimada do envio, na moeda nativa da rede
+	EstimateFee(tx BlockchainTransaction, asset Asset) (Money, error)
+	// Send transfere a moeda nativa ou chama transfer(address,uint256) no contrato do token;
+	// uma falha depois da assinatura, sem recusa do nó, vem como *BroadcastError
+	Send(tx BlockchainTransaction, asset Asset, transactionID string) (*BlockchainTxResult, error)
+	// GetTransactionInfo retorna ErrBlockchainTxNotFound enquanto a transação não entrou em um bloco
+	GetTransactionInfo(txID string) (*BlockchainTxInfo, error)
+	GetLatestBlockNumber() (int64, error)
 }
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Recorrências aceitas por um agendamento; "once" executa uma vez em StartAt
+const (
+	RecurrenceOnce    = "once"
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+	RecurrenceCron    = "cron"
+)
+
+// Tipos de operação que podem ser agendados
+const (
+	ScheduleWithdraw = "withdraw"
+	ScheduleTransfer = "transfer"
+)
+
+// Schedule é um saque ou transferência que o scheduler executa em NextRunAt. Cada ocorrência
+// vira uma transação comum, com ID derivado do agendamento e do horário da ocorrência.
+// NextRunAt nulo indica um agendamento encerrado (execução única já feita ou cancelado).
+type Schedule struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"index"`
+	Type   string // ScheduleWithdraw ou ScheduleTransfer
+	Amount Money  `gorm:"embedded"`
+	// DestinationID escolhe o destino de um saque (0 = carteira do cadastro); RecipientID é o
+	// destinatário de uma transferência
+	DestinationID uint
+	RecipientID   uint
+	Memo          string
+
+	Recurrence string
+	Cron       string
+	// StartAt ancora a recorrência: saques mensais caem no mesmo dia do mês de StartAt
+	StartAt   time.Time
+	NextRunAt *time.Time `gorm:"index"`
+
+	LastRunAt         *time.Time
+	LastTransactionID string
+	LastError         string
+	Runs              int
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ScheduleRun é o resultado de uma ocorrência, gravado ao avançar o agendamento
+type ScheduleRun struct {
+	ScheduleID    uint
+	RunAt         time.Time
+	NextRunAt     *time.Time
+	TransactionID string // vazio quando a ocorrência falhou
+	Error         string
+}
+
+var (
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrInvalidSchedule  = errors.New("invalid schedule")
+)
+
+type ScheduleRepository interface {
+	CreateSchedule(s *Schedule) error
+	ListSchedules(userID uint) ([]Schedule, error)
+	// GetSchedule só encontra agendamentos do próprio usuário
+	GetSchedule(userID, id uint) (*Schedule, error)
+	UpdateSchedule(s *Schedule) error
+	DeleteSchedule(userID, id uint) error
+	// ListDueSchedules retorna os agendamentos com NextRunAt até now, os mais atrasados primeiro
+	ListDueSchedules(now time.Time, limit int) ([]Schedule, error)
+	// AdvanceSchedule grava a ocorrência e o próximo NextRunAt somente se o agendamento ainda
+	// estiver em run.RunAt; false quando outra execução já o avançou
+	AdvanceSchedule(run ScheduleRun) (bool, error)
+	// TransactionExists diz se a ocorrência já gerou sua transação, antes de uma queda do processo
+	TransactionExists(id string) (bool, error)
+}
+
+// Lease é a liderança de uma tarefa periódica entre as réplicas; vale até ExpiresAt
+type Lease struct {
+	Name      string `gorm:"primaryKey"`
+	Holder    string
+	ExpiresAt time.Time
+}
+
+// LeaderLock garante que só uma réplica execute uma tarefa por vez
+type LeaderLock interface {
+	// AcquireLease pega a liderança ou a renova por ttl; false quando outra réplica a detém
+	AcquireLease(name, holder string, ttl time.Duration) (bool, error)
+	ReleaseLease(name, holder string) error
+}
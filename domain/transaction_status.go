@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TransactionStatus string
+
+const (
+	StatusReceived  TransactionStatus = "RECEIVED"
+	StatusPending   TransactionStatus = "PENDING"
+	StatusSending   TransactionStatus = "SENDING"
+	StatusBroadcast TransactionStatus = "BROADCAST"
+	StatusConfirmed TransactionStatus = "CONFIRMED"
+	StatusCompleted TransactionStatus = "COMPLETED"
+	StatusFailed    TransactionStatus = "FAILED"
+	StatusRefunded  TransactionStatus = "REFUNDED"
+	StatusCancelled TransactionStatus = "CANCELLED"
+
+	// saques acima do limite de aprovação esperam a decisão de um admin com o valor retido
+	StatusAwaitingApproval TransactionStatus = "AWAITING_APPROVAL"
+	StatusApproved         TransactionStatus = "APPROVED"
+	StatusRejected         TransactionStatus = "REJECTED"
+)
+
+const RefundTransaction = "refund"
+
+var (
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrStatusConflict    = errors.New("transaction status changed concurrently")
+)
+
+// transitions lista, por tipo de transação, os próximos status permitidos a partir de cada status.
+// Status sem entrada são finais.
+var transitions = map[string]map[TransactionStatus][]TransactionStatus{
+	DepositTransaction: {
+		StatusReceived: {StatusPending, StatusCompleted, StatusFailed, StatusCancelled},
+		StatusPending:  {StatusConfirmed, StatusCompleted, StatusFailed},
+		// depósitos on-chain ficam CONFIRMED até serem creditados
+		StatusConfirmed: {StatusCompleted, StatusFailed},
+	},
+	WithdrawTransaction: {
+		StatusAwaitingApproval: {StatusApproved, StatusRejected},
+		// um saque aprovado segue para o worker, que usa o valor já retido
+		StatusApproved: {StatusPending, StatusFailed},
+		StatusReceived: {StatusPending, StatusFailed, StatusCancelled},
+		StatusPending:  {StatusSending, StatusFailed, StatusCancelled},
+		// o worker reservou o saque para o envio; só sai daqui com o resultado dele
+		StatusSending: {StatusBroadcast, StatusFailed},
+		// saques só saem de BROADCAST pelo rastreador de confirmações; CONFIRMED é final
+		StatusBroadcast: {StatusConfirmed, StatusFailed},
+		StatusFailed:    {StatusRefunded},
+	},
+	// a perna de saída é aplicada de uma vez pelo worker; a de entrada já nasce COMPLETED
+	TransferOutTransaction: {
+		StatusReceived: {StatusCompleted, StatusFailed, StatusCancelled},
+	},
+	RefundTransaction: {
+		StatusReceived: {StatusCompleted, StatusFailed},
+	},
+}
+
+// CanTransition diz se uma transação do tipo txType pode ir de from para to
+func CanTransition(txType string, from, to TransactionStatus) bool {
+	for _, next := range transitions[txType][from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition retorna ErrInvalidTransition descrevendo a mudança rejeitada
+func ValidateTransition(txType string, from, to TransactionStatus) error {
+	if !CanTransition(txType, from, to) {
+		return fmt.Errorf("%w: %s %s → %s", ErrInvalidTransition, txType, from, to)
+	}
+	return nil
+}
+
+// IsFinal indica que nenhuma transição sai deste status
+func IsFinal(txType string, status TransactionStatus) bool {
+	return len(transitions[txType][status]) == 0
+}
+
+// TransactionStatusHistory registra cada mudança de status, inclusive a criação (From vazio)
+type TransactionStatusHistory struct {
+	ID            uint              `gorm:"primaryKey" json:"-"`
+	TransactionID string            `gorm:"index" json:"transaction_id"`
+	From          TransactionStatus `json:"from"`
+	To            TransactionStatus `json:"to"`
+	Reason        string            `json:"reason"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+func (TransactionStatusHistory) TableName() string {
+	return "transaction_status_history"
+}
@@ -0,0 +1,62 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		txType string
+		from   domain.TransactionStatus
+		to     domain.TransactionStatus
+		ok     bool
+	}{
+		{domain.DepositTransaction, domain.StatusReceived, domain.StatusCompleted, true},
+		{domain.DepositTransaction, domain.StatusPending, domain.StatusConfirmed, true},
+		{domain.DepositTransaction, domain.StatusCompleted, domain.StatusPending, false},
+		{domain.WithdrawTransaction, domain.StatusReceived, domain.StatusPending, true},
+		{domain.WithdrawTransaction, domain.StatusPending, domain.StatusSending, true},
+		{domain.WithdrawTransaction, domain.StatusPending, domain.StatusBroadcast, false},
+		{domain.WithdrawTransaction, domain.StatusSending, domain.StatusBroadcast, true},
+		{domain.WithdrawTransaction, domain.StatusSending, domain.StatusCancelled, false},
+		{domain.WithdrawTransaction, domain.StatusBroadcast, domain.StatusConfirmed, true},
+		{domain.WithdrawTransaction, domain.StatusBroadcast, domain.StatusCompleted, false},
+		{domain.WithdrawTransaction, domain.StatusFailed, domain.StatusRefunded, true},
+		{domain.WithdrawTransaction, domain.StatusReceived, domain.StatusCompleted, false},
+		{domain.WithdrawTransaction, domain.StatusCompleted, domain.StatusFailed, false},
+		{domain.WithdrawTransaction, domain.StatusRefunded, domain.StatusFailed, false},
+		{domain.WithdrawTransaction, domain.StatusAwaitingApproval, domain.StatusApproved, true},
+		{domain.WithdrawTransaction, domain.StatusAwaitingApproval, domain.StatusRejected, true},
+		{domain.WithdrawTransaction, domain.StatusAwaitingApproval, domain.StatusPending, false},
+		{domain.WithdrawTransaction, domain.StatusApproved, domain.StatusPending, true},
+		{domain.WithdrawTransaction, domain.StatusRejected, domain.StatusApproved, false},
+		{domain.RefundTransaction, domain.StatusReceived, domain.StatusCompleted, true},
+		{"unknown", domain.StatusReceived, domain.StatusCompleted, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.ok, domain.CanTransition(c.txType, c.from, c.to), "%s %s → %s", c.txType, c.from, c.to)
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	assert.NoError(t, domain.ValidateTransition(domain.WithdrawTransaction, domain.StatusPending, domain.StatusFailed))
+
+	err := domain.ValidateTransition(domain.DepositTransaction, domain.StatusCompleted, domain.StatusFailed)
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+	assert.Contains(t, err.Error(), "COMPLETED → FAILED")
+}
+
+func TestIsFinal(t *testing.T) {
+	assert.True(t, domain.IsFinal(domain.DepositTransaction, domain.StatusCompleted))
+	assert.True(t, domain.IsFinal(domain.WithdrawTransaction, domain.StatusConfirmed))
+	assert.True(t, domain.IsFinal(domain.WithdrawTransaction, domain.StatusRefunded))
+	assert.True(t, domain.IsFinal(domain.WithdrawTransaction, domain.StatusRejected))
+	assert.True(t, domain.IsFinal(domain.WithdrawTransaction, domain.StatusCancelled))
+	assert.False(t, domain.IsFinal(domain.WithdrawTransaction, domain.StatusFailed))
+	assert.False(t, domain.IsFinal(domain.WithdrawTransaction, domain.StatusBroadcast))
+}
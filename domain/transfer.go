@@ -0,0 +1,19 @@
+package domain
+
+import "errors"
+
+// Uma transferência interna tem duas pernas: transfer_out no remetente, gravada pela API,
+// e transfer_in no destinatário, gravada pelo worker ao aplicar; cada uma aponta para a outra
+const (
+	TransferOutTransaction = "transfer_out"
+	TransferInTransaction  = "transfer_in"
+)
+
+// MaxTransferMemoLength limita a mensagem que acompanha a transferência nos extratos
+const MaxTransferMemoLength = 140
+
+var (
+	ErrRecipientNotFound = errors.New("recipient not found")
+	ErrSelfTransfer      = errors.New("cannot transfer to yourself")
+	ErrMemoTooLong       = errors.New("memo is too long")
+)
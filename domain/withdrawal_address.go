@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// WithdrawalAddress é um destino de saque cadastrado pelo usuário. O endereço é validado para a
+// rede do ativo informado e só recebe saques a partir de ActiveAt, fim do período de carência.
+type WithdrawalAddress struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_withdrawal_address_dest" json:"-"`
+	Label     string    `json:"label"`
+	Currency  string    `gorm:"type:varchar(10)" json:"currency"`
+	Chain     string    `gorm:"uniqueIndex:idx_withdrawal_address_dest;type:varchar(20)" json:"chain"`
+	Address   string    `gorm:"uniqueIndex:idx_withdrawal_address_dest" json:"address"`
+	ActiveAt  time.Time `json:"active_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (a WithdrawalAddress) IsActive(now time.Time) bool {
+	return !now.Before(a.ActiveAt)
+}
+
+var (
+	ErrWithdrawalAddressNotFound    = errors.New("withdrawal address not found")
+	ErrWithdrawalAddressExists      = errors.New("withdrawal address already registered")
+	ErrWithdrawalAddressCoolingDown = errors.New("withdrawal address is still in its cooling period")
+	// ErrDestinationChainMismatch indica um destino cadastrado em outra rede que não a do ativo sacado
+	ErrDestinationChainMismatch = errors.New("withdrawal address is not on the asset's chain")
+)
+
+type WithdrawalAddressRepository interface {
+	ListWithdrawalAddresses(userID uint) ([]WithdrawalAddress, error)
+	// GetWithdrawalAddress só encontra endereços do próprio usuário
+	GetWithdrawalAddress(userID, id uint) (*WithdrawalAddress, error)
+	// SaveWithdrawalAddress retorna ErrWithdrawalAddressExists se o usuário já tem o endereço na rede
+	SaveWithdrawalAddress(addr *WithdrawalAddress) error
+	DeleteWithdrawalAddress(userID, id uint) error
+}
@@ -0,0 +1,268 @@
+package fees
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+var ErrInvalidRule = errors.New("invalid fee rule")
+
+// Rule é uma regra do arquivo de tarifas. Type é obrigatório; Currency e Role vazios valem para
+// qualquer moeda e papel. A tarifa é Fixed + Percent do valor, limitada por Min e Max; com Tiers,
+// a faixa em que o valor cai define Fixed e Percent para o valor inteiro.
+// Valores são decimais na moeda da regra ("1.5"), e Percent vai até 4 casas ("0.25" = 0,25%).
+type Rule struct {
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+	Role     string `json:"role"`
+	Fixed    string `json:"fixed"`
+	Percent  string `json:"percent"`
+	Min      string `json:"min"`
+	Max      string `json:"max"`
+	Tiers    []Tier `json:"tiers"`
+}
+
+// Tier vale para valores até UpTo, inclusive; a última faixa deixa UpTo vazio
+type Tier struct {
+	UpTo    string `json:"up_to"`
+	Fixed   string `json:"fixed"`
+	Percent string `json:"percent"`
+}
+
+// percentScale: Percent é guardado em milionésimos do valor (1% = 10.000)
+const percentScale = 1_000_000
+
+type price struct {
+	fixed   int64
+	percent int64
+}
+
+type tier struct {
+	upTo int64 // 0 na última faixa, sem teto
+	price
+}
+
+type rule struct {
+	txType, currency, role string
+	price
+	min, max int64 // max 0 = sem teto
+	tiers    []tier
+}
+
+// Engine escolhe, para cada transação, a regra mais específica: moeda e papel definidos vencem
+// regras genéricas e, entre regras iguais, vale a primeira do arquivo. Sem regra não há tarifa.
+type Engine struct {
+	rules []rule
+	users d.UserRepository
+}
+
+var _ d.FeeSchedule = &Engine{}
+
+// LoadRules lê o arquivo JSON com a lista de regras
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	return rules, nil
+}
+
+// NewEngine valida as regras; users só é consultado quando alguma regra depende do papel
+func NewEngine(rules []Rule, users d.UserRepository) (*Engine, error) {
+	e := &Engine{users: users}
+	for i, r := range rules {
+		compiled, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidRule, i, err)
+		}
+		if compiled.role != "" && users == nil {
+			return nil, fmt.Errorf("%w: rule %d: role rules need the user repository", ErrInvalidRule, i)
+		}
+		e.rules = append(e.rules, compiled)
+	}
+	return e, nil
+}
+
+func compile(r Rule) (rule, error) {
+	out := rule{
+		txType:   strings.TrimSpace(r.Type),
+		currency: strings.ToUpper(strings.TrimSpace(r.Currency)),
+		role:     strings.TrimSpace(r.Role),
+	}
+	if out.txType == "" {
+		return rule{}, errors.New("type is required")
+	}
+	// valores absolutos só fazem sentido numa moeda
+	if out.currency == "" && (r.Fixed != "" || r.Min != "" || r.Max != "" || len(r.Tiers) > 0) {
+		return rule{}, errors.New("fixed, min, max and tiers need a currency")
+	}
+
+	var err error
+	if out.price, err = parsePrice(r.Fixed, r.Percent, out.currency); err != nil {
+		return rule{}, err
+	}
+	if out.min, err = parseAmount(r.Min, out.currency); err != nil {
+		return rule{}, err
+	}
+	if out.max, err = parseAmount(r.Max, out.currency); err != nil {
+		return rule{}, err
+	}
+	if out.max != 0 && out.max < out.min {
+		return rule{}, errors.New("max is below min")
+	}
+
+	if len(r.Tiers) > 0 && (r.Fixed != "" || r.Percent != "") {
+		return rule{}, errors.New("tiers replace fixed and percent")
+	}
+	for i, t := range r.Tiers {
+		var compiled tier
+		if compiled.price, err = parsePrice(t.Fixed, t.Percent, out.currency); err != nil {
+			return rule{}, err
+		}
+		last := i == len(r.Tiers)-1
+		switch {
+		case last && t.UpTo != "":
+			return rule{}, errors.New("the last tier must not have up_to")
+		case !last:
+			if compiled.upTo, err = parseAmount(t.UpTo, out.currency); err != nil {
+				return rule{}, err
+			}
+			if compiled.upTo <= 0 || i > 0 && compiled.upTo <= out.tiers[i-1].upTo {
+				return rule{}, errors.New("tier up_to must be positive and increasing")
+			}
+		}
+		out.tiers = append(out.tiers, compiled)
+	}
+	return out, nil
+}
+
+func parsePrice(fixed, percent, currency string) (price, error) {
+	var p price
+	var err error
+	if p.fixed, err = parseAmount(fixed, currency); err != nil {
+		return price{}, err
+	}
+	if p.percent, err = parsePercent(percent); err != nil {
+		return price{}, err
+	}
+	return p, nil
+}
+
+func parseAmount(value, currency string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	m, err := d.ParseMoney(value, currency)
+	if err != nil {
+		return 0, err
+	}
+	if m.IsNegative() {
+		return 0, fmt.Errorf("negative amount %q", value)
+	}
+	return m.Units, nil
+}
+
+// parsePercent converte "0.25" em milionésimos (2.500), até 4 casas decimais e no máximo 100%
+func parsePercent(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	intPart, fracPart, _ := strings.Cut(strings.TrimSpace(value), ".")
+	if len(fracPart) > 4 {
+		return 0, fmt.Errorf("percent %q has more than 4 decimal places", value)
+	}
+	n, err := strconv.ParseInt(intPart+fracPart+strings.Repeat("0", 4-len(fracPart)), 10, 64)
+	if err != nil || n < 0 || n > 100*10_000 {
+		return 0, fmt.Errorf("invalid percent %q", value)
+	}
+	return n, nil
+}
+
+func (e *Engine) Quote(userID uint, txType string, amount d.Money) (d.Money, error) {
+	currency := strings.ToUpper(amount.Currency)
+	if currency == "" {
+		currency = d.DefaultCurrency
+	}
+
+	var (
+		role       string
+		roleLoaded bool
+		best       *rule
+	)
+	bestScore := -1
+	for i := range e.rules {
+		r := &e.rules[i]
+		if r.txType != txType || r.currency != "" && r.currency != currency {
+			continue
+		}
+		if r.role != "" {
+			if !roleLoaded {
+				user, err := e.users.GetByID(userID)
+				if err != nil {
+					return d.Money{}, err
+				}
+				role, roleLoaded = user.Role, true
+			}
+			if r.role != role {
+				continue
+			}
+		}
+
+		score := 0
+		if r.currency != "" {
+			score += 2
+		}
+		if r.role != "" {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+
+	if best == nil {
+		return d.Zero(currency), nil
+	}
+	return d.NewMoney(best.fee(amount.Units), currency), nil
+}
+
+func (r *rule) fee(units int64) int64 {
+	p := r.price
+	for _, t := range r.tiers {
+		p = t.price
+		if t.upTo == 0 || units <= t.upTo {
+			break
+		}
+	}
+
+	fee := p.fixed + percentOf(units, p.percent)
+	if fee < r.min {
+		fee = r.min
+	}
+	if r.max != 0 && fee > r.max {
+		fee = r.max
+	}
+	return fee
+}
+
+// percentOf arredonda para cima: a tarifa nunca fica abaixo da fração da menor unidade
+func percentOf(units, percent int64) int64 {
+	if percent == 0 || units <= 0 {
+		return 0
+	}
+	n := new(big.Int).Mul(big.NewInt(units), big.NewInt(percent))
+	n.Add(n, big.NewInt(percentScale-1))
+	n.Quo(n, big.NewInt(percentScale))
+	return n.Int64()
+}
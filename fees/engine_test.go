@@ -0,0 +1,83 @@
+package fees_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/fees"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+)
+
+func trx(units int64) domain.Money {
+	return domain.NewMoney(units, "TRX")
+}
+
+func TestEngine_Quote(t *testing.T) {
+	users := new(mocks.UserRepository)
+	users.On("GetByID", uint(1)).Return(&domain.User{ID: 1, Role: "user"}, nil)
+	users.On("GetByID", uint(2)).Return(&domain.User{ID: 2, Role: "vip"}, nil)
+
+	engine, err := fees.NewEngine([]fees.Rule{
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Fixed: "1.1"},
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Role: "vip", Fixed: "0"},
+		{Type: domain.WithdrawTransaction, Percent: "0.1"},
+		{Type: domain.TransferOutTransaction, Currency: "TRX", Percent: "0.5", Min: "0.1", Max: "2"},
+		{Type: domain.TransferOutTransaction, Currency: "USDT", Tiers: []fees.Tier{
+			{UpTo: "100", Fixed: "0.5"},
+			{UpTo: "1000", Percent: "0.25"},
+			{Percent: "0.1"},
+		}},
+	}, users)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		userID uint
+		txType string
+		amount domain.Money
+		want   domain.Money
+	}{
+		{"fixed", 1, domain.WithdrawTransaction, trx(50_000000), trx(1_100000)},
+		{"role wins over generic rule", 2, domain.WithdrawTransaction, trx(50_000000), trx(0)},
+		{"percent for any currency", 1, domain.WithdrawTransaction, domain.NewMoney(300_000000, "USDT"), domain.NewMoney(300000, "USDT")},
+		{"percent rounds up", 1, domain.TransferOutTransaction, trx(30_000001), trx(150001)},
+		{"min", 1, domain.TransferOutTransaction, trx(1_000000), trx(100000)},
+		{"max", 1, domain.TransferOutTransaction, trx(1000_000000), trx(2_000000)},
+		{"first tier", 1, domain.TransferOutTransaction, domain.NewMoney(100_000000, "USDT"), domain.NewMoney(500000, "USDT")},
+		{"middle tier", 1, domain.TransferOutTransaction, domain.NewMoney(400_000000, "USDT"), domain.NewMoney(1_000000, "USDT")},
+		{"last tier", 1, domain.TransferOutTransaction, domain.NewMoney(2000_000000, "USDT"), domain.NewMoney(2_000000, "USDT")},
+		{"no rule", 1, domain.DepositTransaction, trx(10_000000), trx(0)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := engine.Quote(tc.userID, tc.txType, tc.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, fee)
+		})
+	}
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	invalid := []fees.Rule{
+		{Currency: "TRX", Fixed: "1"},
+		{Type: domain.WithdrawTransaction, Fixed: "1"},
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Fixed: "-1"},
+		{Type: domain.WithdrawTransaction, Percent: "101"},
+		{Type: domain.WithdrawTransaction, Percent: "0.00001"},
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Min: "5", Max: "1"},
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Fixed: "1", Tiers: []fees.Tier{{Fixed: "1"}}},
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Tiers: []fees.Tier{{UpTo: "10"}}},
+		{Type: domain.WithdrawTransaction, Currency: "TRX", Tiers: []fees.Tier{{UpTo: "10"}, {UpTo: "5"}, {}}},
+		{Type: domain.WithdrawTransaction, Currency: "DOGE", Fixed: "1"},
+	}
+	for i, r := range invalid {
+		_, err := fees.NewEngine([]fees.Rule{r}, new(mocks.UserRepository))
+		assert.ErrorIs(t, err, fees.ErrInvalidRule, "rule %d", i)
+	}
+
+	_, err := fees.NewEngine([]fees.Rule{{Type: domain.WithdrawTransaction, Role: "vip"}}, nil)
+	assert.ErrorIs(t, err, fees.ErrInvalidRule)
+}
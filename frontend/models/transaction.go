@@ -2,8 +2,9 @@ package models
 
 // Transaction espelha o JSON que sai da /api/transactions
 type Transaction struct {
-	ID     string  `json:"id"`
-	Type   string  `json:"type"`
-	Amount float64 `json:"amount"`
-	Status string  `json:"status"`
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+	Status   string `json:"status"`
 }
@@ -5,6 +5,7 @@ package integration_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 	"github.com/gabrielksneiva/go-financial-transactions/api"
 	"github.com/gabrielksneiva/go-financial-transactions/config"
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/outbox"
 	"github.com/gabrielksneiva/go-financial-transactions/repositories"
 	"github.com/gabrielksneiva/go-financial-transactions/services"
 	"github.com/gabrielksneiva/go-financial-transactions/workers"
@@ -24,16 +26,6 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-type FakeRateLimiter struct{}
-
-func (f *FakeRateLimiter) Allow(userID uint) (bool, error) {
-	return true, nil
-}
-
-func (f *FakeRateLimiter) CheckTransactionRateLimit(userID uint) error {
-	return nil
-}
-
 type ChannelWriter struct {
 	Ch chan domain.Transaction
 }
@@ -55,18 +47,76 @@ func setupTestApp(txChannel chan domain.Transaction) (*api.App, *repositories.Go
 
 	// Usa o fake writer que escreve no canal em vez do Kafka real
 	fakeWriter := &ChannelWriter{Ch: txChannel}
-	fakeLimiter := &FakeRateLimiter{}
+	// sem classes configuradas, nenhuma cota é aplicada
+	fakeLimiter := repositories.NewMemoryRateLimiter(map[string]domain.RateLimit{})
 
-	depositSvc := services.NewDepositService(repo, repo, fakeWriter, fakeLimiter)
-	withdrawSvc := services.NewWithdrawService(repo, repo, fakeWriter, fakeLimiter)
+	// As services gravam no outbox; o relay publica no fake writer
+	go outbox.NewRelay(repo, fakeWriter, 10).Run(context.Background(), 50*time.Millisecond)
+
+	depositSvc := services.NewDepositService(repo, repo, repo, fakeLimiter)
+	withdrawSvc := services.NewWithdrawService(repo, repo, repo, fakeLimiter)
 	statementSvc := services.NewStatementService(repo, repo)
 	userSvc := services.NewUserService(repo)
 
-	app := api.NewApp(depositSvc, withdrawSvc, statementSvc, userSvc)
+	app := api.NewApp(depositSvc, withdrawSvc, statementSvc, userSvc, nil, nil, nil, nil, nil, nil, nil, nil, repo, time.Hour)
 
 	return app, repo
 }
 
+// fakeTron aceita qualquer envio sem falar com um nó
+type fakeTron struct{}
+
+func (fakeTron) ValidateAddress(address string) error { return nil }
+
+func (fakeTron) EstimateFee(tx domain.BlockchainTransaction, asset domain.Asset) (domain.Money, error) {
+	return domain.Zero(asset.Symbol), nil
+}
+
+func (fakeTron) Send(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	return &domain.BlockchainTxResult{TxID: "fake-" + transactionID, ToAddress: tx.ToAddress, Amount: domain.NewMoney(tx.Amount, asset.Symbol)}, nil
+}
+
+func (fakeTron) GetTransactionInfo(txID string) (*domain.BlockchainTxInfo, error) {
+	return nil, domain.ErrBlockchainTxNotFound
+}
+
+func (fakeTron) GetLatestBlockNumber() (int64, error) { return 0, nil }
+
+// startWorkers liga o canal do fake writer ao pool de workers, como o consumer faz com o Kafka
+func startWorkers(ctx context.Context, txChannel <-chan domain.Transaction, repo *repositories.GormRepository) {
+	chains := domain.NewChainRegistry()
+	chains.Register(domain.ChainTron, fakeTron{})
+
+	pool := workers.NewPool(1, repo.GetDB(), chains, repo)
+	jobs := make(chan domain.TransactionJob)
+	acks := make(chan domain.JobAck, 1)
+	pool.Start(jobs, acks)
+
+	// sem Kafka não há offset a confirmar
+	go func() {
+		for range acks {
+		}
+	}()
+
+	go func() {
+		defer pool.Stop(context.Background())
+		var offset int64
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tx := <-txChannel:
+				offset++
+				select {
+				case jobs <- domain.TransactionJob{Transaction: tx, Offset: offset}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+}
+
 func cleanupUserByEmail(repo *repositories.GormRepository, email string) error {
 	user, err := repo.GetByEmail(email)
 	if err != nil || user == nil {
@@ -84,7 +134,7 @@ func TestIntegration_DepositFlow(t *testing.T) {
 
 	ctx := t.Context()
 
-	go workers.StartWorkers(ctx, txChannel, 1, repo.GetDB())
+	startWorkers(ctx, txChannel, repo)
 
 	// Clean up any leftover data from previous runs
 	user, _ := repo.GetByEmail("test@example.com")
@@ -127,8 +177,8 @@ func TestIntegration_DepositFlow(t *testing.T) {
 	assert.NotEmpty(t, token)
 
 	// 3. Deposit
-	depositBody := map[string]float64{
-		"amount": 100,
+	depositBody := map[string]string{
+		"amount": "100",
 	}
 	bodyBytes, _ = json.Marshal(depositBody)
 	req = httptest.NewRequest(http.MethodPost, "/api/deposit", bytes.NewBuffer(bodyBytes))
@@ -158,7 +208,7 @@ func TestIntegration_DepositFlow(t *testing.T) {
 	txs, err := repo.GetTransactionsByUserID(user.ID)
 	require.NoError(t, err)
 	require.Len(t, txs, 1)
-	assert.Equal(t, 100.0, txs[0].Amount)
+	assert.Equal(t, domain.NewMoney(100_000000, "TRX"), txs[0].Amount)
 	assert.Equal(t, "deposit", txs[0].Type)
 
 }
@@ -168,7 +218,7 @@ func TestIntegration_WithdrawFlow(t *testing.T) {
 	app, repo := setupTestApp(txChannel)
 
 	ctx := t.Context()
-	go workers.StartWorkers(ctx, txChannel, 1, repo.GetDB())
+	startWorkers(ctx, txChannel, repo)
 
 	// Cleanup
 	user, _ := repo.GetByEmail("withdraw@example.com")
@@ -209,8 +259,8 @@ func TestIntegration_WithdrawFlow(t *testing.T) {
 	require.NotEmpty(t, token)
 
 	// 3. Deposit R$ 1000
-	depositBody := map[string]float64{
-		"amount": 1000,
+	depositBody := map[string]string{
+		"amount": "1000",
 	}
 	bodyBytes, _ = json.Marshal(depositBody)
 	req = httptest.NewRequest(http.MethodPost, "/api/deposit", bytes.NewBuffer(bodyBytes))
@@ -230,9 +280,12 @@ func TestIntegration_WithdrawFlow(t *testing.T) {
 		return true
 	}, 5*time.Second, 100*time.Millisecond)
 
+	// sem endereço de saque o worker estorna a retirada
+	require.NoError(t, repo.GetDB().Model(user).Update("wallet_address", "TIntegrationWallet").Error)
+
 	// 4. Withdraw R$ 200
-	withdrawBody := map[string]float64{
-		"amount": 200,
+	withdrawBody := map[string]string{
+		"amount": "200",
 	}
 	bodyBytes, _ = json.Marshal(withdrawBody)
 	req = httptest.NewRequest(http.MethodPost, "/api/withdraw", bytes.NewBuffer(bodyBytes))
@@ -244,22 +297,22 @@ func TestIntegration_WithdrawFlow(t *testing.T) {
 
 	// Aguarda processamento
 	require.Eventually(t, func() bool {
-		balance, err := repo.GetBalance(user.ID)
+		balance, err := repo.GetBalance(user.ID, domain.DefaultCurrency)
 		if err != nil {
 			return false
 		}
-		return balance.Amount == 800
+		return balance.Units == 800_000000
 	}, 5*time.Second, 100*time.Millisecond)
 
-	balance, err := repo.GetBalance(user.ID)
+	balance, err := repo.GetBalance(user.ID, domain.DefaultCurrency)
 	require.NoError(t, err)
-	assert.Equal(t, 800.0, balance.Amount)
+	assert.Equal(t, int64(800_000000), balance.Units)
 	assert.Equal(t, user.ID, balance.UserID)
-	assert.Equal(t, 800.0, balance.Amount)
+	assert.Equal(t, int64(800_000000), balance.Units)
 
 	// 5. Tenta sacar R$ 1000 (deve falhar por saldo insuficiente)
-	overdraftBody := map[string]float64{
-		"amount": 1000,
+	overdraftBody := map[string]string{
+		"amount": "1000",
 	}
 	bodyBytes, _ = json.Marshal(overdraftBody)
 	req = httptest.NewRequest(http.MethodPost, "/api/withdraw", bytes.NewBuffer(bodyBytes))
@@ -275,7 +328,7 @@ func TestIntegration_StatementFlow(t *testing.T) {
 	app, repo := setupTestApp(txChannel)
 
 	ctx := t.Context()
-	go workers.StartWorkers(ctx, txChannel, 1, repo.GetDB())
+	startWorkers(ctx, txChannel, repo)
 
 	// Cleanup antes do registro
 	_ = cleanupUserByEmail(repo, "statement@example.com")
@@ -316,8 +369,8 @@ func TestIntegration_StatementFlow(t *testing.T) {
 	require.NoError(t, err)
 
 	// 3. Deposit R$ 500
-	depositBody := map[string]float64{
-		"amount": 500,
+	depositBody := map[string]string{
+		"amount": "500",
 	}
 	bodyBytes, _ = json.Marshal(depositBody)
 	req = httptest.NewRequest(http.MethodPost, "/api/deposit", bytes.NewBuffer(bodyBytes))
@@ -328,13 +381,16 @@ func TestIntegration_StatementFlow(t *testing.T) {
 	require.Equal(t, http.StatusAccepted, res.StatusCode)
 
 	require.Eventually(t, func() bool {
-		balance, err := repo.GetBalance(user.ID)
-		return err == nil && balance.Amount >= 500
+		balance, err := repo.GetBalance(user.ID, domain.DefaultCurrency)
+		return err == nil && balance.Units >= 500_000000
 	}, 5*time.Second, 100*time.Millisecond)
 
+	// sem endereço de saque o worker estorna a retirada
+	require.NoError(t, repo.GetDB().Model(user).Update("wallet_address", "TIntegrationWallet").Error)
+
 	// 4. Withdraw R$ 200
-	withdrawBody := map[string]float64{
-		"amount": 200,
+	withdrawBody := map[string]string{
+		"amount": "200",
 	}
 	bodyBytes, _ = json.Marshal(withdrawBody)
 	req = httptest.NewRequest(http.MethodPost, "/api/withdraw", bytes.NewBuffer(bodyBytes))
@@ -372,9 +428,9 @@ func TestIntegration_StatementFlow(t *testing.T) {
 	tx0 := txs[0].(map[string]interface{})
 	tx1 := txs[1].(map[string]interface{})
 
-	assert.Equal(t, 500.0, tx0["amount"].(float64))
+	assert.Equal(t, "500.000000", tx0["amount"].(string))
 	assert.Equal(t, "deposit", tx0["type"].(string))
-	assert.Equal(t, -200.0, tx1["amount"].(float64))
+	assert.Equal(t, "200.000000", tx1["amount"].(string))
 	assert.Equal(t, "withdrawal", tx1["type"].(string))
 
 }
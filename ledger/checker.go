@@ -0,0 +1,145 @@
+package ledger
+
+import (
+	"context"
+	"log"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+)
+
+// Discrepancy aponta uma conta cujo saldo em cache diverge da soma das postings
+type Discrepancy struct {
+	Account  string
+	UserID   *uint
+	Cached   d.Money
+	Computed d.Money
+}
+
+type Report struct {
+	Discrepancies     []Discrepancy
+	UnbalancedEntries []string
+}
+
+func (r Report) OK() bool {
+	return len(r.Discrepancies) == 0 && len(r.UnbalancedEntries) == 0
+}
+
+type Checker struct {
+	db *gorm.DB
+}
+
+func NewChecker(db *gorm.DB) *Checker {
+	return &Checker{db: db}
+}
+
+// Check recalcula os saldos a partir das postings e compara com os caches
+// (ledger_accounts.balance_amount, balances.amount e balances.held).
+func (c *Checker) Check() (*Report, error) {
+	var sums []struct {
+		AccountID uint
+		Total     int64
+	}
+	if err := c.db.Model(&d.Posting{}).
+		Select("account_id, COALESCE(SUM(amount), 0) AS total").
+		Group("account_id").
+		Scan(&sums).Error; err != nil {
+		return nil, err
+	}
+	computed := make(map[uint]int64, len(sums))
+	for _, s := range sums {
+		computed[s.AccountID] = s.Total
+	}
+
+	var accounts []d.LedgerAccount
+	if err := c.db.Order("id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+
+	var balances []d.Balance
+	if err := c.db.Find(&balances).Error; err != nil {
+		return nil, err
+	}
+	// saldos em cache indexados pelo código da conta do usuário ("user:42:TRX") ou da
+	// reserva ("hold:42:TRX")
+	cachedBalances := make(map[string]d.Money, 2*len(balances))
+	for _, b := range balances {
+		cachedBalances[UserAccount(b.UserID, b.Currency)] = b.Amount()
+		cachedBalances[HoldAccount(b.UserID, b.Currency)] = b.HeldAmount()
+	}
+
+	report := &Report{}
+	seenAccounts := make(map[string]bool)
+	for _, acc := range accounts {
+		total := d.NewMoney(computed[acc.ID], acc.Balance.Currency)
+
+		if acc.Balance.Units != total.Units {
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Account: acc.Code, UserID: acc.UserID, Cached: acc.Balance, Computed: total,
+			})
+			continue
+		}
+
+		if acc.UserID != nil {
+			seenAccounts[acc.Code] = true
+			if cached, ok := cachedBalances[acc.Code]; !ok || cached.Units != total.Units {
+				report.Discrepancies = append(report.Discrepancies, Discrepancy{
+					Account: acc.Code, UserID: acc.UserID, Cached: d.NewMoney(cached.Units, acc.Balance.Currency), Computed: total,
+				})
+			}
+		}
+	}
+
+	// Saldos sem nenhuma conta no razão também são inconsistentes
+	for _, b := range balances {
+		for _, code := range []string{UserAccount(b.UserID, b.Currency), HoldAccount(b.UserID, b.Currency)} {
+			cached := cachedBalances[code]
+			if seenAccounts[code] || cached.Units == 0 {
+				continue
+			}
+			id := b.UserID
+			report.Discrepancies = append(report.Discrepancies, Discrepancy{
+				Account: code, UserID: &id,
+				Cached: cached, Computed: d.Zero(b.Currency),
+			})
+		}
+	}
+
+	if err := c.db.Model(&d.Posting{}).
+		Select("journal_entry_id").
+		Group("journal_entry_id").
+		Having("SUM(amount) <> 0").
+		Pluck("journal_entry_id", &report.UnbalancedEntries).Error; err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Run executa o Check periodicamente, logando as divergências encontradas
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Ledger checker encerrado")
+			return
+		case <-ticker.C:
+			report, err := c.Check()
+			if err != nil {
+				log.Printf("❌ Ledger checker: erro ao verificar razão: %v", err)
+				continue
+			}
+			for _, disc := range report.Discrepancies {
+				log.Printf("⚠️ Ledger checker: conta %s com saldo %s diverge das postings (%s)", disc.Account, disc.Cached, disc.Computed)
+			}
+			for _, id := range report.UnbalancedEntries {
+				log.Printf("⚠️ Ledger checker: lançamento %s não está balanceado", id)
+			}
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Contas de sistema usadas como contrapartida dos lançamentos de usuário
+const (
+	AccountHotWallet = "hot_wallet"
+	AccountClearing  = "clearing"
+	AccountFees      = "fees"
+	// AccountOpeningEquity é a contrapartida dos saldos que já existiam antes do razão
+	AccountOpeningEquity = "opening_equity"
+)
+
+// DescriptionHold identifica o lançamento que reserva o valor de um saque
+const DescriptionHold = "withdraw_held"
+
+// DescriptionFee identifica o lançamento da tarifa, separado do lançamento do valor
+const DescriptionFee = d.FeeTransaction
+
+var ErrUnbalancedEntry = errors.New("journal entry is not balanced")
+
+// Leg é uma perna de um lançamento: um valor com sinal aplicado a uma conta
+type Leg struct {
+	Code   string
+	Type   string
+	UserID *uint
+	Amount d.Money
+}
+
+func UserAccount(userID uint, currency string) string {
+	return fmt.Sprintf("%s:%d:%s", d.LedgerAccountUser, userID, currency)
+}
+
+func SystemAccount(name, currency string) string {
+	return fmt.Sprintf("%s:%s:%s", d.LedgerAccountSystem, name, currency)
+}
+
+func UserLeg(userID uint, amount d.Money) Leg {
+	id := userID
+	return Leg{Code: UserAccount(userID, amount.Currency), Type: d.LedgerAccountUser, UserID: &id, Amount: amount}
+}
+
+// HoldAccount é a reserva do usuário em uma moeda, espelhada em balances.held
+func HoldAccount(userID uint, currency string) string {
+	return fmt.Sprintf("%s:%d:%s", d.LedgerAccountHold, userID, currency)
+}
+
+func HoldLeg(userID uint, amount d.Money) Leg {
+	id := userID
+	return Leg{Code: HoldAccount(userID, amount.Currency), Type: d.LedgerAccountHold, UserID: &id, Amount: amount}
+}
+
+func SystemLeg(name string, amount d.Money) Leg {
+	return Leg{Code: SystemAccount(name, amount.Currency), Type: d.LedgerAccountSystem, Amount: amount}
+}
+
+// Post grava um lançamento balanceado e atualiza os saldos em cache (contas e domain.Balance).
+// Deve ser chamado dentro de uma transação de banco.
+func Post(txDB *gorm.DB, transactionID, description string, legs ...Leg) (*d.JournalEntry, error) {
+	return post(txDB, transactionID, description, true, legs...)
+}
+
+// OpeningBalance lança no razão um saldo gravado antes dele existir, contra o patrimônio de
+// abertura. balances.amount já tem o valor, então só as contas do razão são atualizadas.
+func OpeningBalance(txDB *gorm.DB, balance d.Balance) error {
+	amount := balance.Amount()
+	_, err := post(txDB, fmt.Sprintf("opening:%d:%s", balance.UserID, balance.Currency), "opening_balance", false,
+		UserLeg(balance.UserID, amount),
+		SystemLeg(AccountOpeningEquity, amount.Neg()),
+	)
+	return err
+}
+
+func post(txDB *gorm.DB, transactionID, description string, applyBalances bool, legs ...Leg) (*d.JournalEntry, error) {
+	if len(legs) < 2 {
+		return nil, fmt.Errorf("%w: at least two legs are required", ErrUnbalancedEntry)
+	}
+
+	total := d.Zero(legs[0].Amount.Currency)
+	for _, leg := range legs {
+		var err error
+		if total, err = total.Add(leg.Amount); err != nil {
+			return nil, err
+		}
+	}
+	if !total.IsZero() {
+		return nil, fmt.Errorf("%w: postings sum to %s", ErrUnbalancedEntry, total)
+	}
+
+	entry := d.JournalEntry{
+		ID:            uuid.New().String(),
+		TransactionID: transactionID,
+		Description:   description,
+		CreatedAt:     time.Now(),
+	}
+	if err := txDB.Create(&entry).Error; err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*d.LedgerAccount, len(legs))
+	for i, leg := range legs {
+		account, err := ensureAccount(txDB, leg)
+		if err != nil {
+			return nil, err
+		}
+		accounts[i] = account
+	}
+
+	// as contas são atualizadas em ordem crescente de ID: Withdraw e Refund do mesmo usuário
+	// rodando ao mesmo tempo travam as linhas na mesma ordem e não entram em deadlock
+	order := make([]int, len(legs))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return accounts[order[a]].ID < accounts[order[b]].ID })
+
+	for _, i := range order {
+		leg, account := legs[i], accounts[i]
+		posting := d.Posting{
+			JournalEntryID: entry.ID,
+			AccountID:      account.ID,
+			Amount:         leg.Amount,
+			CreatedAt:      entry.CreatedAt,
+		}
+		if err := txDB.Create(&posting).Error; err != nil {
+			return nil, err
+		}
+		entry.Postings = append(entry.Postings, posting)
+
+		if err := txDB.Model(&d.LedgerAccount{}).
+			Where("id = ?", account.ID).
+			Update("balance_amount", gorm.Expr("balance_amount + ?", leg.Amount.Units)).Error; err != nil {
+			return nil, err
+		}
+
+		if applyBalances && leg.UserID != nil {
+			if err := applyToBalance(txDB, *leg.UserID, leg.Type, leg.Amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &entry, nil
+}
+
+// Deposit credita o usuário contra a hot wallet, que recebeu os fundos
+func Deposit(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, d.DepositTransaction,
+		UserLeg(tx.UserID, tx.Amount),
+		SystemLeg(AccountHotWallet, tx.Amount.Neg()),
+	)
+	return err
+}
+
+// Withdraw debita o usuário e segura o valor em clearing até o envio on-chain
+func Withdraw(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, d.WithdrawTransaction,
+		UserLeg(tx.UserID, tx.Amount.Neg()),
+		SystemLeg(AccountClearing, tx.Amount),
+	)
+	return err
+}
+
+// Transfer debita o remetente e credita o destinatário de uma transferência interna, sem
+// conta de sistema no meio
+func Transfer(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, d.TransferOutTransaction,
+		UserLeg(tx.UserID, tx.Amount.Neg()),
+		UserLeg(tx.CounterpartyID, tx.Amount),
+	)
+	return err
+}
+
+// SettleWithdraw move o valor de clearing para a hot wallet após as confirmações on-chain
+func SettleWithdraw(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, "withdraw_settled",
+		SystemLeg(AccountClearing, tx.Amount.Neg()),
+		SystemLeg(AccountHotWallet, tx.Amount),
+	)
+	return err
+}
+
+// Refund devolve ao usuário um saque que falhou, retirando o valor de clearing e a tarifa da
+// conta de tarifas
+func Refund(txDB *gorm.DB, refundID string, tx d.Transaction) error {
+	legs := []Leg{
+		SystemLeg(AccountClearing, tx.Amount.Neg()),
+		UserLeg(tx.UserID, tx.Total()),
+	}
+	if tx.Fee != 0 {
+		legs = append(legs, SystemLeg(AccountFees, tx.FeeAmount().Neg()))
+	}
+	_, err := Post(txDB, refundID, "refund", legs...)
+	return err
+}
+
+// ChargeFee debita do saldo disponível a tarifa da transação e a credita na conta de tarifas
+func ChargeFee(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, DescriptionFee,
+		UserLeg(tx.UserID, tx.FeeAmount().Neg()),
+		SystemLeg(AccountFees, tx.FeeAmount()),
+	)
+	return err
+}
+
+// CaptureHeldFee leva a tarifa reservada junto com o saque para a conta de tarifas
+func CaptureHeldFee(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, DescriptionFee,
+		HoldLeg(tx.UserID, tx.FeeAmount().Neg()),
+		SystemLeg(AccountFees, tx.FeeAmount()),
+	)
+	return err
+}
+
+// Hold move o valor de um saque, com a tarifa, do saldo disponível para a reserva do usuário
+func Hold(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, DescriptionHold,
+		UserLeg(tx.UserID, tx.Total().Neg()),
+		HoldLeg(tx.UserID, tx.Total()),
+	)
+	return err
+}
+
+// ReleaseHold devolve ao saldo disponível o valor reservado de um saque que não será enviado
+func ReleaseHold(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, "withdraw_released",
+		HoldLeg(tx.UserID, tx.Total().Neg()),
+		UserLeg(tx.UserID, tx.Total()),
+	)
+	return err
+}
+
+// CaptureHold leva o valor reservado para clearing, como Withdraw faria com o saldo disponível
+func CaptureHold(txDB *gorm.DB, tx d.Transaction) error {
+	_, err := Post(txDB, tx.ID, d.WithdrawTransaction,
+		HoldLeg(tx.UserID, tx.Amount.Neg()),
+		SystemLeg(AccountClearing, tx.Amount),
+	)
+	return err
+}
+
+func ensureAccount(txDB *gorm.DB, leg Leg) (*d.LedgerAccount, error) {
+	account := d.LedgerAccount{
+		Code:    leg.Code,
+		Type:    leg.Type,
+		UserID:  leg.UserID,
+		Balance: d.Zero(leg.Amount.Currency),
+	}
+	if err := txDB.Where("code = ?", leg.Code).FirstOrCreate(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// applyToBalance atualiza balances.amount para contas de usuário e balances.held para reservas
+func applyToBalance(txDB *gorm.DB, userID uint, accountType string, amount d.Money) error {
+	balance := d.NewBalance(userID, amount)
+	column, other := "amount", "held"
+	if accountType == d.LedgerAccountHold {
+		balance.Held, balance.Units = balance.Units, 0
+		column, other = "held", "amount"
+	}
+
+	return txDB.Omit(other).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "currency"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			column: gorm.Expr("balances." + column + " + EXCLUDED." + column),
+		}),
+	}).Create(&balance).Error
+}
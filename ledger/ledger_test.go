@@ -0,0 +1,191 @@
+package ledger_test
+
+import (
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&domain.Balance{}, &domain.LedgerAccount{}, &domain.JournalEntry{}, &domain.Posting{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func trx(units int64) domain.Money {
+	return domain.NewMoney(units, "TRX")
+}
+
+func TestPost_RejectsUnbalancedEntry(t *testing.T) {
+	db := setupTestDB(t)
+
+	_, err := ledger.Post(db, "tx-1", "broken",
+		ledger.UserLeg(1, trx(100)),
+		ledger.SystemLeg(ledger.AccountHotWallet, trx(-99)),
+	)
+	assert.ErrorIs(t, err, ledger.ErrUnbalancedEntry)
+
+	_, err = ledger.Post(db, "tx-1", "single", ledger.UserLeg(1, trx(100)))
+	assert.ErrorIs(t, err, ledger.ErrUnbalancedEntry)
+}
+
+func TestLedger_DepositWithdrawRefund(t *testing.T) {
+	db := setupTestDB(t)
+
+	deposit := domain.Transaction{ID: "dep-1", UserID: 7, Amount: trx(500_000000)}
+	withdraw := domain.Transaction{ID: "wd-1", UserID: 7, Amount: trx(200_000000)}
+
+	assert.NoError(t, ledger.Deposit(db, deposit))
+	assert.NoError(t, ledger.Withdraw(db, withdraw))
+	assert.NoError(t, ledger.Refund(db, "refund-1", withdraw))
+
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ?", 7).Error)
+	assert.Equal(t, trx(500_000000), balance.Amount())
+
+	var clearing domain.LedgerAccount
+	assert.NoError(t, db.First(&clearing, "code = ?", ledger.SystemAccount(ledger.AccountClearing, "TRX")).Error)
+	assert.True(t, clearing.Balance.IsZero())
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestPost_UpdatesAccountsInAscendingIDOrder(t *testing.T) {
+	db := setupTestDB(t)
+
+	withdraw := domain.Transaction{ID: "wd-1", UserID: 7, Amount: trx(200_000000)}
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-1", UserID: 7, Amount: trx(500_000000)}))
+	assert.NoError(t, ledger.Withdraw(db, withdraw))
+	// Refund lista clearing antes do usuário, o contrário de Withdraw
+	assert.NoError(t, ledger.Refund(db, "refund-1", withdraw))
+
+	for _, id := range []string{"wd-1", "refund-1"} {
+		var entry domain.JournalEntry
+		assert.NoError(t, db.First(&entry, "transaction_id = ?", id).Error)
+		var postings []domain.Posting
+		assert.NoError(t, db.Order("id").Find(&postings, "journal_entry_id = ?", entry.ID).Error)
+		assert.Len(t, postings, 2)
+		assert.Less(t, postings[0].AccountID, postings[1].AccountID, id)
+	}
+}
+
+func TestLedger_BalancesPerCurrency(t *testing.T) {
+	db := setupTestDB(t)
+
+	usdt := domain.NewMoney(25_000000, "USDT")
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-trx", UserID: 9, Amount: trx(10_000000)}))
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-usdt", UserID: 9, Amount: usdt}))
+	assert.NoError(t, ledger.Withdraw(db, domain.Transaction{ID: "wd-usdt", UserID: 9, Amount: domain.NewMoney(5_000000, "USDT")}))
+
+	var balances []domain.Balance
+	assert.NoError(t, db.Order("currency").Find(&balances, "user_id = ?", 9).Error)
+	assert.Len(t, balances, 2)
+	assert.Equal(t, trx(10_000000), balances[0].Amount())
+	assert.Equal(t, domain.NewMoney(20_000000, "USDT"), balances[1].Amount())
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestLedger_SettleWithdraw(t *testing.T) {
+	db := setupTestDB(t)
+
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-1", UserID: 1, Amount: trx(10)}))
+	assert.NoError(t, ledger.Withdraw(db, domain.Transaction{ID: "wd-1", UserID: 1, Amount: trx(4)}))
+	assert.NoError(t, ledger.SettleWithdraw(db, domain.Transaction{ID: "wd-1", UserID: 1, Amount: trx(4)}))
+
+	var hot domain.LedgerAccount
+	assert.NoError(t, db.First(&hot, "code = ?", ledger.SystemAccount(ledger.AccountHotWallet, "TRX")).Error)
+	assert.Equal(t, int64(-6), hot.Balance.Units)
+
+	var entries int64
+	db.Model(&domain.JournalEntry{}).Count(&entries)
+	assert.Equal(t, int64(3), entries)
+}
+
+func TestChecker_FlagsTamperedBalance(t *testing.T) {
+	db := setupTestDB(t)
+
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-1", UserID: 3, Amount: trx(100)}))
+	assert.NoError(t, db.Model(&domain.Balance{}).Where("user_id = ?", 3).Update("amount", 1000).Error)
+
+	// saldo sem nenhuma conta no razão
+	orphan := domain.NewBalance(4, trx(50))
+	assert.NoError(t, db.Create(&orphan).Error)
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.False(t, report.OK())
+	assert.Len(t, report.Discrepancies, 2)
+
+	byAccount := map[string]ledger.Discrepancy{}
+	for _, disc := range report.Discrepancies {
+		byAccount[disc.Account] = disc
+	}
+	assert.Equal(t, int64(1000), byAccount[ledger.UserAccount(3, "TRX")].Cached.Units)
+	assert.Equal(t, int64(100), byAccount[ledger.UserAccount(3, "TRX")].Computed.Units)
+	assert.Equal(t, int64(0), byAccount[ledger.UserAccount(4, "TRX")].Computed.Units)
+}
+
+func TestChecker_FlagsUnbalancedEntry(t *testing.T) {
+	db := setupTestDB(t)
+
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-1", UserID: 5, Amount: trx(100)}))
+
+	var posting domain.Posting
+	assert.NoError(t, db.First(&posting).Error)
+	assert.NoError(t, db.Model(&posting).Update("amount", 1).Error)
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.Len(t, report.UnbalancedEntries, 1)
+	assert.Equal(t, posting.JournalEntryID, report.UnbalancedEntries[0])
+}
+
+func TestLedger_HoldCaptureRelease(t *testing.T) {
+	db := setupTestDB(t)
+
+	assert.NoError(t, ledger.Deposit(db, domain.Transaction{ID: "dep-1", UserID: 3, Amount: trx(10)}))
+	assert.NoError(t, ledger.Hold(db, domain.Transaction{ID: "wd-1", UserID: 3, Amount: trx(4)}))
+	assert.NoError(t, ledger.Hold(db, domain.Transaction{ID: "wd-2", UserID: 3, Amount: trx(5)}))
+
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ?", 3).Error)
+	assert.Equal(t, trx(1), balance.Amount())
+	assert.Equal(t, trx(9), balance.HeldAmount())
+
+	assert.NoError(t, ledger.CaptureHold(db, domain.Transaction{ID: "wd-1", UserID: 3, Amount: trx(4)}))
+	assert.NoError(t, ledger.ReleaseHold(db, domain.Transaction{ID: "wd-2", UserID: 3, Amount: trx(5)}))
+
+	assert.NoError(t, db.First(&balance, "user_id = ?", 3).Error)
+	assert.Equal(t, trx(6), balance.Amount())
+	assert.True(t, balance.HeldAmount().IsZero())
+
+	var clearing domain.LedgerAccount
+	assert.NoError(t, db.First(&clearing, "code = ?", ledger.SystemAccount(ledger.AccountClearing, "TRX")).Error)
+	assert.Equal(t, int64(4), clearing.Balance.Units)
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+
+	// a reserva em cache também é conferida com as postings
+	assert.NoError(t, db.Model(&domain.Balance{}).Where("user_id = ?", 3).Update("held", 2).Error)
+	report, err = ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	if assert.Len(t, report.Discrepancies, 1) {
+		assert.Equal(t, ledger.HoldAccount(3, "TRX"), report.Discrepancies[0].Account)
+	}
+}
@@ -0,0 +1,160 @@
+package limits
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+var ErrInvalidRule = errors.New("invalid limit rule")
+
+// Rule é uma regra do arquivo de limites. Currency é obrigatória; Role e KYCTier ausentes valem
+// para qualquer papel e nível. Valores são decimais na moeda da regra ("5000"); vazio é sem teto.
+type Rule struct {
+	Role              string `json:"role"`
+	KYCTier           *int   `json:"kyc_tier"`
+	Currency          string `json:"currency"`
+	MaxWithdrawal     string `json:"max_withdrawal"`
+	DailyWithdrawal   string `json:"daily_withdrawal"`
+	MonthlyWithdrawal string `json:"monthly_withdrawal"`
+	DailyDeposit      string `json:"daily_deposit"`
+}
+
+type rule struct {
+	role    string
+	kycTier *int
+	limits  d.Limits
+}
+
+// Policy escolhe a regra mais específica para o usuário: nível de KYC vence papel, que vence a
+// regra genérica, e entre regras iguais vale a primeira do arquivo. Sem regra não há teto.
+// Um override do usuário substitui os campos que define.
+type Policy struct {
+	rules     []rule
+	users     d.UserRepository
+	overrides d.LimitRepository
+}
+
+var _ d.LimitPolicy = &Policy{}
+
+// LoadRules lê o arquivo JSON com a lista de regras
+func LoadRules(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules []Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	return rules, nil
+}
+
+// NewPolicy valida as regras; overrides pode ser nil quando não há tetos por usuário
+func NewPolicy(rules []Rule, users d.UserRepository, overrides d.LimitRepository) (*Policy, error) {
+	if users == nil {
+		return nil, fmt.Errorf("%w: the user repository is required", ErrInvalidRule)
+	}
+	p := &Policy{users: users, overrides: overrides}
+	for i, r := range rules {
+		compiled, err := compile(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %d: %v", ErrInvalidRule, i, err)
+		}
+		p.rules = append(p.rules, compiled)
+	}
+	return p, nil
+}
+
+func compile(r Rule) (rule, error) {
+	out := rule{
+		role:    strings.TrimSpace(r.Role),
+		kycTier: r.KYCTier,
+		limits:  d.Limits{Currency: strings.ToUpper(strings.TrimSpace(r.Currency))},
+	}
+	if out.limits.Currency == "" {
+		return rule{}, errors.New("currency is required")
+	}
+	if out.kycTier != nil && *out.kycTier < 0 {
+		return rule{}, errors.New("kyc_tier must not be negative")
+	}
+
+	fields := []struct {
+		value string
+		dst   *int64
+	}{
+		{r.MaxWithdrawal, &out.limits.MaxWithdrawal},
+		{r.DailyWithdrawal, &out.limits.DailyWithdrawal},
+		{r.MonthlyWithdrawal, &out.limits.MonthlyWithdrawal},
+		{r.DailyDeposit, &out.limits.DailyDeposit},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		m, err := d.ParseMoney(f.value, out.limits.Currency)
+		if err != nil {
+			return rule{}, err
+		}
+		if !m.IsPositive() {
+			return rule{}, fmt.Errorf("limit %q must be positive", f.value)
+		}
+		*f.dst = m.Units
+	}
+	return out, nil
+}
+
+func (p *Policy) LimitsFor(userID uint, currency string) (d.Limits, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = d.DefaultCurrency
+	}
+
+	user, err := p.users.GetByID(userID)
+	if err != nil {
+		return d.Limits{}, err
+	}
+
+	limits := d.Limits{Currency: currency}
+	bestScore := -1
+	for _, r := range p.rules {
+		if r.limits.Currency != currency ||
+			r.role != "" && r.role != user.Role ||
+			r.kycTier != nil && *r.kycTier != user.KYCTier {
+			continue
+		}
+
+		score := 0
+		if r.kycTier != nil {
+			score += 2
+		}
+		if r.role != "" {
+			score++
+		}
+		if score > bestScore {
+			limits, bestScore = r.limits, score
+		}
+	}
+
+	if p.overrides == nil {
+		return limits, nil
+	}
+	o, err := p.overrides.GetLimitOverride(userID, currency)
+	if err != nil || o == nil {
+		return limits, err
+	}
+	apply := func(dst *int64, v *int64) {
+		if v != nil {
+			*dst = *v
+		}
+	}
+	apply(&limits.MaxWithdrawal, o.MaxWithdrawal)
+	apply(&limits.DailyWithdrawal, o.DailyWithdrawal)
+	apply(&limits.MonthlyWithdrawal, o.MonthlyWithdrawal)
+	apply(&limits.DailyDeposit, o.DailyDeposit)
+	return limits, nil
+}
@@ -0,0 +1,75 @@
+package limits_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/limits"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+)
+
+func tier(n int) *int { return &n }
+
+func units(n int64) *int64 { return &n }
+
+func TestPolicy_LimitsFor(t *testing.T) {
+	users := new(mocks.UserRepository)
+	users.On("GetByID", uint(1)).Return(&domain.User{ID: 1, Role: "user"}, nil)
+	users.On("GetByID", uint(2)).Return(&domain.User{ID: 2, Role: "user", KYCTier: 2}, nil)
+	users.On("GetByID", uint(3)).Return(&domain.User{ID: 3, Role: "vip", KYCTier: 2}, nil)
+	users.On("GetByID", uint(4)).Return(&domain.User{ID: 4, Role: "vip"}, nil)
+
+	overrides := new(mocks.LimitRepository)
+	overrides.On("GetLimitOverride", uint(4), "TRX").Return(&domain.LimitOverride{UserID: 4, Currency: "TRX", DailyWithdrawal: units(0)}, nil)
+	overrides.On("GetLimitOverride", uint(4), "USDT").Return(nil, nil)
+	overrides.On("GetLimitOverride", uint(1), "TRX").Return(nil, nil)
+	overrides.On("GetLimitOverride", uint(2), "TRX").Return(nil, nil)
+	overrides.On("GetLimitOverride", uint(3), "TRX").Return(nil, nil)
+
+	policy, err := limits.NewPolicy([]limits.Rule{
+		{Currency: "TRX", MaxWithdrawal: "100", DailyWithdrawal: "500"},
+		{Currency: "trx", Role: "vip", DailyWithdrawal: "5000", DailyDeposit: "10000"},
+		{Currency: "TRX", KYCTier: tier(2), DailyWithdrawal: "2000", MonthlyWithdrawal: "20000"},
+	}, users, overrides)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		userID   uint
+		currency string
+		want     domain.Limits
+	}{
+		{"generic rule", 1, "TRX", domain.Limits{Currency: "TRX", MaxWithdrawal: 100_000000, DailyWithdrawal: 500_000000}},
+		{"kyc tier", 2, "TRX", domain.Limits{Currency: "TRX", DailyWithdrawal: 2000_000000, MonthlyWithdrawal: 20000_000000}},
+		{"kyc tier wins over role", 3, "TRX", domain.Limits{Currency: "TRX", DailyWithdrawal: 2000_000000, MonthlyWithdrawal: 20000_000000}},
+		{"override replaces only its fields", 4, "TRX", domain.Limits{Currency: "TRX", DailyDeposit: 10000_000000}},
+		{"no rule", 4, "USDT", domain.Limits{Currency: "USDT"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := policy.LimitsFor(tc.userID, tc.currency)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewPolicy_InvalidRules(t *testing.T) {
+	invalid := []limits.Rule{
+		{DailyWithdrawal: "10"},
+		{Currency: "TRX", DailyWithdrawal: "-1"},
+		{Currency: "TRX", DailyWithdrawal: "0"},
+		{Currency: "TRX", KYCTier: tier(-1)},
+		{Currency: "DOGE", DailyDeposit: "1"},
+	}
+	for i, r := range invalid {
+		_, err := limits.NewPolicy([]limits.Rule{r}, new(mocks.UserRepository), nil)
+		assert.ErrorIs(t, err, limits.ErrInvalidRule, "rule %d", i)
+	}
+
+	_, err := limits.NewPolicy(nil, nil, nil)
+	assert.ErrorIs(t, err, limits.ErrInvalidRule)
+}
@@ -4,6 +4,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"log"
 	"os"
 	"os/signal"
 	"sync"
@@ -15,7 +16,11 @@ import (
 	"github.com/gabrielksneiva/go-financial-transactions/consumer"
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gabrielksneiva/go-financial-transactions/frontend"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+	"github.com/gabrielksneiva/go-financial-transactions/outbox"
 	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/scanner"
+	"github.com/gabrielksneiva/go-financial-transactions/scheduler"
 	"github.com/gabrielksneiva/go-financial-transactions/workers"
 	"github.com/gofiber/fiber/v2"
 )
@@ -58,20 +63,79 @@ func RunApp() {
 	}()
 
 	// 7) Start consumer & workers
-	transactions := make(chan domain.Transaction, 100)
-	go consumer.InitConsumer(ctx, transactions, cfg.KafkaBroker, cfg.KafkaTopic, cfg.KafkaGroupID)
-	tronClient := client.NewTronClient()
+	transactions := make(chan domain.TransactionJob, 100)
+	acks := make(chan domain.JobAck, 100)
+	var dlq consumer.DeadLetterPublisher
+	if app.DeadLetterWriter != nil {
+		dlq = app.DeadLetterWriter
+	}
+	go consumer.InitConsumer(ctx, transactions, acks, cfg.KafkaBroker, cfg.KafkaTopic, cfg.KafkaGroupID, dlq)
+	txSigner, err := config.SetupSigner(cfg)
+	if err != nil {
+		log.Fatalf("❌ Erro ao configurar signer: %v", err)
+	}
+	tronClient := client.NewTronClient(txSigner)
+	if cfg.TronWallet != "" {
+		// após uma rotação, TRON_FROM_ADDR precisa apontar para a chave nova
+		if _, err := tronClient.ActiveAddress(); err != nil {
+			log.Fatalf("❌ TRON_FROM_ADDR não é o endereço da chave ativa do signer; atualize-o após uma rotação: %v", err)
+		}
+	}
 	repo := repositories.NewGormRepository(app.DB)
-	go workers.Worker(ctx, 4, transactions, app.DB, tronClient, repo)
+
+	// Cada ativo é enviado e confirmado pelo driver da sua rede
+	chains := domain.NewChainRegistry()
+	chains.Register(domain.ChainTron, tronClient)
+	if cfg.EthRPCURL != "" {
+		ethClient, err := client.DialEVMClient(cfg.EthRPCURL, txSigner, "ETH")
+		if err != nil {
+			log.Fatalf("❌ Erro ao conectar na rede Ethereum: %v", err)
+		}
+		chains.Register(domain.ChainEthereum, ethClient)
+	}
+
+	// Cada usuário tem um worker fixo: ordem preservada por usuário, usuários diferentes em paralelo
+	pool := workers.NewPool(cfg.WorkerPoolSize, app.DB, chains, repo)
+	pool.Retry = workers.RetryPolicy{
+		MaxAttempts:    cfg.RetryMaxAttempts,
+		InitialBackoff: cfg.RetryInitialBackoff,
+		MaxBackoff:     cfg.RetryMaxBackoff,
+	}
+	pool.Start(transactions, acks)
+	go ledger.NewChecker(app.DB).Run(ctx, cfg.LedgerCheckInterval)
+	go outbox.NewRelay(repo, app.KafkaWriter, cfg.OutboxBatchSize).Run(ctx, cfg.OutboxPollInterval)
+	// Só uma réplica por vez executa os agendamentos (liderança no banco)
+	hostname, _ := os.Hostname()
+	holder := fmt.Sprintf("%s:%d", hostname, os.Getpid())
+	go scheduler.NewScheduler(repo, repo, app.API.Handlers.Schedules, holder, cfg.SchedulerLeaseTTL).
+		Run(ctx, cfg.SchedulerPollInterval)
+	confirmations := map[string]int{
+		domain.ChainTron:     cfg.TronConfirmations,
+		domain.ChainEthereum: cfg.EthConfirmations,
+	}
+	go workers.NewConfirmationTracker(app.DB, chains, repo, confirmations, cfg.ConfirmationDropTimeout).
+		Run(ctx, cfg.ConfirmationPollInterval)
+	if cfg.DepositXpub != "" || cfg.DepositAddressFile != "" {
+		go scanner.NewScanner(tronClient, repo, cfg.TronConfirmations, cfg.DepositReorgDepth, int64(cfg.DepositScanStart)).
+			Run(ctx, cfg.DepositScanInterval)
+	}
 
 	// 8) Aguarda sinal de interrupção
 	<-quit
 	fmt.Println("\n🛑 Interrupt signal received, shutting down...")
 
-	// 9) Cancela contexto (para parar consumer & workers)
+	// 9) Drena os workers antes de cancelar o contexto: o consumer ainda está de pé
+	// para confirmar os offsets dos jobs que terminarem
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.WorkerDrainTimeout)
+	if err := pool.Stop(drainCtx); err != nil {
+		fmt.Printf("⚠️ Workers não drenaram a tempo: %v\n", err)
+	}
+	drainCancel()
+
+	// 10) Cancela contexto (para parar consumer)
 	cancel()
 
-	// 10) Chama Shutdown para ambos os Fiber apps (com timeout)
+	// 11) Chama Shutdown para ambos os Fiber apps (com timeout)
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
 
@@ -82,10 +146,13 @@ func RunApp() {
 		fmt.Printf("❌ Erro ao encerrar Front-end: %v\n", err)
 	}
 
-	// 11) Fecha conexões de Kafka, DB, etc.
+	// 12) Fecha conexões de Kafka, DB, etc.
 	app.KafkaWriter.Close()
+	if app.DeadLetterWriter != nil {
+		app.DeadLetterWriter.Close()
+	}
 
-	// 12) Aguarda Listen() retornarem
+	// 13) Aguarda Listen() retornarem
 	wg.Wait()
 	fmt.Println("✔️ Gracefully shut down.")
 }
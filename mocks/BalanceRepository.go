@@ -20,9 +20,9 @@ func (_m *BalanceRepository) EXPECT() *BalanceRepository_Expecter {
 	return &BalanceRepository_Expecter{mock: &_m.Mock}
 }
 
-// GetBalance provides a mock function with given fields: userID
-func (_m *BalanceRepository) GetBalance(userID uint) (*domain.Balance, error) {
-	ret := _m.Called(userID)
+// GetBalance provides a mock function with given fields: userID, currency
+func (_m *BalanceRepository) GetBalance(userID uint, currency string) (*domain.Balance, error) {
+	ret := _m.Called(userID, currency)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetBalance")
@@ -30,19 +30,19 @@ func (_m *BalanceRepository) GetBalance(userID uint) (*domain.Balance, error) {
 
 	var r0 *domain.Balance
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*domain.Balance, error)); ok {
-		return rf(userID)
+	if rf, ok := ret.Get(0).(func(uint, string) (*domain.Balance, error)); ok {
+		return rf(userID, currency)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *domain.Balance); ok {
-		r0 = rf(userID)
+	if rf, ok := ret.Get(0).(func(uint, string) *domain.Balance); ok {
+		r0 = rf(userID, currency)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Balance)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(userID)
+	if rf, ok := ret.Get(1).(func(uint, string) error); ok {
+		r1 = rf(userID, currency)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -57,13 +57,14 @@ type BalanceRepository_GetBalance_Call struct {
 
 // GetBalance is a helper method to define mock.On call
 //   - userID uint
-func (_e *BalanceRepository_Expecter) GetBalance(userID interface{}) *BalanceRepository_GetBalance_Call {
-	return &BalanceRepository_GetBalance_Call{Call: _e.mock.On("GetBalance", userID)}
+//   - currency string
+func (_e *BalanceRepository_Expecter) GetBalance(userID interface{}, currency interface{}) *BalanceRepository_GetBalance_Call {
+	return &BalanceRepository_GetBalance_Call{Call: _e.mock.On("GetBalance", userID, currency)}
 }
 
-func (_c *BalanceRepository_GetBalance_Call) Run(run func(userID uint)) *BalanceRepository_GetBalance_Call {
+func (_c *BalanceRepository_GetBalance_Call) Run(run func(userID uint, currency string)) *BalanceRepository_GetBalance_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(uint), args[1].(string))
 	})
 	return _c
 }
@@ -73,7 +74,65 @@ func (_c *BalanceRepository_GetBalance_Call) Return(_a0 *domain.Balance, _a1 err
 	return _c
 }
 
-func (_c *BalanceRepository_GetBalance_Call) RunAndReturn(run func(uint) (*domain.Balance, error)) *BalanceRepository_GetBalance_Call {
+func (_c *BalanceRepository_GetBalance_Call) RunAndReturn(run func(uint, string) (*domain.Balance, error)) *BalanceRepository_GetBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListBalances provides a mock function with given fields: userID
+func (_m *BalanceRepository) ListBalances(userID uint) ([]domain.Balance, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListBalances")
+	}
+
+	var r0 []domain.Balance
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]domain.Balance, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []domain.Balance); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Balance)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BalanceRepository_ListBalances_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBalances'
+type BalanceRepository_ListBalances_Call struct {
+	*mock.Call
+}
+
+// ListBalances is a helper method to define mock.On call
+//   - userID uint
+func (_e *BalanceRepository_Expecter) ListBalances(userID interface{}) *BalanceRepository_ListBalances_Call {
+	return &BalanceRepository_ListBalances_Call{Call: _e.mock.On("ListBalances", userID)}
+}
+
+func (_c *BalanceRepository_ListBalances_Call) Run(run func(userID uint)) *BalanceRepository_ListBalances_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(uint))
+	})
+	return _c
+}
+
+func (_c *BalanceRepository_ListBalances_Call) Return(_a0 []domain.Balance, _a1 error) *BalanceRepository_ListBalances_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BalanceRepository_ListBalances_Call) RunAndReturn(run func(uint) ([]domain.Balance, error)) *BalanceRepository_ListBalances_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -20,29 +20,199 @@ func (_m *BlockchainClient) EXPECT() *BlockchainClient_Expecter {
 	return &BlockchainClient_Expecter{mock: &_m.Mock}
 }
 
-// SendSignedTRX provides a mock function with given fields: tx, transactionID
-func (_m *BlockchainClient) SendSignedTRX(tx domain.BlockchainTransaction, transactionID string) (*domain.BlockchainTxResult, error) {
-	ret := _m.Called(tx, transactionID)
+// EstimateFee provides a mock function with given fields: tx, asset
+func (_m *BlockchainClient) EstimateFee(tx domain.BlockchainTransaction, asset domain.Asset) (domain.Money, error) {
+	ret := _m.Called(tx, asset)
 
 	if len(ret) == 0 {
-		panic("no return value specified for SendSignedTRX")
+		panic("no return value specified for EstimateFee")
+	}
+
+	var r0 domain.Money
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.BlockchainTransaction, domain.Asset) (domain.Money, error)); ok {
+		return rf(tx, asset)
+	}
+	if rf, ok := ret.Get(0).(func(domain.BlockchainTransaction, domain.Asset) domain.Money); ok {
+		r0 = rf(tx, asset)
+	} else {
+		r0 = ret.Get(0).(domain.Money)
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.BlockchainTransaction, domain.Asset) error); ok {
+		r1 = rf(tx, asset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BlockchainClient_EstimateFee_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EstimateFee'
+type BlockchainClient_EstimateFee_Call struct {
+	*mock.Call
+}
+
+// EstimateFee is a helper method to define mock.On call
+//   - tx domain.BlockchainTransaction
+//   - asset domain.Asset
+func (_e *BlockchainClient_Expecter) EstimateFee(tx interface{}, asset interface{}) *BlockchainClient_EstimateFee_Call {
+	return &BlockchainClient_EstimateFee_Call{Call: _e.mock.On("EstimateFee", tx, asset)}
+}
+
+func (_c *BlockchainClient_EstimateFee_Call) Run(run func(tx domain.BlockchainTransaction, asset domain.Asset)) *BlockchainClient_EstimateFee_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(domain.BlockchainTransaction), args[1].(domain.Asset))
+	})
+	return _c
+}
+
+func (_c *BlockchainClient_EstimateFee_Call) Return(_a0 domain.Money, _a1 error) *BlockchainClient_EstimateFee_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BlockchainClient_EstimateFee_Call) RunAndReturn(run func(domain.BlockchainTransaction, domain.Asset) (domain.Money, error)) *BlockchainClient_EstimateFee_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLatestBlockNumber provides a mock function with no fields
+func (_m *BlockchainClient) GetLatestBlockNumber() (int64, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestBlockNumber")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BlockchainClient_GetLatestBlockNumber_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLatestBlockNumber'
+type BlockchainClient_GetLatestBlockNumber_Call struct {
+	*mock.Call
+}
+
+// GetLatestBlockNumber is a helper method to define mock.On call
+func (_e *BlockchainClient_Expecter) GetLatestBlockNumber() *BlockchainClient_GetLatestBlockNumber_Call {
+	return &BlockchainClient_GetLatestBlockNumber_Call{Call: _e.mock.On("GetLatestBlockNumber")}
+}
+
+func (_c *BlockchainClient_GetLatestBlockNumber_Call) Run(run func()) *BlockchainClient_GetLatestBlockNumber_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *BlockchainClient_GetLatestBlockNumber_Call) Return(_a0 int64, _a1 error) *BlockchainClient_GetLatestBlockNumber_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BlockchainClient_GetLatestBlockNumber_Call) RunAndReturn(run func() (int64, error)) *BlockchainClient_GetLatestBlockNumber_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransactionInfo provides a mock function with given fields: txID
+func (_m *BlockchainClient) GetTransactionInfo(txID string) (*domain.BlockchainTxInfo, error) {
+	ret := _m.Called(txID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionInfo")
+	}
+
+	var r0 *domain.BlockchainTxInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*domain.BlockchainTxInfo, error)); ok {
+		return rf(txID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *domain.BlockchainTxInfo); ok {
+		r0 = rf(txID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.BlockchainTxInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(txID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BlockchainClient_GetTransactionInfo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransactionInfo'
+type BlockchainClient_GetTransactionInfo_Call struct {
+	*mock.Call
+}
+
+// GetTransactionInfo is a helper method to define mock.On call
+//   - txID string
+func (_e *BlockchainClient_Expecter) GetTransactionInfo(txID interface{}) *BlockchainClient_GetTransactionInfo_Call {
+	return &BlockchainClient_GetTransactionInfo_Call{Call: _e.mock.On("GetTransactionInfo", txID)}
+}
+
+func (_c *BlockchainClient_GetTransactionInfo_Call) Run(run func(txID string)) *BlockchainClient_GetTransactionInfo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *BlockchainClient_GetTransactionInfo_Call) Return(_a0 *domain.BlockchainTxInfo, _a1 error) *BlockchainClient_GetTransactionInfo_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *BlockchainClient_GetTransactionInfo_Call) RunAndReturn(run func(string) (*domain.BlockchainTxInfo, error)) *BlockchainClient_GetTransactionInfo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Send provides a mock function with given fields: tx, asset, transactionID
+func (_m *BlockchainClient) Send(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	ret := _m.Called(tx, asset, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
 	}
 
 	var r0 *domain.BlockchainTxResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(domain.BlockchainTransaction, string) (*domain.BlockchainTxResult, error)); ok {
-		return rf(tx, transactionID)
+	if rf, ok := ret.Get(0).(func(domain.BlockchainTransaction, domain.Asset, string) (*domain.BlockchainTxResult, error)); ok {
+		return rf(tx, asset, transactionID)
 	}
-	if rf, ok := ret.Get(0).(func(domain.BlockchainTransaction, string) *domain.BlockchainTxResult); ok {
-		r0 = rf(tx, transactionID)
+	if rf, ok := ret.Get(0).(func(domain.BlockchainTransaction, domain.Asset, string) *domain.BlockchainTxResult); ok {
+		r0 = rf(tx, asset, transactionID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.BlockchainTxResult)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(domain.BlockchainTransaction, string) error); ok {
-		r1 = rf(tx, transactionID)
+	if rf, ok := ret.Get(1).(func(domain.BlockchainTransaction, domain.Asset, string) error); ok {
+		r1 = rf(tx, asset, transactionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -50,31 +220,78 @@ func (_m *BlockchainClient) SendSignedTRX(tx domain.BlockchainTransaction, trans
 	return r0, r1
 }
 
-// BlockchainClient_SendSignedTRX_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SendSignedTRX'
-type BlockchainClient_SendSignedTRX_Call struct {
+// BlockchainClient_Send_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Send'
+type BlockchainClient_Send_Call struct {
 	*mock.Call
 }
 
-// SendSignedTRX is a helper method to define mock.On call
+// Send is a helper method to define mock.On call
 //   - tx domain.BlockchainTransaction
+//   - asset domain.Asset
 //   - transactionID string
-func (_e *BlockchainClient_Expecter) SendSignedTRX(tx interface{}, transactionID interface{}) *BlockchainClient_SendSignedTRX_Call {
-	return &BlockchainClient_SendSignedTRX_Call{Call: _e.mock.On("SendSignedTRX", tx, transactionID)}
+func (_e *BlockchainClient_Expecter) Send(tx interface{}, asset interface{}, transactionID interface{}) *BlockchainClient_Send_Call {
+	return &BlockchainClient_Send_Call{Call: _e.mock.On("Send", tx, asset, transactionID)}
 }
 
-func (_c *BlockchainClient_SendSignedTRX_Call) Run(run func(tx domain.BlockchainTransaction, transactionID string)) *BlockchainClient_SendSignedTRX_Call {
+func (_c *BlockchainClient_Send_Call) Run(run func(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string)) *BlockchainClient_Send_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(domain.BlockchainTransaction), args[1].(string))
+		run(args[0].(domain.BlockchainTransaction), args[1].(domain.Asset), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *BlockchainClient_SendSignedTRX_Call) Return(_a0 *domain.BlockchainTxResult, _a1 error) *BlockchainClient_SendSignedTRX_Call {
+func (_c *BlockchainClient_Send_Call) Return(_a0 *domain.BlockchainTxResult, _a1 error) *BlockchainClient_Send_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *BlockchainClient_SendSignedTRX_Call) RunAndReturn(run func(domain.BlockchainTransaction, string) (*domain.BlockchainTxResult, error)) *BlockchainClient_SendSignedTRX_Call {
+func (_c *BlockchainClient_Send_Call) RunAndReturn(run func(domain.BlockchainTransaction, domain.Asset, string) (*domain.BlockchainTxResult, error)) *BlockchainClient_Send_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateAddress provides a mock function with given fields: address
+func (_m *BlockchainClient) ValidateAddress(address string) error {
+	ret := _m.Called(address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateAddress")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BlockchainClient_ValidateAddress_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateAddress'
+type BlockchainClient_ValidateAddress_Call struct {
+	*mock.Call
+}
+
+// ValidateAddress is a helper method to define mock.On call
+//   - address string
+func (_e *BlockchainClient_Expecter) ValidateAddress(address interface{}) *BlockchainClient_ValidateAddress_Call {
+	return &BlockchainClient_ValidateAddress_Call{Call: _e.mock.On("ValidateAddress", address)}
+}
+
+func (_c *BlockchainClient_ValidateAddress_Call) Run(run func(address string)) *BlockchainClient_ValidateAddress_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *BlockchainClient_ValidateAddress_Call) Return(_a0 error) *BlockchainClient_ValidateAddress_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BlockchainClient_ValidateAddress_Call) RunAndReturn(run func(string) error) *BlockchainClient_ValidateAddress_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -67,12 +67,73 @@ func (_c *KafkaReader_Close_Call) RunAndReturn(run func() error) *KafkaReader_Cl
 	return _c
 }
 
-// ReadMessage provides a mock function with given fields: _a0
-func (_m *KafkaReader) ReadMessage(_a0 context.Context) (kafka.Message, error) {
+// CommitMessages provides a mock function with given fields: ctx, msgs
+func (_m *KafkaReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	_va := make([]interface{}, len(msgs))
+	for _i := range msgs {
+		_va[_i] = msgs[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitMessages")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...kafka.Message) error); ok {
+		r0 = rf(ctx, msgs...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// KafkaReader_CommitMessages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CommitMessages'
+type KafkaReader_CommitMessages_Call struct {
+	*mock.Call
+}
+
+// CommitMessages is a helper method to define mock.On call
+//   - ctx context.Context
+//   - msgs ...kafka.Message
+func (_e *KafkaReader_Expecter) CommitMessages(ctx interface{}, msgs ...interface{}) *KafkaReader_CommitMessages_Call {
+	return &KafkaReader_CommitMessages_Call{Call: _e.mock.On("CommitMessages",
+		append([]interface{}{ctx}, msgs...)...)}
+}
+
+func (_c *KafkaReader_CommitMessages_Call) Run(run func(ctx context.Context, msgs ...kafka.Message)) *KafkaReader_CommitMessages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]kafka.Message, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(kafka.Message)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *KafkaReader_CommitMessages_Call) Return(_a0 error) *KafkaReader_CommitMessages_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *KafkaReader_CommitMessages_Call) RunAndReturn(run func(context.Context, ...kafka.Message) error) *KafkaReader_CommitMessages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FetchMessage provides a mock function with given fields: _a0
+func (_m *KafkaReader) FetchMessage(_a0 context.Context) (kafka.Message, error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ReadMessage")
+		panic("no return value specified for FetchMessage")
 	}
 
 	var r0 kafka.Message
@@ -95,30 +156,30 @@ func (_m *KafkaReader) ReadMessage(_a0 context.Context) (kafka.Message, error) {
 	return r0, r1
 }
 
-// KafkaReader_ReadMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReadMessage'
-type KafkaReader_ReadMessage_Call struct {
+// KafkaReader_FetchMessage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FetchMessage'
+type KafkaReader_FetchMessage_Call struct {
 	*mock.Call
 }
 
-// ReadMessage is a helper method to define mock.On call
+// FetchMessage is a helper method to define mock.On call
 //   - _a0 context.Context
-func (_e *KafkaReader_Expecter) ReadMessage(_a0 interface{}) *KafkaReader_ReadMessage_Call {
-	return &KafkaReader_ReadMessage_Call{Call: _e.mock.On("ReadMessage", _a0)}
+func (_e *KafkaReader_Expecter) FetchMessage(_a0 interface{}) *KafkaReader_FetchMessage_Call {
+	return &KafkaReader_FetchMessage_Call{Call: _e.mock.On("FetchMessage", _a0)}
 }
 
-func (_c *KafkaReader_ReadMessage_Call) Run(run func(_a0 context.Context)) *KafkaReader_ReadMessage_Call {
+func (_c *KafkaReader_FetchMessage_Call) Run(run func(_a0 context.Context)) *KafkaReader_FetchMessage_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context))
 	})
 	return _c
 }
 
-func (_c *KafkaReader_ReadMessage_Call) Return(_a0 kafka.Message, _a1 error) *KafkaReader_ReadMessage_Call {
+func (_c *KafkaReader_FetchMessage_Call) Return(_a0 kafka.Message, _a1 error) *KafkaReader_FetchMessage_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *KafkaReader_ReadMessage_Call) RunAndReturn(run func(context.Context) (kafka.Message, error)) *KafkaReader_ReadMessage_Call {
+func (_c *KafkaReader_FetchMessage_Call) RunAndReturn(run func(context.Context) (kafka.Message, error)) *KafkaReader_FetchMessage_Call {
 	_c.Call.Return(run)
 	return _c
 }
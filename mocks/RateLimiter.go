@@ -2,7 +2,10 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	domain "github.com/gabrielksneiva/go-financial-transactions/domain"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // RateLimiter is an autogenerated mock type for the RateLimiter type
 type RateLimiter struct {
@@ -17,6 +20,63 @@ func (_m *RateLimiter) EXPECT() *RateLimiter_Expecter {
 	return &RateLimiter_Expecter{mock: &_m.Mock}
 }
 
+// Allow provides a mock function with given fields: class, key
+func (_m *RateLimiter) Allow(class string, key string) (domain.RateLimitResult, error) {
+	ret := _m.Called(class, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Allow")
+	}
+
+	var r0 domain.RateLimitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (domain.RateLimitResult, error)); ok {
+		return rf(class, key)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) domain.RateLimitResult); ok {
+		r0 = rf(class, key)
+	} else {
+		r0 = ret.Get(0).(domain.RateLimitResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(class, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RateLimiter_Allow_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Allow'
+type RateLimiter_Allow_Call struct {
+	*mock.Call
+}
+
+// Allow is a helper method to define mock.On call
+//   - class string
+//   - key string
+func (_e *RateLimiter_Expecter) Allow(class interface{}, key interface{}) *RateLimiter_Allow_Call {
+	return &RateLimiter_Allow_Call{Call: _e.mock.On("Allow", class, key)}
+}
+
+func (_c *RateLimiter_Allow_Call) Run(run func(class string, key string)) *RateLimiter_Allow_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RateLimiter_Allow_Call) Return(_a0 domain.RateLimitResult, _a1 error) *RateLimiter_Allow_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RateLimiter_Allow_Call) RunAndReturn(run func(string, string) (domain.RateLimitResult, error)) *RateLimiter_Allow_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CheckTransactionRateLimit provides a mock function with given fields: userID
 func (_m *RateLimiter) CheckTransactionRateLimit(userID uint) error {
 	ret := _m.Called(userID)
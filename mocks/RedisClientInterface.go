@@ -4,10 +4,9 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 
 	mock "github.com/stretchr/testify/mock"
-
-	time "time"
 )
 
 // RedisClientInterface is an autogenerated mock type for the RedisClientInterface type
@@ -23,6 +22,83 @@ func (_m *RedisClientInterface) EXPECT() *RedisClientInterface_Expecter {
 	return &RedisClientInterface_Expecter{mock: &_m.Mock}
 }
 
+// Eval provides a mock function with given fields: ctx, script, keys, args
+func (_m *RedisClientInterface) Eval(ctx context.Context, script string, keys []string, args ...interface{}) ([]int64, error) {
+	_va := make([]interface{}, len(args))
+	for _i := range args {
+		_va[_i] = args[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, script)
+	_ca = append(_ca, keys)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Eval")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ...interface{}) ([]int64, error)); ok {
+		return rf(ctx, script, keys, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ...interface{}) []int64); ok {
+		r0 = rf(ctx, script, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, ...interface{}) error); ok {
+		r1 = rf(ctx, script, keys, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedisClientInterface_Eval_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Eval'
+type RedisClientInterface_Eval_Call struct {
+	*mock.Call
+}
+
+// Eval is a helper method to define mock.On call
+//   - ctx context.Context
+//   - script string
+//   - keys []string
+//   - args ...interface{}
+func (_e *RedisClientInterface_Expecter) Eval(ctx interface{}, script interface{}, keys interface{}, args ...interface{}) *RedisClientInterface_Eval_Call {
+	return &RedisClientInterface_Eval_Call{Call: _e.mock.On("Eval",
+		append([]interface{}{ctx, script, keys}, args...)...)}
+}
+
+func (_c *RedisClientInterface_Eval_Call) Run(run func(ctx context.Context, script string, keys []string, args ...interface{})) *RedisClientInterface_Eval_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]interface{}, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(interface{})
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *RedisClientInterface_Eval_Call) Return(_a0 []int64, _a1 error) *RedisClientInterface_Eval_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RedisClientInterface_Eval_Call) RunAndReturn(run func(context.Context, string, []string, ...interface{}) ([]int64, error)) *RedisClientInterface_Eval_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Expire provides a mock function with given fields: ctx, key, expiration
 func (_m *RedisClientInterface) Expire(ctx context.Context, key string, expiration time.Duration) error {
 	ret := _m.Called(ctx, key, expiration)
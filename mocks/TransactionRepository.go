@@ -78,6 +78,64 @@ func (_c *TransactionRepository_GetByUser_Call) RunAndReturn(run func(uint) ([]d
 	return _c
 }
 
+// GetStatusHistory provides a mock function with given fields: txID
+func (_m *TransactionRepository) GetStatusHistory(txID string) ([]domain.TransactionStatusHistory, error) {
+	ret := _m.Called(txID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetStatusHistory")
+	}
+
+	var r0 []domain.TransactionStatusHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]domain.TransactionStatusHistory, error)); ok {
+		return rf(txID)
+	}
+	if rf, ok := ret.Get(0).(func(string) []domain.TransactionStatusHistory); ok {
+		r0 = rf(txID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TransactionStatusHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(txID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepository_GetStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetStatusHistory'
+type TransactionRepository_GetStatusHistory_Call struct {
+	*mock.Call
+}
+
+// GetStatusHistory is a helper method to define mock.On call
+//   - txID string
+func (_e *TransactionRepository_Expecter) GetStatusHistory(txID interface{}) *TransactionRepository_GetStatusHistory_Call {
+	return &TransactionRepository_GetStatusHistory_Call{Call: _e.mock.On("GetStatusHistory", txID)}
+}
+
+func (_c *TransactionRepository_GetStatusHistory_Call) Run(run func(txID string)) *TransactionRepository_GetStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_GetStatusHistory_Call) Return(_a0 []domain.TransactionStatusHistory, _a1 error) *TransactionRepository_GetStatusHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepository_GetStatusHistory_Call) RunAndReturn(run func(string) ([]domain.TransactionStatusHistory, error)) *TransactionRepository_GetStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetTransactionsByUserID provides a mock function with given fields: userID
 func (_m *TransactionRepository) GetTransactionsByUserID(userID uint) ([]domain.Transaction, error) {
 	ret := _m.Called(userID)
@@ -136,6 +194,65 @@ func (_c *TransactionRepository_GetTransactionsByUserID_Call) RunAndReturn(run f
 	return _c
 }
 
+// ListTransactionsByStatus provides a mock function with given fields: status, limit
+func (_m *TransactionRepository) ListTransactionsByStatus(status domain.TransactionStatus, limit int) ([]domain.Transaction, error) {
+	ret := _m.Called(status, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTransactionsByStatus")
+	}
+
+	var r0 []domain.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(domain.TransactionStatus, int) ([]domain.Transaction, error)); ok {
+		return rf(status, limit)
+	}
+	if rf, ok := ret.Get(0).(func(domain.TransactionStatus, int) []domain.Transaction); ok {
+		r0 = rf(status, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(domain.TransactionStatus, int) error); ok {
+		r1 = rf(status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TransactionRepository_ListTransactionsByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListTransactionsByStatus'
+type TransactionRepository_ListTransactionsByStatus_Call struct {
+	*mock.Call
+}
+
+// ListTransactionsByStatus is a helper method to define mock.On call
+//   - status domain.TransactionStatus
+//   - limit int
+func (_e *TransactionRepository_Expecter) ListTransactionsByStatus(status interface{}, limit interface{}) *TransactionRepository_ListTransactionsByStatus_Call {
+	return &TransactionRepository_ListTransactionsByStatus_Call{Call: _e.mock.On("ListTransactionsByStatus", status, limit)}
+}
+
+func (_c *TransactionRepository_ListTransactionsByStatus_Call) Run(run func(status domain.TransactionStatus, limit int)) *TransactionRepository_ListTransactionsByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(domain.TransactionStatus), args[1].(int))
+	})
+	return _c
+}
+
+func (_c *TransactionRepository_ListTransactionsByStatus_Call) Return(_a0 []domain.Transaction, _a1 error) *TransactionRepository_ListTransactionsByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *TransactionRepository_ListTransactionsByStatus_Call) RunAndReturn(run func(domain.TransactionStatus, int) ([]domain.Transaction, error)) *TransactionRepository_ListTransactionsByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function with given fields: tx
 func (_m *TransactionRepository) Save(tx domain.Transaction) error {
 	ret := _m.Called(tx)
@@ -229,17 +346,17 @@ func (_c *TransactionRepository_UpdateTransactionHash_Call) RunAndReturn(run fun
 	return _c
 }
 
-// UpdateTransactionStatus provides a mock function with given fields: txID, status
-func (_m *TransactionRepository) UpdateTransactionStatus(txID string, status string) error {
-	ret := _m.Called(txID, status)
+// UpdateTransactionStatus provides a mock function with given fields: txID, from, to, reason
+func (_m *TransactionRepository) UpdateTransactionStatus(txID string, from domain.TransactionStatus, to domain.TransactionStatus, reason string) error {
+	ret := _m.Called(txID, from, to, reason)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateTransactionStatus")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = rf(txID, status)
+	if rf, ok := ret.Get(0).(func(string, domain.TransactionStatus, domain.TransactionStatus, string) error); ok {
+		r0 = rf(txID, from, to, reason)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -254,14 +371,16 @@ type TransactionRepository_UpdateTransactionStatus_Call struct {
 
 // UpdateTransactionStatus is a helper method to define mock.On call
 //   - txID string
-//   - status string
-func (_e *TransactionRepository_Expecter) UpdateTransactionStatus(txID interface{}, status interface{}) *TransactionRepository_UpdateTransactionStatus_Call {
-	return &TransactionRepository_UpdateTransactionStatus_Call{Call: _e.mock.On("UpdateTransactionStatus", txID, status)}
+//   - from domain.TransactionStatus
+//   - to domain.TransactionStatus
+//   - reason string
+func (_e *TransactionRepository_Expecter) UpdateTransactionStatus(txID interface{}, from interface{}, to interface{}, reason interface{}) *TransactionRepository_UpdateTransactionStatus_Call {
+	return &TransactionRepository_UpdateTransactionStatus_Call{Call: _e.mock.On("UpdateTransactionStatus", txID, from, to, reason)}
 }
 
-func (_c *TransactionRepository_UpdateTransactionStatus_Call) Run(run func(txID string, status string)) *TransactionRepository_UpdateTransactionStatus_Call {
+func (_c *TransactionRepository_UpdateTransactionStatus_Call) Run(run func(txID string, from domain.TransactionStatus, to domain.TransactionStatus, reason string)) *TransactionRepository_UpdateTransactionStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(string), args[1].(domain.TransactionStatus), args[2].(domain.TransactionStatus), args[3].(string))
 	})
 	return _c
 }
@@ -271,7 +390,7 @@ func (_c *TransactionRepository_UpdateTransactionStatus_Call) Return(_a0 error)
 	return _c
 }
 
-func (_c *TransactionRepository_UpdateTransactionStatus_Call) RunAndReturn(run func(string, string) error) *TransactionRepository_UpdateTransactionStatus_Call {
+func (_c *TransactionRepository_UpdateTransactionStatus_Call) RunAndReturn(run func(string, domain.TransactionStatus, domain.TransactionStatus, string) error) *TransactionRepository_UpdateTransactionStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,84 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	p "github.com/gabrielksneiva/go-financial-transactions/producer"
+)
+
+// Relay publica no Kafka as mensagens gravadas no outbox pelas services.
+// A entrega é at-least-once: se o processo cair entre o envio e o MarkOutboxSent,
+// a mensagem é publicada de novo e os workers a ignoram pelo ID da transação.
+type Relay struct {
+	repo      d.OutboxRepository
+	producer  p.Producer
+	batchSize int
+}
+
+func NewRelay(repo d.OutboxRepository, producer p.Producer, batchSize int) *Relay {
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	return &Relay{repo: repo, producer: producer, batchSize: batchSize}
+}
+
+// Flush publica um lote de mensagens pendentes e retorna quantas foram enviadas.
+// Na primeira falha o lote é interrompido, para não publicar mensagens seguintes
+// do mesmo usuário fora de ordem.
+func (r *Relay) Flush() (int, error) {
+	msgs, err := r.repo.FetchPendingOutbox(r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, msg := range msgs {
+		var tx d.Transaction
+		if err := json.Unmarshal(msg.Payload, &tx); err != nil {
+			log.Printf("❌ Outbox: mensagem %d com payload inválido: %v", msg.ID, err)
+			if err := r.repo.MarkOutboxInvalid(msg.ID, err.Error()); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		if err := r.producer.SendTransaction(tx); err != nil {
+			if markErr := r.repo.MarkOutboxFailed(msg.ID, err.Error()); markErr != nil {
+				log.Printf("⚠️ Outbox: erro ao registrar falha da mensagem %d: %v", msg.ID, markErr)
+			}
+			return sent, err
+		}
+
+		if err := r.repo.MarkOutboxSent(msg.ID); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, nil
+}
+
+func (r *Relay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Outbox relay encerrado")
+			return
+		case <-ticker.C:
+			sent, err := r.Flush()
+			if err != nil {
+				log.Printf("❌ Outbox relay: erro ao publicar mensagens: %v", err)
+			}
+			if sent > 0 {
+				log.Printf("📤 Outbox relay: %d mensagem(ns) publicada(s)", sent)
+			}
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package outbox_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/outbox"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func outboxMessage(t *testing.T, id uint, txID string) domain.OutboxMessage {
+	payload, err := json.Marshal(domain.Transaction{ID: txID, UserID: 1, Amount: domain.NewMoney(1_000000, "TRX"), Type: "deposit"})
+	assert.NoError(t, err)
+	return domain.OutboxMessage{ID: id, TransactionID: txID, Payload: payload}
+}
+
+func TestRelay_Flush_PublishesAndMarksSent(t *testing.T) {
+	repo := new(mocks.OutboxRepository)
+	producer := new(mocks.Producer)
+
+	repo.On("FetchPendingOutbox", 10).Return([]domain.OutboxMessage{
+		outboxMessage(t, 1, "tx-1"),
+		outboxMessage(t, 2, "tx-2"),
+	}, nil)
+	producer.On("SendTransaction", mock.MatchedBy(func(tx domain.Transaction) bool { return tx.ID == "tx-1" })).Return(nil)
+	producer.On("SendTransaction", mock.MatchedBy(func(tx domain.Transaction) bool { return tx.ID == "tx-2" })).Return(nil)
+	repo.On("MarkOutboxSent", uint(1)).Return(nil)
+	repo.On("MarkOutboxSent", uint(2)).Return(nil)
+
+	sent, err := outbox.NewRelay(repo, producer, 10).Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	repo.AssertExpectations(t)
+	producer.AssertExpectations(t)
+}
+
+func TestRelay_Flush_StopsOnKafkaError(t *testing.T) {
+	repo := new(mocks.OutboxRepository)
+	producer := new(mocks.Producer)
+
+	repo.On("FetchPendingOutbox", 10).Return([]domain.OutboxMessage{
+		outboxMessage(t, 1, "tx-1"),
+		outboxMessage(t, 2, "tx-2"),
+	}, nil)
+	producer.On("SendTransaction", mock.Anything).Return(errors.New("kafka down")).Once()
+	repo.On("MarkOutboxFailed", uint(1), "kafka down").Return(nil)
+
+	// a mensagem seguinte não é enviada antes da que falhou
+	sent, err := outbox.NewRelay(repo, producer, 10).Flush()
+	assert.EqualError(t, err, "kafka down")
+	assert.Equal(t, 0, sent)
+	producer.AssertNumberOfCalls(t, "SendTransaction", 1)
+	repo.AssertNotCalled(t, "MarkOutboxSent", mock.Anything)
+}
+
+func TestRelay_Flush_InvalidPayloadLeavesTheQueue(t *testing.T) {
+	repo := new(mocks.OutboxRepository)
+	producer := new(mocks.Producer)
+
+	broken := domain.OutboxMessage{ID: 1, TransactionID: "tx-broken", Payload: []byte("{not json")}
+	repo.On("FetchPendingOutbox", 10).Return([]domain.OutboxMessage{broken, outboxMessage(t, 2, "tx-2")}, nil)
+	repo.On("MarkOutboxInvalid", uint(1), mock.Anything).Return(nil)
+	producer.On("SendTransaction", mock.Anything).Return(nil)
+	repo.On("MarkOutboxSent", uint(2)).Return(nil)
+
+	// a mensagem inválida sai do PENDING em vez de ser buscada de novo a cada passada
+	sent, err := outbox.NewRelay(repo, producer, 10).Flush()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkOutboxFailed", mock.Anything, mock.Anything)
+}
+
+func TestRelay_Flush_FetchError(t *testing.T) {
+	repo := new(mocks.OutboxRepository)
+	repo.On("FetchPendingOutbox", 100).Return(nil, errors.New("db down"))
+
+	sent, err := outbox.NewRelay(repo, new(mocks.Producer), 0).Flush()
+	assert.Error(t, err)
+	assert.Equal(t, 0, sent)
+}
@@ -0,0 +1,58 @@
+package producer
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// DeadLetterWriter publica no tópico de dead-letter as mensagens que não puderam ser processadas
+type DeadLetterWriter struct {
+	writer WriterInterface
+}
+
+func NewDeadLetterWriter(broker, topic string) *DeadLetterWriter {
+	w := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:  []string{broker},
+		Topic:    topic,
+		Balancer: &kafka.Hash{},
+	})
+
+	return &DeadLetterWriter{writer: w}
+}
+
+// NewDeadLetterWriterWithMock is used only in unit tests to inject a mock writer
+func NewDeadLetterWriterWithMock(w WriterInterface) *DeadLetterWriter {
+	return &DeadLetterWriter{writer: w}
+}
+
+// PublishDeadLetter copia chave e valor da mensagem original e descreve a falha nos headers
+func (d *DeadLetterWriter) PublishDeadLetter(ctx context.Context, msg kafka.Message, cause error, attempts int) error {
+	errMsg := ""
+	if cause != nil {
+		errMsg = cause.Error()
+	}
+
+	headers := []kafka.Header{
+		{Key: domain.HeaderDLQError, Value: []byte(errMsg)},
+		{Key: domain.HeaderDLQAttempts, Value: []byte(strconv.Itoa(attempts))},
+		{Key: domain.HeaderDLQOriginalTopic, Value: []byte(msg.Topic)},
+		{Key: domain.HeaderDLQOriginalPartition, Value: []byte(strconv.Itoa(msg.Partition))},
+		{Key: domain.HeaderDLQOriginalOffset, Value: []byte(strconv.FormatInt(msg.Offset, 10))},
+		{Key: domain.HeaderDLQFailedAt, Value: []byte(time.Now().UTC().Format(time.RFC3339))},
+	}
+
+	return d.writer.WriteMessages(ctx, kafka.Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: headers,
+	})
+}
+
+func (d *DeadLetterWriter) Close() error {
+	return d.writer.Close()
+}
@@ -4,11 +4,10 @@ package producer
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"strconv"
 
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 
-	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 )
 
@@ -25,7 +24,7 @@ func NewKafkaWriter(broker, topic string) *KafkaWriter {
 	w := kafka.NewWriter(kafka.WriterConfig{
 		Brokers:  []string{broker},
 		Topic:    topic,
-		Balancer: &kafka.LeastBytes{},
+		Balancer: &kafka.Hash{}, // mesma chave → mesma partição
 	})
 
 	return &KafkaWriter{
@@ -39,16 +38,19 @@ func (k *KafkaWriter) SendTransaction(tx domain.Transaction) error {
 		return err
 	}
 
+	// A chave é o usuário: todas as transações dele caem na mesma partição, em ordem
 	msg := kafka.Message{
-		Key:   []byte(uuid.New().String()),
+		Key:   MessageKey(tx.UserID),
 		Value: data,
 	}
 
-	fmt.Printf("Producing message: %s\n", string(msg.Value))
-
 	return k.writer.WriteMessages(context.Background(), msg)
 }
 
+func MessageKey(userID uint) []byte {
+	return []byte(strconv.FormatUint(uint64(userID), 10))
+}
+
 func (k *KafkaWriter) Close() error {
 	return k.writer.Close()
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/gabrielksneiva/go-financial-transactions/mocks"
 	"github.com/gabrielksneiva/go-financial-transactions/producer"
 
+	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,7 +24,7 @@ func TestKafkaProducer_SendTransaction(t *testing.T) {
 	tx := domain.Transaction{
 		ID:     "tx-123",
 		UserID: uint(456),
-		Amount: 100,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 		Type:   "deposit",
 	}
 
@@ -32,6 +33,29 @@ func TestKafkaProducer_SendTransaction(t *testing.T) {
 	writerMock.AssertExpectations(t)
 }
 
+func TestKafkaProducer_SendTransaction_KeyedByUser(t *testing.T) {
+	writerMock := new(mocks.WriterInterface)
+	writerMock.
+		On("WriteMessages", mock.Anything, mock.MatchedBy(func(msg kafka.Message) bool {
+			return string(msg.Key) == "456"
+		})).
+		Return(nil).Twice()
+
+	prod := producer.NewKafkaWriterWithMock(writerMock)
+
+	// depósito e saque do mesmo usuário usam a mesma chave, logo a mesma partição
+	for _, txType := range []string{"deposit", "withdraw"} {
+		err := prod.SendTransaction(domain.Transaction{
+			ID:     "tx-" + txType,
+			UserID: uint(456),
+			Amount: domain.NewMoney(1_000000, "TRX"),
+			Type:   txType,
+		})
+		assert.NoError(t, err)
+	}
+	writerMock.AssertExpectations(t)
+}
+
 func TestKafkaProducer_SendTransaction_Error(t *testing.T) {
 	writerMock := new(mocks.WriterInterface)
 	writerMock.
@@ -43,7 +67,7 @@ func TestKafkaProducer_SendTransaction_Error(t *testing.T) {
 	tx := domain.Transaction{
 		ID:     "tx-123",
 		UserID: uint(456),
-		Amount: 100,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 		Type:   "deposit",
 	}
 
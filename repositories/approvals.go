@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ d.ApprovalRepository = &GormRepository{}
+
+func (r *GormRepository) HoldForApproval(tx d.Transaction) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		return holdForApproval(txDB, tx)
+	})
+}
+
+// holdForApproval reserva o valor e grava o saque AWAITING_APPROVAL, sem mensagem no outbox
+func holdForApproval(txDB *gorm.DB, tx d.Transaction) error {
+	if err := holdFunds(txDB, tx); err != nil {
+		return err
+	}
+
+	tx.Status = d.StatusAwaitingApproval
+	if err := txDB.Omit("User").Create(&tx).Error; err != nil {
+		return err
+	}
+	return txDB.Create(&d.TransactionStatusHistory{
+		TransactionID: tx.ID,
+		To:            tx.Status,
+		Reason:        "above approval threshold",
+	}).Error
+}
+
+func (r *GormRepository) ApproveWithdrawal(txID string, approverID uint, reason string) (*d.Transaction, error) {
+	return r.decideWithdrawal(txID, approverID, d.ApprovalDecisionApproved, reason, func(txDB *gorm.DB, tx *d.Transaction) error {
+		if err := TransitionStatus(txDB, tx.ID, d.StatusAwaitingApproval, d.StatusApproved, approvalReason(approverID, reason)); err != nil {
+			return err
+		}
+		tx.Status = d.StatusApproved
+		return enqueueOutbox(txDB, *tx)
+	})
+}
+
+func (r *GormRepository) RejectWithdrawal(txID string, approverID uint, reason string) (*d.Transaction, error) {
+	return r.decideWithdrawal(txID, approverID, d.ApprovalDecisionRejected, reason, func(txDB *gorm.DB, tx *d.Transaction) error {
+		if err := TransitionStatus(txDB, tx.ID, d.StatusAwaitingApproval, d.StatusRejected, approvalReason(approverID, reason)); err != nil {
+			return err
+		}
+		tx.Status = d.StatusRejected
+		return ledger.ReleaseHold(txDB, *tx)
+	})
+}
+
+func (r *GormRepository) ListApprovalDecisions(txID string) ([]d.ApprovalDecision, error) {
+	var decisions []d.ApprovalDecision
+	err := r.db.Where("transaction_id = ?", txID).Order("id ASC").Find(&decisions).Error
+	return decisions, err
+}
+
+// decideWithdrawal trava o saque, confere quem decide e grava a decisão na trilha de auditoria
+// na mesma transação do efeito
+func (r *GormRepository) decideWithdrawal(txID string, approverID uint, decision, reason string, apply func(*gorm.DB, *d.Transaction) error) (*d.Transaction, error) {
+	var tx d.Transaction
+	err := r.db.Transaction(func(txDB *gorm.DB) error {
+		err := txDB.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND type = ?", txID, d.WithdrawTransaction).
+			First(&tx).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return d.ErrTransactionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if tx.UserID == approverID {
+			return d.ErrSelfApproval
+		}
+
+		if err := apply(txDB, &tx); err != nil {
+			return err
+		}
+
+		return txDB.Create(&d.ApprovalDecision{
+			TransactionID: tx.ID,
+			RequesterID:   tx.UserID,
+			ApproverID:    approverID,
+			Decision:      decision,
+			Reason:        reason,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func approvalReason(approverID uint, reason string) string {
+	if reason == "" {
+		return fmt.Sprintf("decided by admin %d", approverID)
+	}
+	return fmt.Sprintf("decided by admin %d: %s", approverID, reason)
+}
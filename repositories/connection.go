@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
 	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -39,7 +40,105 @@ func connect(dbHost, dbUser, dbPassword, dbName, dbPort string) (*gorm.DB, error
 }
 
 func migrate(db *gorm.DB) error {
-	return db.AutoMigrate(&domain.Transaction{}, &domain.Balance{}, &domain.User{})
+	if err := migrateAmountUnits(db); err != nil {
+		return err
+	}
+	if err := migrateBalanceKey(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
+		&domain.Transaction{},
+		&domain.Balance{},
+		&domain.User{},
+		&domain.LedgerAccount{},
+		&domain.JournalEntry{},
+		&domain.Posting{},
+		&domain.IdempotencyRecord{},
+		&domain.ProcessedMessage{},
+		&domain.OutboxMessage{},
+		&domain.TransactionStatusHistory{},
+		&domain.DepositAddress{},
+		&domain.ScannedBlock{},
+		&domain.OnchainDeposit{},
+		&domain.WithdrawalAddress{},
+		&domain.ApprovalDecision{},
+		&domain.Schedule{},
+		&domain.Lease{},
+		&domain.LimitOverride{},
+	); err != nil {
+		return err
+	}
+
+	return migrateOpeningBalances(db)
+}
+
+// migrateAmountUnits converte balances.amount e transactions.amount do formato antigo (float
+// em TRX inteiros) para bigint em SUN. Sem isso o AutoMigrate só trocaria o tipo e 100 TRX
+// virariam 100 SUN; todas as linhas antigas são TRX, então a escala é 10^6.
+func migrateAmountUnits(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	for _, table := range []string{"balances", "transactions"} {
+		err := db.Exec(fmt.Sprintf(`
+DO $$
+BEGIN
+	IF (SELECT data_type FROM information_schema.columns
+	     WHERE table_schema = current_schema() AND table_name = '%[1]s' AND column_name = 'amount')
+	   IN ('double precision', 'real', 'numeric') THEN
+		ALTER TABLE %[1]s ALTER COLUMN amount TYPE bigint USING round(amount * 1e6)::bigint;
+	END IF;
+END $$`, table)).Error
+		if err != nil {
+			return fmt.Errorf("falha ao converter %s.amount para unidades mínimas: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// migrateBalanceKey converte a tabela balances do formato antigo (uma linha por usuário,
+// chave em user_id) para uma linha por moeda. O AutoMigrate não altera chaves primárias.
+func migrateBalanceKey(db *gorm.DB) error {
+	if db.Dialector.Name() != "postgres" || !db.Migrator().HasTable(&domain.Balance{}) {
+		return nil
+	}
+
+	return db.Exec(`
+DO $$
+BEGIN
+	IF (SELECT count(*) FROM pg_index i
+	      JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+	     WHERE i.indrelid = 'balances'::regclass AND i.indisprimary) = 1 THEN
+		UPDATE balances SET currency = 'TRX' WHERE currency IS NULL OR currency = '';
+		ALTER TABLE balances DROP CONSTRAINT balances_pkey, ADD PRIMARY KEY (user_id, currency);
+	END IF;
+END $$`).Error
+}
+
+// migrateOpeningBalances lança no razão os saldos gravados antes dele existir; sem isso o
+// checker acusaria todo usuário antigo. Só entram saldos sem conta de usuário no razão, então
+// rodar de novo não altera nada.
+func migrateOpeningBalances(db *gorm.DB) error {
+	var balances []domain.Balance
+	if err := db.Where(`amount <> 0 AND NOT EXISTS (
+		SELECT 1 FROM ledger_accounts a WHERE a.code = CAST(? AS text) || CAST(balances.user_id AS text) || ':' || balances.currency)`,
+		domain.LedgerAccountUser+":").Find(&balances).Error; err != nil {
+		return err
+	}
+
+	for _, balance := range balances {
+		if err := db.Transaction(func(txDB *gorm.DB) error {
+			return ledger.OpeningBalance(txDB, balance)
+		}); err != nil {
+			return fmt.Errorf("falha ao lançar saldo de abertura do usuário %d (%s): %w", balance.UserID, balance.Currency, err)
+		}
+	}
+	if len(balances) > 0 {
+		log.Printf("📒 %d saldos anteriores ao razão lançados contra %s", len(balances), ledger.AccountOpeningEquity)
+	}
+	return nil
 }
 
 func CallMigrateTestHelper(db *gorm.DB) error {
@@ -34,24 +34,28 @@ func (r *GormRepository) GetByUser(userID uint) ([]d.Transaction, error) {
 
 // Implementa BalanceRepository
 func (r *GormRepository) UpdateBalance(tx d.Transaction) error {
+	balance := d.NewBalance(tx.UserID, tx.Amount)
 
 	return r.db.Clauses(clause.OnConflict{
-		Columns: []clause.Column{{Name: "user_id"}},
+		Columns: []clause.Column{{Name: "user_id"}, {Name: "currency"}},
 		DoUpdates: clause.Assignments(map[string]interface{}{
 			"amount": gorm.Expr("balances.amount + EXCLUDED.amount"),
 		}),
-	}).Create(&d.Balance{
-		UserID: tx.UserID,
-		Amount: tx.Amount,
-	}).Error
+	}).Create(&balance).Error
 }
 
-func (r *GormRepository) GetBalance(userID uint) (*d.Balance, error) {
+func (r *GormRepository) GetBalance(userID uint, currency string) (*d.Balance, error) {
 	var b d.Balance
-	err := r.db.Where("user_id = ?", userID).First(&b).Error
+	err := r.db.Where("user_id = ? AND currency = ?", userID, currency).First(&b).Error
 	return &b, err
 }
 
+func (r *GormRepository) ListBalances(userID uint) ([]d.Balance, error) {
+	var balances []d.Balance
+	err := r.db.Where("user_id = ?", userID).Order("currency").Find(&balances).Error
+	return balances, err
+}
+
 // Implementa d.UserRepository
 func (r *GormRepository) Create(user d.User) error {
 	return r.db.Create(&user).Error
@@ -101,9 +105,3 @@ func (r *GormRepository) UpdateTransactionHash(txID string, txHash string) error
 		Where("id = ?", txID).
 		Update("tx_hash", txHash).Error
 }
-
-func (r *GormRepository) UpdateTransactionStatus(txID string, status string) error {
-	return r.db.Model(&domain.Transaction{}).
-		Where("id = ?", txID).
-		Update("status", status).Error
-}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (r *GormRepository) SaveWithdrawalWithHold(tx d.Transaction) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		if err := holdFunds(txDB, tx); err != nil {
+			return err
+		}
+		return saveWithOutbox(txDB, tx, "accepted by API")
+	})
+}
+
+// holdFunds trava o saldo do usuário na moeda do saque e reserva o valor com a tarifa; pedidos concorrentes
+// esperam o lock e veem o saldo disponível já reduzido
+func holdFunds(txDB *gorm.DB, tx d.Transaction) error {
+	balance, err := lockBalance(txDB, tx.UserID, tx.Amount.Currency)
+	if err != nil {
+		return err
+	}
+	if balance.Amount().Cmp(tx.Total()) < 0 {
+		return d.ErrInsufficientFunds
+	}
+
+	return ledger.Hold(txDB, tx)
+}
+
+// lockBalance trava a linha de saldo do usuário na moeda, criando-a se ainda não existir
+func lockBalance(txDB *gorm.DB, userID uint, currency string) (*d.Balance, error) {
+	var balance d.Balance
+	if err := txDB.Clauses(clause.Locking{Strength: "UPDATE"}).
+		FirstOrCreate(&balance, d.Balance{UserID: userID, Currency: currency}).Error; err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
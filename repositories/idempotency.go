@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ d.IdempotencyRepository = &GormRepository{}
+
+var ErrIdempotencyLeaseLost = errors.New("idempotency key lease lost")
+
+func (r *GormRepository) ReserveIdempotencyKey(rec d.IdempotencyRecord) (*d.IdempotencyRecord, bool, error) {
+	var existing d.IdempotencyRecord
+	created := false
+
+	err := r.db.Transaction(func(txDB *gorm.DB) error {
+		// Chaves expiradas podem ser reaproveitadas, assim como as que ficaram em andamento
+		// depois de vencida a reserva
+		now := time.Now()
+		if err := txDB.Where("user_id = ? AND key = ? AND (expires_at <= ? OR (completed = ? AND locked_until <= ?))",
+			rec.UserID, rec.Key, now, false, now).
+			Delete(&d.IdempotencyRecord{}).Error; err != nil {
+			return err
+		}
+
+		res := txDB.Clauses(clause.OnConflict{DoNothing: true}).Create(&rec)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			created = true
+			return nil
+		}
+
+		return txDB.Where("user_id = ? AND key = ?", rec.UserID, rec.Key).First(&existing).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if created {
+		return &rec, true, nil
+	}
+	return &existing, false, nil
+}
+
+func (r *GormRepository) CompleteIdempotencyKey(rec d.IdempotencyRecord) error {
+	res := r.db.Model(&d.IdempotencyRecord{}).
+		Where("user_id = ? AND key = ? AND lease_token = ?", rec.UserID, rec.Key, rec.LeaseToken).
+		Updates(map[string]interface{}{
+			"status_code":    rec.StatusCode,
+			"response_body":  rec.ResponseBody,
+			"transaction_id": rec.TransactionID,
+			"completed":      true,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrIdempotencyLeaseLost
+	}
+	return nil
+}
+
+func (r *GormRepository) ReleaseIdempotencyKey(rec d.IdempotencyRecord) error {
+	return r.db.Where("user_id = ? AND key = ? AND lease_token = ? AND completed = ?", rec.UserID, rec.Key, rec.LeaseToken, false).
+		Delete(&d.IdempotencyRecord{}).Error
+}
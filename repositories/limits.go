@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ d.LimitRepository = &GormRepository{}
+
+// statuses que não consomem limite: o valor voltou ou nunca saiu do saldo
+var limitExcludedStatuses = []d.TransactionStatus{d.StatusFailed, d.StatusRefunded, d.StatusRejected, d.StatusCancelled}
+
+func (r *GormRepository) GetLimitOverride(userID uint, currency string) (*d.LimitOverride, error) {
+	var o d.LimitOverride
+	err := r.db.Where("user_id = ? AND currency = ?", userID, currency).First(&o).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *GormRepository) SaveLimitOverride(o d.LimitOverride) error {
+	o.UpdatedAt = time.Now()
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "currency"}},
+		UpdateAll: true,
+	}).Create(&o).Error
+}
+
+func (r *GormRepository) DeleteLimitOverride(userID uint, currency string) error {
+	return r.db.Where("user_id = ? AND currency = ?", userID, currency).Delete(&d.LimitOverride{}).Error
+}
+
+func (r *GormRepository) GetLimitUsage(userID uint, currency string, now time.Time) (d.LimitUsage, error) {
+	return limitUsage(r.db, userID, currency, now)
+}
+
+func (r *GormRepository) SaveDepositWithinLimits(tx d.Transaction, limits d.Limits) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		if _, err := lockBalance(txDB, tx.UserID, tx.Amount.Currency); err != nil {
+			return err
+		}
+		if err := checkLimits(txDB, tx, limits); err != nil {
+			return err
+		}
+		return saveWithOutbox(txDB, tx, "accepted by API")
+	})
+}
+
+func (r *GormRepository) SaveWithdrawalWithinLimits(tx d.Transaction, limits d.Limits) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		// o lock do saldo é o mesmo da reserva: a soma abaixo já vê os saques concorrentes gravados
+		if _, err := lockBalance(txDB, tx.UserID, tx.Amount.Currency); err != nil {
+			return err
+		}
+		if err := checkLimits(txDB, tx, limits); err != nil {
+			return err
+		}
+		if tx.Status == d.StatusAwaitingApproval {
+			return holdForApproval(txDB, tx)
+		}
+		if err := holdFunds(txDB, tx); err != nil {
+			return err
+		}
+		return saveWithOutbox(txDB, tx, "accepted by API")
+	})
+}
+
+// checkLimits soma a transação ao volume do dia e do mês; deve rodar com o saldo do usuário travado
+func checkLimits(txDB *gorm.DB, tx d.Transaction, limits d.Limits) error {
+	usage, err := limitUsage(txDB, tx.UserID, tx.Amount.Currency, time.Now())
+	if err != nil {
+		return err
+	}
+
+	exceeds := func(limit, used int64) bool {
+		return limit > 0 && used+tx.Amount.Units > limit
+	}
+	money := func(units int64) d.Money {
+		return d.NewMoney(units, tx.Amount.Currency)
+	}
+
+	switch tx.Type {
+	case d.WithdrawTransaction:
+		switch {
+		case limits.MaxWithdrawal > 0 && tx.Amount.Units > limits.MaxWithdrawal:
+			return fmt.Errorf("%w: maximum withdrawal is %s", d.ErrLimitExceeded, money(limits.MaxWithdrawal))
+		case exceeds(limits.DailyWithdrawal, usage.DailyWithdrawal):
+			return fmt.Errorf("%w: daily withdrawal limit is %s", d.ErrLimitExceeded, money(limits.DailyWithdrawal))
+		case exceeds(limits.MonthlyWithdrawal, usage.MonthlyWithdrawal):
+			return fmt.Errorf("%w: monthly withdrawal limit is %s", d.ErrLimitExceeded, money(limits.MonthlyWithdrawal))
+		}
+	case d.DepositTransaction:
+		if exceeds(limits.DailyDeposit, usage.DailyDeposit) {
+			return fmt.Errorf("%w: daily deposit limit is %s", d.ErrLimitExceeded, money(limits.DailyDeposit))
+		}
+	}
+	return nil
+}
+
+func limitUsage(db *gorm.DB, userID uint, currency string, now time.Time) (d.LimitUsage, error) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var usage d.LimitUsage
+	var err error
+	if usage.DailyWithdrawal, err = volumeSince(db, userID, currency, d.WithdrawTransaction, day); err != nil {
+		return usage, err
+	}
+	if usage.MonthlyWithdrawal, err = volumeSince(db, userID, currency, d.WithdrawTransaction, month); err != nil {
+		return usage, err
+	}
+	if usage.DailyDeposit, err = volumeSince(db, userID, currency, d.DepositTransaction, day); err != nil {
+		return usage, err
+	}
+	return usage, nil
+}
+
+func volumeSince(db *gorm.DB, userID uint, currency, txType string, since time.Time) (int64, error) {
+	var total int64
+	err := db.Model(&d.Transaction{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("user_id = ? AND currency = ? AND type = ? AND created_at >= ? AND status NOT IN ?",
+			userID, currency, txType, since, limitExcludedStatuses).
+		Scan(&total).Error
+	return total, err
+}
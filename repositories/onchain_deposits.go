@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var _ d.DepositRepository = &GormRepository{}
+
+func (r *GormRepository) GetDepositAddress(userID uint) (*d.DepositAddress, error) {
+	var addr d.DepositAddress
+	err := r.db.Where("user_id = ?", userID).First(&addr).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, d.ErrDepositAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
+
+func (r *GormRepository) SaveDepositAddress(addr d.DepositAddress) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoNothing: true,
+	}).Create(&addr).Error
+}
+
+func (r *GormRepository) UsersByDepositAddress(addresses []string) (map[string]uint, error) {
+	users := make(map[string]uint)
+	if len(addresses) == 0 {
+		return users, nil
+	}
+
+	var rows []d.DepositAddress
+	if err := r.db.Where("address IN ?", addresses).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		users[row.Address] = row.UserID
+	}
+	return users, nil
+}
+
+func (r *GormRepository) LastScannedBlock() (*d.ScannedBlock, error) {
+	var block d.ScannedBlock
+	err := r.db.Order("number DESC").First(&block).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+func (r *GormRepository) GetScannedBlock(number int64) (*d.ScannedBlock, error) {
+	var block d.ScannedBlock
+	err := r.db.Where("number = ?", number).First(&block).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// RecordScannedBlock avança o cursor: bloco e depósitos entram juntos, então um restart
+// no meio da gravação relê o bloco inteiro
+func (r *GormRepository) RecordScannedBlock(block d.ScannedBlock, deposits []d.OnchainDeposit, keepFrom int64) error {
+	if block.ScannedAt.IsZero() {
+		block.ScannedAt = time.Now()
+	}
+
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		if err := txDB.Create(&block).Error; err != nil {
+			return err
+		}
+
+		for _, dep := range deposits {
+			dep.Status = d.OnchainDepositSeen
+			// a mesma transação pode reaparecer em outro bloco após uma reorganização
+			if err := txDB.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "tx_hash"}},
+				DoUpdates: clause.AssignmentColumns([]string{"block_number", "block_hash", "status", "updated_at"}),
+				Where:     clause.Where{Exprs: []clause.Expression{clause.Neq{Column: clause.Column{Table: "onchain_deposits", Name: "status"}, Value: d.OnchainDepositCredited}}},
+			}).Create(&dep).Error; err != nil {
+				return err
+			}
+		}
+
+		return txDB.Where("number < ?", keepFrom).Delete(&d.ScannedBlock{}).Error
+	})
+}
+
+func (r *GormRepository) RewindScan(number int64) ([]d.OnchainDeposit, error) {
+	var orphaned []d.OnchainDeposit
+
+	err := r.db.Transaction(func(txDB *gorm.DB) error {
+		if err := txDB.Where("block_number > ?", number).Find(&orphaned).Error; err != nil {
+			return err
+		}
+
+		// depósitos ainda não creditados voltam a ser detectados se a transação for reincluída;
+		// os já creditados ficam ORPHANED para conciliação manual
+		if err := txDB.Where("block_number > ? AND status = ?", number, d.OnchainDepositSeen).
+			Delete(&d.OnchainDeposit{}).Error; err != nil {
+			return err
+		}
+		if err := txDB.Model(&d.OnchainDeposit{}).
+			Where("block_number > ? AND status = ?", number, d.OnchainDepositCredited).
+			Update("status", d.OnchainDepositOrphaned).Error; err != nil {
+			return err
+		}
+
+		return txDB.Where("number > ?", number).Delete(&d.ScannedBlock{}).Error
+	})
+
+	return orphaned, err
+}
+
+func (r *GormRepository) ListSeenDeposits(maxBlock int64) ([]d.OnchainDeposit, error) {
+	var deposits []d.OnchainDeposit
+	err := r.db.Where("status = ? AND block_number <= ?", d.OnchainDepositSeen, maxBlock).
+		Order("block_number ASC").
+		Find(&deposits).Error
+	return deposits, err
+}
+
+func (r *GormRepository) CreditOnchainDeposit(dep d.OnchainDeposit, tx d.Transaction) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		result := txDB.Model(&d.OnchainDeposit{}).
+			Where("tx_hash = ? AND status = ? AND block_hash = ?", dep.TxHash, d.OnchainDepositSeen, dep.BlockHash).
+			Updates(map[string]interface{}{
+				"status":         d.OnchainDepositCredited,
+				"transaction_id": tx.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: deposit %s is no longer pending in block %s", d.ErrStatusConflict, dep.TxHash, dep.BlockHash)
+		}
+
+		return saveWithOutbox(txDB, tx, "on-chain deposit "+dep.TxHash)
+	})
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"encoding/json"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+)
+
+var _ d.OutboxRepository = &GormRepository{}
+
+func (r *GormRepository) SaveTransactionWithOutbox(tx d.Transaction) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		return saveWithOutbox(txDB, tx, "accepted by API")
+	})
+}
+
+// saveWithOutbox grava a transação, o status inicial no histórico e a mensagem do outbox
+// na transação do banco recebida
+func saveWithOutbox(txDB *gorm.DB, tx d.Transaction, reason string) error {
+	if err := txDB.Omit("User").Create(&tx).Error; err != nil {
+		return err
+	}
+
+	if err := txDB.Create(&d.TransactionStatusHistory{
+		TransactionID: tx.ID,
+		To:            tx.Status,
+		Reason:        reason,
+	}).Error; err != nil {
+		return err
+	}
+
+	return enqueueOutbox(txDB, tx)
+}
+
+// enqueueOutbox grava a mensagem do outbox de uma transação já gravada
+func enqueueOutbox(txDB *gorm.DB, tx d.Transaction) error {
+	payload, err := json.Marshal(tx)
+	if err != nil {
+		return err
+	}
+
+	return txDB.Create(&d.OutboxMessage{
+		TransactionID: tx.ID,
+		Payload:       payload,
+		Status:        d.OutboxStatusPending,
+	}).Error
+}
+
+// FetchPendingOutbox retorna as mensagens na ordem de gravação, preservando a ordem por usuário
+func (r *GormRepository) FetchPendingOutbox(limit int) ([]d.OutboxMessage, error) {
+	var msgs []d.OutboxMessage
+	err := r.db.Where("status = ?", d.OutboxStatusPending).
+		Order("id ASC").
+		Limit(limit).
+		Find(&msgs).Error
+	return msgs, err
+}
+
+func (r *GormRepository) MarkOutboxSent(id uint) error {
+	now := time.Now()
+	return r.db.Model(&d.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     d.OutboxStatusSent,
+			"sent_at":    &now,
+			"last_error": "",
+		}).Error
+}
+
+func (r *GormRepository) MarkOutboxFailed(id uint, reason string) error {
+	return r.db.Model(&d.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
+
+func (r *GormRepository) MarkOutboxInvalid(id uint, reason string) error {
+	return r.db.Model(&d.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     d.OutboxStatusInvalid,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+var (
+	_ domain.RateLimiter = &MemoryRateLimiter{}
+	_ domain.RateLimiter = &RedisRateLimiter{}
+)
+
+// MemoryRateLimiter aplica o mesmo GCRA do RedisRateLimiter com o estado na memória do processo:
+// serve para testes e para uma única instância da API, não para réplicas
+type MemoryRateLimiter struct {
+	Limits map[string]domain.RateLimit
+	// Now substitui o relógio nos testes
+	Now func() time.Time
+
+	mu        sync.Mutex
+	tat       map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewMemoryRateLimiter usa DefaultRateLimits quando limits é nil
+func NewMemoryRateLimiter(limits map[string]domain.RateLimit) *MemoryRateLimiter {
+	if limits == nil {
+		limits = DefaultRateLimits
+	}
+	return &MemoryRateLimiter{Limits: limits, Now: time.Now, tat: make(map[string]time.Time)}
+}
+
+func (m *MemoryRateLimiter) Allow(class, key string) (domain.RateLimitResult, error) {
+	limit, ok := m.Limits[class]
+	if !ok || limit.Limit <= 0 {
+		return domain.RateLimitResult{Allowed: true}, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.Now()
+	key = class + ":" + key
+	// chaves com a cota cheia não guardam nada de útil; a varredura roda no máximo uma vez por minuto
+	if now.Sub(m.lastSweep) >= time.Minute {
+		for k, tat := range m.tat {
+			if !tat.After(now) {
+				delete(m.tat, k)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	tat, ok := m.tat[key]
+	if !ok || tat.Before(now) {
+		tat = now
+	}
+	newTAT := tat.Add(limit.Interval())
+	if allowAt := newTAT.Add(-limit.Period); allowAt.After(now) {
+		return limit.Result(false, tat.Sub(now), allowAt.Sub(now)), nil
+	}
+	m.tat[key] = newTAT
+	return limit.Result(true, newTAT.Sub(now), 0), nil
+}
+
+func (m *MemoryRateLimiter) CheckTransactionRateLimit(userID uint) error {
+	return checkTransactionRateLimit(m, userID)
+}
@@ -0,0 +1,122 @@
+package repositories_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRateLimiter_GCRA(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	limiter := repositories.NewMemoryRateLimiter(map[string]domain.RateLimit{
+		domain.RateLimitUser: {Limit: 3, Period: 3 * time.Second},
+	})
+	limiter.Now = func() time.Time { return now }
+
+	// a cota inteira pode ser usada de uma vez
+	for want := 2; want >= 0; want-- {
+		res, err := limiter.Allow(domain.RateLimitUser, "1")
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, want, res.Remaining)
+	}
+
+	res, err := limiter.Allow(domain.RateLimitUser, "1")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, time.Second, res.RetryAfter)
+	assert.Equal(t, 3*time.Second, res.ResetAfter)
+
+	// outra chave tem a própria cota, e classes sem limite sempre passam
+	res, err = limiter.Allow(domain.RateLimitUser, "2")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	res, err = limiter.Allow(domain.RateLimitIP, "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// a cota volta uma requisição por intervalo, sem janela que recomeça a cada acesso
+	now = now.Add(time.Second)
+	res, err = limiter.Allow(domain.RateLimitUser, "1")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	res, err = limiter.Allow(domain.RateLimitUser, "1")
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+
+	now = now.Add(10 * time.Second)
+	res, err = limiter.Allow(domain.RateLimitUser, "1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.Remaining)
+}
+
+func TestMemoryRateLimiter_ConcurrentRequestsCannotExceedLimit(t *testing.T) {
+	limiter := repositories.NewMemoryRateLimiter(nil)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 25)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = limiter.CheckTransactionRateLimit(7)
+		}(i)
+	}
+	wg.Wait()
+
+	allowed := 0
+	for _, err := range errs {
+		if err == nil {
+			allowed++
+			continue
+		}
+		assert.ErrorIs(t, err, domain.ErrRateLimited)
+		var limited *domain.RateLimitError
+		require.True(t, errors.As(err, &limited))
+		assert.Positive(t, limited.Result.RetryAfter)
+	}
+	assert.Equal(t, 10, allowed)
+}
+
+func TestRedisRateLimiter_Allow(t *testing.T) {
+	client := new(mocks.RedisClientInterface)
+	limiter := repositories.NewRedisRateLimiter(client, map[string]domain.RateLimit{
+		domain.RateLimitEndpoint:    {Limit: 30, Period: time.Minute},
+		domain.RateLimitTransaction: {Limit: 10, Period: time.Minute},
+	})
+
+	// intervalo de 2s e período de 60s, em microssegundos
+	client.On("Eval", mock.Anything, mock.AnythingOfType("string"), []string{"rate_limit:endpoint:user:1:POST /api/deposit"},
+		int64(2_000000), int64(60_000000)).Return([]int64{1, 10_000000, 0}, nil).Once()
+	client.On("Eval", mock.Anything, mock.AnythingOfType("string"), []string{"rate_limit:transaction:1"},
+		int64(6_000000), int64(60_000000)).Return([]int64{0, 60_000000, 4_500000}, nil).Once()
+
+	res, err := limiter.Allow(domain.RateLimitEndpoint, "user:1:POST /api/deposit")
+	require.NoError(t, err)
+	assert.Equal(t, domain.RateLimitResult{Allowed: true, Limit: 30, Remaining: 25, ResetAfter: 10 * time.Second}, res)
+
+	err = limiter.CheckTransactionRateLimit(1)
+	var limited *domain.RateLimitError
+	require.True(t, errors.As(err, &limited))
+	assert.Equal(t, 4500*time.Millisecond, limited.Result.RetryAfter)
+	assert.Zero(t, limited.Result.Remaining)
+
+	// classes sem limite não vão ao Redis
+	res, err = limiter.Allow(domain.RateLimitIP, "10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	client.AssertExpectations(t)
+
+	client.On("Eval", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused"))
+	assert.Error(t, limiter.CheckTransactionRateLimit(1))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
@@ -15,15 +16,17 @@ var (
 	RedisCtx = context.Background()
 )
 
-const (
-	transactionLimit  = 10
-	transactionWindow = time.Minute
-)
+// DefaultRateLimits mantém o limite histórico de 10 transações por minuto por usuário
+var DefaultRateLimits = map[string]domain.RateLimit{
+	domain.RateLimitTransaction: {Limit: 10, Period: time.Minute},
+}
 
 type RedisClient struct{}
 
+// RedisRateLimiter guarda o estado de cada chave no Redis, compartilhado entre as réplicas da API
 type RedisRateLimiter struct {
 	Client domain.RedisClientInterface // Interface do cliente Redis
+	Limits map[string]domain.RateLimit // limite por classe de chave
 }
 
 // InitRedis conecta ao Redis e testa a conexão
@@ -43,8 +46,12 @@ func InitRedis(Host string, DB int) domain.RedisClientInterface {
 	return &RedisClient{}
 }
 
-func NewRedisRateLimiter(client domain.RedisClientInterface) *RedisRateLimiter {
-	return &RedisRateLimiter{Client: client}
+// NewRedisRateLimiter usa DefaultRateLimits quando limits é nil
+func NewRedisRateLimiter(client domain.RedisClientInterface, limits map[string]domain.RateLimit) *RedisRateLimiter {
+	if limits == nil {
+		limits = DefaultRateLimits
+	}
+	return &RedisRateLimiter{Client: client, Limits: limits}
 }
 
 func (r *RedisClient) Get(ctx context.Context, key string) (int, error) {
@@ -76,22 +83,65 @@ func (r *RedisClient) Expire(ctx context.Context, key string, expiration time.Du
 	return err
 }
 
-func (r *RedisRateLimiter) CheckTransactionRateLimit(userID uint) error {
-	key := fmt.Sprintf("rate_limit:user:%d", userID)
-
-	val, err := r.Client.Get(RedisCtx, key)
+// Eval tenta EVALSHA antes e só envia o script inteiro se o Redis ainda não o tiver em cache
+func (r *RedisClient) Eval(ctx context.Context, script string, keys []string, args ...interface{}) ([]int64, error) {
+	val, err := redis.NewScript(script).Run(ctx, RedisCli, keys, args...).Int64Slice()
 	if err != nil {
-		return fmt.Errorf("erro no Redis: %w", err)
+		return nil, fmt.Errorf("erro ao executar script no Redis: %w", err)
 	}
+	return val, nil
+}
 
-	if val >= transactionLimit {
-		return fmt.Errorf("limite de transações atingido")
+// gcraScript aplica o GCRA numa única ida ao Redis. A chave guarda o TAT (theoretical arrival
+// time) em microssegundos, no relógio do próprio Redis para as réplicas não divergirem.
+// ARGV[1] é o intervalo entre requisições e ARGV[2] o período, ambos em microssegundos.
+// Retorna {permitida, reset_after, retry_after}, também em microssegundos
+const gcraScript = `
+local interval = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+if tat < now then
+  tat = now
+end
+local new_tat = tat + interval
+local allow_at = new_tat - period
+if allow_at > now then
+  return {0, tat - now, allow_at - now}
+end
+redis.call('SET', KEYS[1], new_tat, 'PX', math.ceil((new_tat - now) / 1000))
+return {1, new_tat - now, 0}
+`
+
+func (r *RedisRateLimiter) Allow(class, key string) (domain.RateLimitResult, error) {
+	limit, ok := r.Limits[class]
+	if !ok || limit.Limit <= 0 {
+		return domain.RateLimitResult{Allowed: true}, nil
 	}
 
-	_, err = r.Client.Incr(RedisCtx, key)
+	res, err := r.Client.Eval(RedisCtx, gcraScript, []string{"rate_limit:" + class + ":" + key},
+		limit.Interval().Microseconds(), limit.Period.Microseconds())
 	if err != nil {
-		return err
+		return domain.RateLimitResult{}, err
+	}
+	if len(res) != 3 {
+		return domain.RateLimitResult{}, fmt.Errorf("resposta inesperada do rate limiter: %v", res)
 	}
+	return limit.Result(res[0] == 1, time.Duration(res[1])*time.Microsecond, time.Duration(res[2])*time.Microsecond), nil
+}
+
+func (r *RedisRateLimiter) CheckTransactionRateLimit(userID uint) error {
+	return checkTransactionRateLimit(r, userID)
+}
 
-	return r.Client.Expire(RedisCtx, key, transactionWindow)
+func checkTransactionRateLimit(limiter domain.RateLimiter, userID uint) error {
+	res, err := limiter.Allow(domain.RateLimitTransaction, strconv.FormatUint(uint64(userID), 10))
+	if err != nil {
+		return fmt.Errorf("erro no rate limiter: %w", err)
+	}
+	if !res.Allowed {
+		return &domain.RateLimitError{Result: res}
+	}
+	return nil
 }
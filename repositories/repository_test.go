@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
 	"github.com/gabrielksneiva/go-financial-transactions/repositories"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -32,7 +33,7 @@ func TestGormRepository_SaveAndGetByUser(t *testing.T) {
 	tx := domain.Transaction{
 		ID:        "tx-1",
 		UserID:    123,
-		Amount:    200.0,
+		Amount:    domain.NewMoney(200_000000, "TRX"),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		Type:      domain.DepositTransaction,
@@ -64,15 +65,15 @@ func TestGormRepository_UpdateBalance_InsertNewUser(t *testing.T) {
 
 	tx := domain.Transaction{
 		UserID: 1,
-		Amount: 100.0,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 	}
 
 	err := repo.UpdateBalance(tx)
 	assert.NoError(t, err)
 
-	balance, err := repo.GetBalance(1)
+	balance, err := repo.GetBalance(1, "TRX")
 	assert.NoError(t, err)
-	assert.Equal(t, 100.0, balance.Amount)
+	assert.Equal(t, domain.NewMoney(100_000000, "TRX"), balance.Amount())
 }
 
 func TestGormRepository_UpdateBalance_ExistingUser(t *testing.T) {
@@ -81,28 +82,47 @@ func TestGormRepository_UpdateBalance_ExistingUser(t *testing.T) {
 
 	initial := domain.Transaction{
 		UserID: 2,
-		Amount: 100.0,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 	}
 	err := repo.UpdateBalance(initial)
 	assert.NoError(t, err)
 
 	additional := domain.Transaction{
 		UserID: 2,
-		Amount: 50.0,
+		Amount: domain.NewMoney(50_000000, "TRX"),
 	}
 	err = repo.UpdateBalance(additional)
 	assert.NoError(t, err)
 
-	balance, err := repo.GetBalance(2)
+	balance, err := repo.GetBalance(2, "TRX")
 	assert.NoError(t, err)
-	assert.Equal(t, 150.0, balance.Amount)
+	assert.Equal(t, domain.NewMoney(150_000000, "TRX"), balance.Amount())
+}
+
+func TestGormRepository_BalancesPerCurrency(t *testing.T) {
+	db := setupTestDB(t)
+	repo := repositories.NewGormRepository(db)
+
+	assert.NoError(t, repo.UpdateBalance(domain.Transaction{UserID: 3, Amount: domain.NewMoney(100_000000, "TRX")}))
+	assert.NoError(t, repo.UpdateBalance(domain.Transaction{UserID: 3, Amount: domain.NewMoney(40_000000, "USDT")}))
+	assert.NoError(t, repo.UpdateBalance(domain.Transaction{UserID: 3, Amount: domain.NewMoney(2_000000, "USDT")}))
+
+	usdt, err := repo.GetBalance(3, "USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(42_000000, "USDT"), usdt.Amount())
+
+	balances, err := repo.ListBalances(3)
+	assert.NoError(t, err)
+	assert.Len(t, balances, 2)
+	assert.Equal(t, domain.NewMoney(100_000000, "TRX"), balances[0].Amount())
+	assert.Equal(t, domain.NewMoney(42_000000, "USDT"), balances[1].Amount())
 }
 
 func TestGormRepository_GetBalance_NotFound(t *testing.T) {
 	db := setupTestDB(t)
 	repo := repositories.NewGormRepository(db)
 
-	_, err := repo.GetBalance(999)
+	_, err := repo.GetBalance(999, "TRX")
 	assert.Error(t, err)
 }
 
@@ -113,7 +133,7 @@ func TestGormRepository_Save_InvalidTransaction(t *testing.T) {
 	tx := domain.Transaction{
 		ID:     "invalid",
 		UserID: 999,
-		Amount: 100,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 		Type:   "deposit",
 	}
 
@@ -129,7 +149,7 @@ func TestGormRepository_Save_TableMissing(t *testing.T) {
 	tx := domain.Transaction{
 		ID:        "invalid",
 		UserID:    999,
-		Amount:    100.0,
+		Amount:    domain.NewMoney(100_000000, "TRX"),
 		Type:      "deposit",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -152,7 +172,7 @@ func TestGormRepository_UpdateBalance_TableMissing(t *testing.T) {
 	_ = db.Migrator().DropTable(&domain.Balance{})
 	tx := domain.Transaction{
 		UserID: 999,
-		Amount: 100.0,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 	}
 	err := repo.UpdateBalance(tx)
 	assert.Error(t, err)
@@ -163,19 +183,19 @@ func TestGormRepository_UpdateBalance_ConflictUpdate(t *testing.T) {
 	repo := repositories.NewGormRepository(db)
 	tx1 := domain.Transaction{
 		UserID: 777,
-		Amount: 100.0,
+		Amount: domain.NewMoney(100_000000, "TRX"),
 	}
 	err := repo.UpdateBalance(tx1)
 	assert.NoError(t, err)
 	tx2 := domain.Transaction{
 		UserID: 777,
-		Amount: 50.0,
+		Amount: domain.NewMoney(50_000000, "TRX"),
 	}
 	err = repo.UpdateBalance(tx2)
 	assert.NoError(t, err)
-	balance, err := repo.GetBalance(777)
+	balance, err := repo.GetBalance(777, "TRX")
 	assert.NoError(t, err)
-	assert.Equal(t, 150.0, balance.Amount)
+	assert.Equal(t, domain.NewMoney(150_000000, "TRX"), balance.Amount())
 }
 
 func TestMigrate_Error_WithMock(t *testing.T) {
@@ -191,3 +211,286 @@ func TestMigrate_Error_WithMock(t *testing.T) {
 	err = repositories.CallMigrate(db)
 	assert.Error(t, err)
 }
+
+func TestMigrate_PostsOpeningBalances(t *testing.T) {
+	db := setupTestDB(t)
+	// saldos gravados antes do razão existir
+	assert.NoError(t, db.Create(&domain.Balance{UserID: 1, Currency: "TRX", Units: 100_000000}).Error)
+	assert.NoError(t, db.Create(&domain.Balance{UserID: 2, Currency: "USDT", Units: 5_000000}).Error)
+
+	assert.NoError(t, repositories.CallMigrate(db))
+	// rodar de novo não lança o saldo outra vez
+	assert.NoError(t, repositories.CallMigrate(db))
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.True(t, report.OK(), "%+v", report)
+
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ? AND currency = ?", 1, "TRX").Error)
+	assert.Equal(t, int64(100_000000), balance.Units)
+
+	var equity domain.LedgerAccount
+	assert.NoError(t, db.First(&equity, "code = ?", ledger.SystemAccount(ledger.AccountOpeningEquity, "TRX")).Error)
+	assert.Equal(t, int64(-100_000000), equity.Balance.Units)
+
+	var entries int64
+	assert.NoError(t, db.Model(&domain.JournalEntry{}).Count(&entries).Error)
+	assert.Equal(t, int64(2), entries)
+}
+
+func TestGormRepository_IdempotencyKeys(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.IdempotencyRecord{}))
+	repo := repositories.NewGormRepository(db)
+
+	rec := domain.IdempotencyRecord{
+		UserID:      1,
+		Key:         "key-1",
+		RequestHash: "hash-1",
+		LeaseToken:  "lease-1",
+		LockedUntil: time.Now().Add(time.Minute),
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	_, created, err := repo.ReserveIdempotencyKey(rec)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	existing, created, err := repo.ReserveIdempotencyKey(rec)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.False(t, existing.Completed)
+
+	err = repo.CompleteIdempotencyKey(domain.IdempotencyRecord{
+		UserID: 1, Key: "key-1", LeaseToken: "lease-1", StatusCode: 202, ResponseBody: []byte(`{"ok":true}`), TransactionID: "tx-1",
+	})
+	assert.NoError(t, err)
+
+	existing, _, err = repo.ReserveIdempotencyKey(rec)
+	assert.NoError(t, err)
+	assert.True(t, existing.Completed)
+	assert.Equal(t, 202, existing.StatusCode)
+	assert.Equal(t, "tx-1", existing.TransactionID)
+	assert.Equal(t, "hash-1", existing.RequestHash)
+
+	// a mesma chave de outro usuário é independente
+	_, created, err = repo.ReserveIdempotencyKey(domain.IdempotencyRecord{UserID: 2, Key: "key-1", ExpiresAt: time.Now().Add(time.Hour)})
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// a resposta já gravada não é liberada
+	assert.NoError(t, repo.ReleaseIdempotencyKey(rec))
+	_, created, err = repo.ReserveIdempotencyKey(rec)
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	other := domain.IdempotencyRecord{UserID: 1, Key: "key-2", LeaseToken: "lease-2", LockedUntil: time.Now().Add(time.Minute), ExpiresAt: time.Now().Add(time.Hour)}
+	_, created, err = repo.ReserveIdempotencyKey(other)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.NoError(t, repo.ReleaseIdempotencyKey(other))
+	_, created, err = repo.ReserveIdempotencyKey(other)
+	assert.NoError(t, err)
+	assert.True(t, created)
+}
+
+func TestGormRepository_IdempotencyKeyStaleLease(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.IdempotencyRecord{}))
+	repo := repositories.NewGormRepository(db)
+
+	// o processo que reservou a chave caiu antes de gravar a resposta
+	crashed := domain.IdempotencyRecord{UserID: 1, Key: "key-1", RequestHash: "a", LeaseToken: "old", LockedUntil: time.Now().Add(-time.Second), ExpiresAt: time.Now().Add(time.Hour)}
+	_, created, err := repo.ReserveIdempotencyKey(crashed)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	retry := domain.IdempotencyRecord{UserID: 1, Key: "key-1", RequestHash: "a", LeaseToken: "new", LockedUntil: time.Now().Add(time.Minute), ExpiresAt: time.Now().Add(time.Hour)}
+	_, created, err = repo.ReserveIdempotencyKey(retry)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// o dono antigo não grava nem libera a chave de quem a assumiu
+	assert.ErrorIs(t, repo.CompleteIdempotencyKey(domain.IdempotencyRecord{UserID: 1, Key: "key-1", LeaseToken: "old", StatusCode: 202}), repositories.ErrIdempotencyLeaseLost)
+	assert.NoError(t, repo.ReleaseIdempotencyKey(crashed))
+
+	existing, created, err := repo.ReserveIdempotencyKey(retry)
+	assert.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "new", existing.LeaseToken)
+	assert.NoError(t, repo.CompleteIdempotencyKey(domain.IdempotencyRecord{UserID: 1, Key: "key-1", LeaseToken: "new", StatusCode: 202}))
+}
+
+func TestGormRepository_IdempotencyKeyExpired(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.IdempotencyRecord{}))
+	repo := repositories.NewGormRepository(db)
+
+	expired := domain.IdempotencyRecord{UserID: 1, Key: "old", RequestHash: "a", ExpiresAt: time.Now().Add(-time.Minute)}
+	_, created, err := repo.ReserveIdempotencyKey(expired)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	fresh := domain.IdempotencyRecord{UserID: 1, Key: "old", RequestHash: "b", ExpiresAt: time.Now().Add(time.Hour)}
+	rec, created, err := repo.ReserveIdempotencyKey(fresh)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "b", rec.RequestHash)
+}
+
+func TestGormRepository_SaveTransactionWithOutbox(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.OutboxMessage{}, &domain.TransactionStatusHistory{}))
+	repo := repositories.NewGormRepository(db)
+
+	tx := domain.Transaction{
+		ID:     "tx-outbox",
+		UserID: 5,
+		User:   domain.User{ID: 5},
+		Amount: domain.NewMoney(3_000000, "TRX"),
+		Type:   domain.DepositTransaction,
+		Status: domain.StatusReceived,
+	}
+	assert.NoError(t, repo.SaveTransactionWithOutbox(tx))
+
+	// a transação já aparece no extrato antes de chegar ao Kafka
+	txs, err := repo.GetByUser(5)
+	assert.NoError(t, err)
+	assert.Len(t, txs, 1)
+	assert.Equal(t, domain.StatusReceived, txs[0].Status)
+
+	history, err := repo.GetStatusHistory(tx.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+	assert.Equal(t, domain.StatusReceived, history[0].To)
+
+	pending, err := repo.FetchPendingOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, tx.ID, pending[0].TransactionID)
+	assert.Contains(t, string(pending[0].Payload), tx.ID)
+
+	assert.NoError(t, repo.MarkOutboxFailed(pending[0].ID, "kafka down"))
+	pending, err = repo.FetchPendingOutbox(10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "kafka down", pending[0].LastError)
+
+	assert.NoError(t, repo.MarkOutboxSent(pending[0].ID))
+	pending, err = repo.FetchPendingOutbox(10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// uma mensagem inválida também sai da fila, mas fica registrada com o motivo
+	broken := domain.OutboxMessage{TransactionID: "tx-broken", Payload: []byte("{not json"), Status: domain.OutboxStatusPending}
+	assert.NoError(t, db.Create(&broken).Error)
+	assert.NoError(t, repo.MarkOutboxInvalid(broken.ID, "invalid payload"))
+	pending, err = repo.FetchPendingOutbox(10)
+	assert.NoError(t, err)
+	assert.Empty(t, pending)
+
+	var stored domain.OutboxMessage
+	assert.NoError(t, db.First(&stored, broken.ID).Error)
+	assert.Equal(t, domain.OutboxStatusInvalid, stored.Status)
+	assert.Equal(t, "invalid payload", stored.LastError)
+}
+
+func TestGormRepository_SaveTransactionWithOutbox_IsAtomic(t *testing.T) {
+	// sem a tabela do outbox a gravação falha e a transação não pode ficar para trás
+	db := setupTestDB(t)
+	repo := repositories.NewGormRepository(db)
+
+	err := repo.SaveTransactionWithOutbox(domain.Transaction{ID: "tx-orphan", UserID: 6, Amount: domain.NewMoney(1, "TRX")})
+	assert.Error(t, err)
+
+	txs, err := repo.GetByUser(6)
+	assert.NoError(t, err)
+	assert.Empty(t, txs)
+}
+
+func TestGormRepository_UpdateTransactionStatus(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.TransactionStatusHistory{}))
+	repo := repositories.NewGormRepository(db)
+
+	tx := domain.Transaction{ID: "tx-status", UserID: 7, Amount: domain.NewMoney(1_000000, "TRX"), Type: domain.WithdrawTransaction}
+	created, err := repositories.EnsureTransaction(db, &tx, "received")
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	// a segunda gravação não altera a linha nem o histórico
+	created, err = repositories.EnsureTransaction(db, &tx, "received again")
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	assert.NoError(t, repo.UpdateTransactionStatus(tx.ID, domain.StatusReceived, domain.StatusPending, "debited"))
+	assert.NoError(t, repo.UpdateTransactionStatus(tx.ID, domain.StatusPending, domain.StatusSending, "sending"))
+	assert.NoError(t, repo.UpdateTransactionStatus(tx.ID, domain.StatusSending, domain.StatusBroadcast, "sent"))
+
+	history, err := repo.GetStatusHistory(tx.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 4)
+	assert.Equal(t, domain.TransactionStatus(""), history[0].From)
+	assert.Equal(t, domain.StatusReceived, history[0].To)
+	assert.Equal(t, domain.StatusSending, history[3].From)
+	assert.Equal(t, domain.StatusBroadcast, history[3].To)
+	assert.Equal(t, "sent", history[3].Reason)
+}
+
+func TestGormRepository_UpdateTransactionStatus_Rejected(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.TransactionStatusHistory{}))
+	repo := repositories.NewGormRepository(db)
+
+	tx := domain.Transaction{ID: "tx-cas", UserID: 8, Amount: domain.NewMoney(1_000000, "TRX"), Type: domain.WithdrawTransaction, Status: domain.StatusPending}
+	_, err := repositories.EnsureTransaction(db, &tx, "pending")
+	assert.NoError(t, err)
+
+	// o status atual não é o esperado: outro processo já mudou a transação
+	err = repo.UpdateTransactionStatus(tx.ID, domain.StatusReceived, domain.StatusPending, "stale")
+	assert.ErrorIs(t, err, domain.ErrStatusConflict)
+
+	// a máquina de estados não permite pular a transmissão
+	err = repo.UpdateTransactionStatus(tx.ID, domain.StatusPending, domain.StatusCompleted, "skip")
+	assert.ErrorIs(t, err, domain.ErrInvalidTransition)
+
+	var stored domain.Transaction
+	assert.NoError(t, db.First(&stored, "id = ?", tx.ID).Error)
+	assert.Equal(t, domain.StatusPending, stored.Status)
+
+	history, err := repo.GetStatusHistory(tx.ID)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+}
+
+func TestGormRepository_WithdrawalAddresses(t *testing.T) {
+	db := setupTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&domain.WithdrawalAddress{}))
+	repo := repositories.NewGormRepository(db)
+
+	addr := &domain.WithdrawalAddress{UserID: 1, Label: "exchange", Currency: "TRX", Chain: domain.ChainTron, Address: "TAddr", ActiveAt: time.Now()}
+	assert.NoError(t, repo.SaveWithdrawalAddress(addr))
+	assert.NotZero(t, addr.ID)
+
+	// o mesmo endereço na mesma rede não entra duas vezes, nem por outro ativo
+	dup := &domain.WithdrawalAddress{UserID: 1, Label: "usdt", Currency: "USDT", Chain: domain.ChainTron, Address: "TAddr"}
+	assert.ErrorIs(t, repo.SaveWithdrawalAddress(dup), domain.ErrWithdrawalAddressExists)
+	assert.NoError(t, repo.SaveWithdrawalAddress(&domain.WithdrawalAddress{UserID: 2, Currency: "TRX", Chain: domain.ChainTron, Address: "TAddr"}))
+
+	list, err := repo.ListWithdrawalAddresses(1)
+	assert.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	_, err = repo.GetWithdrawalAddress(2, addr.ID)
+	assert.ErrorIs(t, err, domain.ErrWithdrawalAddressNotFound)
+	got, err := repo.GetWithdrawalAddress(1, addr.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "exchange", got.Label)
+
+	assert.ErrorIs(t, repo.DeleteWithdrawalAddress(2, addr.ID), domain.ErrWithdrawalAddressNotFound)
+	assert.NoError(t, repo.DeleteWithdrawalAddress(1, addr.ID))
+	_, err = repo.GetWithdrawalAddress(1, addr.ID)
+	assert.ErrorIs(t, err, domain.ErrWithdrawalAddressNotFound)
+}
@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	_ d.ScheduleRepository = &GormRepository{}
+	_ d.LeaderLock         = &GormRepository{}
+)
+
+func (r *GormRepository) CreateSchedule(s *d.Schedule) error {
+	return r.db.Create(s).Error
+}
+
+func (r *GormRepository) ListSchedules(userID uint) ([]d.Schedule, error) {
+	var schedules []d.Schedule
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *GormRepository) GetSchedule(userID, id uint) (*d.Schedule, error) {
+	var s d.Schedule
+	err := r.db.Where("user_id = ? AND id = ?", userID, id).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, d.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *GormRepository) UpdateSchedule(s *d.Schedule) error {
+	// Select("*") grava também os campos zerados, como NextRunAt nulo de um agendamento encerrado
+	res := r.db.Model(&d.Schedule{}).
+		Where("id = ? AND user_id = ?", s.ID, s.UserID).
+		Select("*").Omit("id", "user_id", "created_at").
+		Updates(s)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return d.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) DeleteSchedule(userID, id uint) error {
+	res := r.db.Where("user_id = ? AND id = ?", userID, id).Delete(&d.Schedule{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return d.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *GormRepository) ListDueSchedules(now time.Time, limit int) ([]d.Schedule, error) {
+	var schedules []d.Schedule
+	err := r.db.Where("next_run_at IS NOT NULL AND next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *GormRepository) AdvanceSchedule(run d.ScheduleRun) (bool, error) {
+	updates := map[string]interface{}{
+		"next_run_at": run.NextRunAt,
+		"last_run_at": run.RunAt,
+		"last_error":  run.Error,
+		"runs":        gorm.Expr("runs + 1"),
+	}
+	if run.TransactionID != "" {
+		updates["last_transaction_id"] = run.TransactionID
+	}
+
+	res := r.db.Model(&d.Schedule{}).
+		Where("id = ? AND next_run_at = ?", run.ScheduleID, run.RunAt).
+		Updates(updates)
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *GormRepository) TransactionExists(id string) (bool, error) {
+	var count int64
+	err := r.db.Model(&d.Transaction{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// AcquireLease cria a liderança ou a toma quando está vencida; o detentor atual só a renova
+func (r *GormRepository) AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	lease := d.Lease{Name: name, Holder: holder, ExpiresAt: now.Add(ttl)}
+
+	res := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lease)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return true, nil
+	}
+
+	res = r.db.Model(&d.Lease{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, now).
+		Updates(map[string]interface{}{"holder": holder, "expires_at": lease.ExpiresAt})
+	return res.RowsAffected == 1, res.Error
+}
+
+func (r *GormRepository) ReleaseLease(name, holder string) error {
+	return r.db.Where("name = ? AND holder = ?", name, holder).Delete(&d.Lease{}).Error
+}
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"fmt"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateTransactionStatus faz compare-and-set: a transação só muda de from para to
+// se ainda estiver em from e se a máquina de estados permitir a transição.
+func (r *GormRepository) UpdateTransactionStatus(txID string, from, to d.TransactionStatus, reason string) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		return TransitionStatus(txDB, txID, from, to, reason)
+	})
+}
+
+func (r *GormRepository) GetStatusHistory(txID string) ([]d.TransactionStatusHistory, error) {
+	var history []d.TransactionStatusHistory
+	err := r.db.Where("transaction_id = ?", txID).Order("id ASC").Find(&history).Error
+	return history, err
+}
+
+// ListTransactionsByStatus devolve as transações mais antigas primeiro
+func (r *GormRepository) ListTransactionsByStatus(status d.TransactionStatus, limit int) ([]d.Transaction, error) {
+	var txs []d.Transaction
+	err := r.db.Where("status = ?", status).Order("updated_at ASC").Limit(limit).Find(&txs).Error
+	return txs, err
+}
+
+// TransitionStatus aplica a transição dentro de uma transação do banco já aberta,
+// para que workers possam mudar o status junto com os lançamentos no razão.
+func TransitionStatus(txDB *gorm.DB, txID string, from, to d.TransactionStatus, reason string) error {
+	var current d.Transaction
+	if err := txDB.Select("id", "type", "status").Where("id = ?", txID).First(&current).Error; err != nil {
+		return err
+	}
+
+	if current.Status != from {
+		return fmt.Errorf("%w: %s is %s, expected %s", d.ErrStatusConflict, txID, current.Status, from)
+	}
+	if err := d.ValidateTransition(current.Type, from, to); err != nil {
+		return err
+	}
+
+	result := txDB.Model(&d.Transaction{}).
+		Where("id = ? AND status = ?", txID, from).
+		Update("status", to)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %s is no longer %s", d.ErrStatusConflict, txID, from)
+	}
+
+	return txDB.Create(&d.TransactionStatusHistory{
+		TransactionID: txID,
+		From:          from,
+		To:            to,
+		Reason:        reason,
+	}).Error
+}
+
+// EnsureTransaction grava a transação se ela ainda não existir, registrando o status
+// inicial no histórico. Retorna false quando a linha já existia.
+func EnsureTransaction(txDB *gorm.DB, tx *d.Transaction, reason string) (bool, error) {
+	result := txDB.Omit("User").Clauses(clause.OnConflict{DoNothing: true}).Create(tx)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	return true, txDB.Create(&d.TransactionStatusHistory{
+		TransactionID: tx.ID,
+		To:            tx.Status,
+		Reason:        reason,
+	}).Error
+}
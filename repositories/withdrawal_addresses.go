@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"errors"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"gorm.io/gorm"
+)
+
+var _ d.WithdrawalAddressRepository = &GormRepository{}
+
+func (r *GormRepository) ListWithdrawalAddresses(userID uint) ([]d.WithdrawalAddress, error) {
+	var addrs []d.WithdrawalAddress
+	err := r.db.Where("user_id = ?", userID).Order("id").Find(&addrs).Error
+	return addrs, err
+}
+
+func (r *GormRepository) GetWithdrawalAddress(userID, id uint) (*d.WithdrawalAddress, error) {
+	var addr d.WithdrawalAddress
+	err := r.db.Where("user_id = ? AND id = ?", userID, id).First(&addr).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, d.ErrWithdrawalAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
+
+func (r *GormRepository) SaveWithdrawalAddress(addr *d.WithdrawalAddress) error {
+	return r.db.Transaction(func(txDB *gorm.DB) error {
+		var count int64
+		err := txDB.Model(&d.WithdrawalAddress{}).
+			Where("user_id = ? AND chain = ? AND address = ?", addr.UserID, addr.Chain, addr.Address).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return d.ErrWithdrawalAddressExists
+		}
+		return txDB.Create(addr).Error
+	})
+}
+
+func (r *GormRepository) DeleteWithdrawalAddress(userID, id uint) error {
+	res := r.db.Where("user_id = ? AND id = ?", userID, id).Delete(&d.WithdrawalAddress{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return d.ErrWithdrawalAddressNotFound
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/google/uuid"
+)
+
+var ErrReorgTooDeep = errors.New("chain reorganization deeper than the configured depth")
+
+// Scanner segue a cadeia bloco a bloco procurando transferências de TRX e de tokens TRC-20
+// registrados para os endereços de depósito. O cursor é o último bloco gravado em scanned_blocks, então um restart
+// continua do ponto onde parou; os hashes dos últimos reorgDepth blocos permitem voltar
+// o cursor quando a cadeia é reorganizada.
+type Scanner struct {
+	source        d.BlockSource
+	repo          d.DepositRepository
+	confirmations int64
+	reorgDepth    int64
+	startBlock    int64
+	maxBlocks     int
+}
+
+// NewScanner começa em startBlock quando não há cursor gravado; startBlock <= 0 começa no topo da cadeia
+func NewScanner(source d.BlockSource, repo d.DepositRepository, confirmations, reorgDepth int, startBlock int64) *Scanner {
+	if confirmations < 1 {
+		confirmations = 1
+	}
+	if reorgDepth < 1 {
+		reorgDepth = 1
+	}
+	return &Scanner{
+		source:        source,
+		repo:          repo,
+		confirmations: int64(confirmations),
+		reorgDepth:    int64(reorgDepth),
+		startBlock:    startBlock,
+		maxBlocks:     100,
+	}
+}
+
+// DepositTransactionID deriva o ID da transação do hash on-chain, para que o mesmo
+// depósito nunca seja creditado duas vezes
+func DepositTransactionID(txHash string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte("tron:"+txHash)).String()
+}
+
+// Scan lê até maxBlocks blocos novos e credita os depósitos que já têm confirmações
+// suficientes. Retorna quantos depósitos foram creditados.
+func (s *Scanner) Scan() (int, error) {
+	tip, err := s.source.GetLatestBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < s.maxBlocks; i++ {
+		advanced, err := s.step(tip)
+		if err != nil {
+			return 0, err
+		}
+		if !advanced {
+			break
+		}
+	}
+
+	return s.creditConfirmed(tip)
+}
+
+// step lê o próximo bloco; retorna false quando o cursor já alcançou o topo
+func (s *Scanner) step(tip int64) (bool, error) {
+	last, err := s.repo.LastScannedBlock()
+	if err != nil {
+		return false, err
+	}
+
+	next := s.startBlock
+	switch {
+	case last != nil:
+		next = last.Number + 1
+	case next <= 0:
+		next = tip
+	}
+	if next > tip {
+		return false, nil
+	}
+
+	block, err := s.source.GetBlock(next)
+	if err != nil {
+		return false, err
+	}
+
+	if last != nil && block.ParentHash != last.Hash {
+		log.Printf("🔀 Scanner: reorganização detectada no bloco %d", next)
+		return true, s.rewind(last.Number)
+	}
+
+	deposits, err := s.depositsIn(block)
+	if err != nil {
+		return false, err
+	}
+
+	scanned := d.ScannedBlock{Number: block.Number, Hash: block.Hash, ParentHash: block.ParentHash}
+	if err := s.repo.RecordScannedBlock(scanned, deposits, next-s.reorgDepth); err != nil {
+		return false, err
+	}
+	for _, dep := range deposits {
+		log.Printf("👀 Scanner: depósito %s de %s para usuário %d no bloco %d", dep.TxHash, dep.Amount, dep.UserID, dep.BlockNumber)
+	}
+
+	return true, nil
+}
+
+// rewind volta o cursor até o bloco mais recente cujo hash ainda pertence à cadeia
+func (s *Scanner) rewind(from int64) error {
+	for n := from; n > from-s.reorgDepth; n-- {
+		stored, err := s.repo.GetScannedBlock(n)
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			break
+		}
+
+		current, err := s.source.GetBlock(n)
+		if err != nil {
+			return err
+		}
+		if current.Hash != stored.Hash {
+			continue
+		}
+
+		orphaned, err := s.repo.RewindScan(n)
+		if err != nil {
+			return err
+		}
+		for _, dep := range orphaned {
+			if dep.Status == d.OnchainDepositCredited {
+				log.Printf("🚨 Scanner: depósito %s já creditado ficou órfão após reorganização, conciliação manual necessária", dep.TxHash)
+			}
+		}
+		log.Printf("⏪ Scanner: cursor voltou para o bloco %d", n)
+		return nil
+	}
+
+	return fmt.Errorf("%w: no common ancestor within %d blocks of %d", ErrReorgTooDeep, s.reorgDepth, from)
+}
+
+func (s *Scanner) depositsIn(block *d.Block) ([]d.OnchainDeposit, error) {
+	var candidates []string
+	for _, tr := range block.Transfers {
+		if tr.Success && tr.Amount > 0 {
+			candidates = append(candidates, tr.ToAddress)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	users, err := s.repo.UsersByDepositAddress(candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	var deposits []d.OnchainDeposit
+	for _, tr := range block.Transfers {
+		userID, ok := users[tr.ToAddress]
+		if !ok || !tr.Success || tr.Amount <= 0 {
+			continue
+		}
+		currency := tr.Currency
+		if currency == "" {
+			currency = d.DefaultCurrency
+		}
+		deposits = append(deposits, d.OnchainDeposit{
+			TxHash:      tr.TxID,
+			UserID:      userID,
+			Address:     tr.ToAddress,
+			Amount:      d.NewMoney(tr.Amount, currency),
+			BlockNumber: block.Number,
+			BlockHash:   block.Hash,
+		})
+	}
+	return deposits, nil
+}
+
+func (s *Scanner) creditConfirmed(tip int64) (int, error) {
+	deposits, err := s.repo.ListSeenDeposits(tip - s.confirmations)
+	if err != nil {
+		return 0, err
+	}
+
+	credited := 0
+	for _, dep := range deposits {
+		tx := d.Transaction{
+			ID:            DepositTransactionID(dep.TxHash),
+			UserID:        dep.UserID,
+			Amount:        dep.Amount,
+			Timestamp:     time.Now(),
+			Type:          d.DepositTransaction,
+			WalletAddress: dep.Address,
+			TxHash:        dep.TxHash,
+			Status:        d.StatusReceived,
+		}
+		if err := s.repo.CreditOnchainDeposit(dep, tx); err != nil {
+			log.Printf("⚠️ Scanner: erro ao creditar depósito %s: %v", dep.TxHash, err)
+			continue
+		}
+		log.Printf("💰 Scanner: depósito %s creditado ao usuário %d (transação %s)", dep.TxHash, dep.UserID, tx.ID)
+		credited++
+	}
+
+	return credited, nil
+}
+
+func (s *Scanner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Scanner de depósitos encerrado")
+			return
+		case <-ticker.C:
+			if _, err := s.Scan(); err != nil {
+				log.Printf("❌ Scanner de depósitos: %v", err)
+			}
+		}
+	}
+}
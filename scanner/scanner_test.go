@@ -0,0 +1,204 @@
+package scanner_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/scanner"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeChain é uma cadeia em memória; fork reescreve os blocos a partir de uma altura
+type fakeChain struct {
+	blocks map[int64]*domain.Block
+	tip    int64
+}
+
+func newFakeChain(height int64) *fakeChain {
+	c := &fakeChain{blocks: map[int64]*domain.Block{}}
+	c.extend(height, "a")
+	return c
+}
+
+func (c *fakeChain) GetLatestBlockNumber() (int64, error) {
+	return c.tip, nil
+}
+
+func (c *fakeChain) GetBlock(number int64) (*domain.Block, error) {
+	block, ok := c.blocks[number]
+	if !ok {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	return block, nil
+}
+
+// extend adiciona blocos vazios até height, com hashes marcados pelo branch
+func (c *fakeChain) extend(height int64, branch string) {
+	for n := c.tip + 1; n <= height; n++ {
+		parent := ""
+		if prev, ok := c.blocks[n-1]; ok {
+			parent = prev.Hash
+		}
+		c.blocks[n] = &domain.Block{Number: n, Hash: fmt.Sprintf("%s-%d", branch, n), ParentHash: parent}
+	}
+	c.tip = height
+}
+
+// fork descarta os blocos acima de from e cresce um novo branch até height
+func (c *fakeChain) fork(from, height int64, branch string) {
+	for n := from + 1; n <= c.tip; n++ {
+		delete(c.blocks, n)
+	}
+	c.tip = from
+	c.extend(height, branch)
+}
+
+func (c *fakeChain) transfer(number int64, txID, to string, amount int64) {
+	c.blocks[number].Transfers = append(c.blocks[number].Transfers, domain.Transfer{
+		TxID: txID, FromAddress: "TSender", ToAddress: to, Amount: amount, Success: true,
+	})
+}
+
+func setupScanner(t *testing.T) (*gorm.DB, *repositories.GormRepository) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	assert.NoError(t, db.AutoMigrate(&domain.Transaction{}, &domain.TransactionStatusHistory{}, &domain.OutboxMessage{},
+		&domain.DepositAddress{}, &domain.ScannedBlock{}, &domain.OnchainDeposit{}))
+
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, repo.SaveDepositAddress(domain.DepositAddress{UserID: 1, Address: "TUser1", Index: 1}))
+	assert.NoError(t, repo.SaveDepositAddress(domain.DepositAddress{UserID: 2, Address: "TUser2", Index: 2}))
+	return db, repo
+}
+
+func depositStatus(t *testing.T, db *gorm.DB, txHash string) string {
+	var dep domain.OnchainDeposit
+	if err := db.First(&dep, "tx_hash = ?", txHash).Error; err != nil {
+		return ""
+	}
+	return dep.Status
+}
+
+func TestScanner_CreditsAfterConfirmations(t *testing.T) {
+	chain := newFakeChain(10)
+	db, repo := setupScanner(t)
+
+	chain.extend(12, "a")
+	chain.transfer(11, "tx-user1", "TUser1", 5_000000)
+	chain.transfer(12, "tx-stranger", "TSomeoneElse", 9_000000)
+
+	s := scanner.NewScanner(chain, repo, 3, 10, 10)
+	credited, err := s.Scan()
+	assert.NoError(t, err)
+	assert.Zero(t, credited)
+	assert.Equal(t, domain.OnchainDepositSeen, depositStatus(t, db, "tx-user1"))
+	assert.Equal(t, "", depositStatus(t, db, "tx-stranger"))
+
+	// um novo scanner retoma do cursor gravado, sem reler os blocos
+	chain.extend(14, "a")
+	credited, err = scanner.NewScanner(chain, repo, 3, 10, 10).Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, credited)
+	assert.Equal(t, domain.OnchainDepositCredited, depositStatus(t, db, "tx-user1"))
+
+	last, err := repo.LastScannedBlock()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(14), last.Number)
+
+	var tx domain.Transaction
+	assert.NoError(t, db.First(&tx, "id = ?", scanner.DepositTransactionID("tx-user1")).Error)
+	assert.Equal(t, "tx-user1", tx.TxHash)
+	assert.Equal(t, domain.DepositTransaction, tx.Type)
+	assert.Equal(t, domain.NewMoney(5_000000, "TRX"), tx.Amount)
+	assert.Equal(t, domain.StatusReceived, tx.Status)
+
+	pending, err := repo.FetchPendingOutbox(10)
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	// o mesmo depósito não é creditado de novo
+	chain.extend(20, "a")
+	credited, err = s.Scan()
+	assert.NoError(t, err)
+	assert.Zero(t, credited)
+}
+
+func TestScanner_CreditsTokenDeposit(t *testing.T) {
+	chain := newFakeChain(10)
+	db, repo := setupScanner(t)
+
+	chain.extend(11, "a")
+	chain.blocks[11].Transfers = append(chain.blocks[11].Transfers, domain.Transfer{
+		TxID: "tx-usdt", FromAddress: "TSender", ToAddress: "TUser2", Amount: 25_000000, Currency: "USDT", Success: true,
+	})
+	chain.extend(14, "a")
+
+	credited, err := scanner.NewScanner(chain, repo, 3, 10, 10).Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, credited)
+
+	var tx domain.Transaction
+	assert.NoError(t, db.First(&tx, "id = ?", scanner.DepositTransactionID("tx-usdt")).Error)
+	assert.Equal(t, uint(2), tx.UserID)
+	assert.Equal(t, domain.NewMoney(25_000000, "USDT"), tx.Amount)
+}
+
+func TestScanner_ReorgDropsUnconfirmedDeposit(t *testing.T) {
+	chain := newFakeChain(10)
+	db, repo := setupScanner(t)
+
+	chain.extend(13, "a")
+	chain.transfer(12, "tx-orphan", "TUser1", 1_000000)
+	chain.transfer(13, "tx-moved", "TUser2", 2_000000)
+
+	s := scanner.NewScanner(chain, repo, 5, 10, 10)
+	_, err := s.Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OnchainDepositSeen, depositStatus(t, db, "tx-orphan"))
+
+	// o novo branch a partir do bloco 11 perde tx-orphan e inclui tx-moved em outro bloco
+	chain.fork(11, 14, "b")
+	chain.transfer(14, "tx-moved", "TUser2", 2_000000)
+
+	_, err = s.Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, "", depositStatus(t, db, "tx-orphan"))
+
+	var moved domain.OnchainDeposit
+	assert.NoError(t, db.First(&moved, "tx_hash = ?", "tx-moved").Error)
+	assert.Equal(t, int64(14), moved.BlockNumber)
+	assert.Equal(t, "b-14", moved.BlockHash)
+
+	block, err := repo.GetScannedBlock(12)
+	assert.NoError(t, err)
+	assert.Equal(t, "b-12", block.Hash)
+
+	chain.extend(19, "b")
+	credited, err := s.Scan()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, credited)
+	assert.Equal(t, domain.OnchainDepositCredited, depositStatus(t, db, "tx-moved"))
+}
+
+func TestScanner_ReorgDeeperThanDepthFails(t *testing.T) {
+	chain := newFakeChain(10)
+	_, repo := setupScanner(t)
+
+	chain.extend(20, "a")
+	s := scanner.NewScanner(chain, repo, 1, 3, 10)
+	_, err := s.Scan()
+	assert.NoError(t, err)
+
+	chain.fork(12, 21, "b")
+	_, err = s.Scan()
+	assert.ErrorIs(t, err, scanner.ErrReorgTooDeep)
+}
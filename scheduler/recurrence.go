@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+// Validate confere a recorrência do agendamento; a expressão cron só vale com a recorrência "cron"
+func Validate(s d.Schedule) error {
+	switch s.Recurrence {
+	case d.RecurrenceOnce, d.RecurrenceDaily, d.RecurrenceWeekly, d.RecurrenceMonthly:
+		if s.Cron != "" {
+			return fmt.Errorf("%w: cron is only used with the cron recurrence", d.ErrInvalidSchedule)
+		}
+	case d.RecurrenceCron:
+		if _, err := ParseCron(s.Cron); err != nil {
+			return fmt.Errorf("%w: %v", d.ErrInvalidSchedule, err)
+		}
+	default:
+		return fmt.Errorf("%w: unknown recurrence %q", d.ErrInvalidSchedule, s.Recurrence)
+	}
+	return nil
+}
+
+// FirstRun é a primeira ocorrência a partir de StartAt, inclusive
+func FirstRun(s d.Schedule) (*time.Time, error) {
+	return NextRun(s, s.StartAt.Add(-time.Second))
+}
+
+// NextRun é a primeira ocorrência estritamente depois de after, nunca antes de StartAt;
+// nil quando o agendamento não tem mais ocorrências
+func NextRun(s d.Schedule, after time.Time) (*time.Time, error) {
+	start := s.StartAt.UTC()
+	after = after.UTC()
+
+	var next time.Time
+	switch s.Recurrence {
+	case d.RecurrenceOnce:
+		if !start.After(after) {
+			return nil, nil
+		}
+		next = start
+	case d.RecurrenceDaily, d.RecurrenceWeekly:
+		days := 1
+		if s.Recurrence == d.RecurrenceWeekly {
+			days = 7
+		}
+		next = start
+		if !start.After(after) {
+			periods := int(after.Sub(start)/(time.Duration(days)*24*time.Hour)) + 1
+			next = start.AddDate(0, 0, periods*days)
+		}
+	case d.RecurrenceMonthly:
+		months := 0
+		if !start.After(after) {
+			months = (after.Year()-start.Year())*12 + int(after.Month()-start.Month())
+		}
+		for next = addMonths(start, months); !next.After(after); months++ {
+			next = addMonths(start, months+1)
+		}
+	case d.RecurrenceCron:
+		cron, err := ParseCron(s.Cron)
+		if err != nil {
+			return nil, err
+		}
+		if start.After(after) {
+			after = start.Add(-time.Nanosecond)
+		}
+		if next = cron.Next(after); next.IsZero() {
+			return nil, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown recurrence %q", d.ErrInvalidSchedule, s.Recurrence)
+	}
+	return &next, nil
+}
+
+// addMonths soma meses mantendo o dia de start; em meses mais curtos cai no último dia
+// (31/jan → 28 ou 29/fev → 31/mar)
+func addMonths(start time.Time, months int) time.Time {
+	firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(months), 1, start.Hour(), start.Minute(), start.Second(), 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := start.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
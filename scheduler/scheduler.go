@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/google/uuid"
+)
+
+// LeaseName identifica a liderança do scheduler entre as réplicas
+const LeaseName = "scheduler"
+
+// Executor cria a transação de uma ocorrência pelo mesmo caminho da API (services e outbox);
+// o relay do outbox a publica no Kafka pelo producer.Producer
+type Executor interface {
+	ExecuteSchedule(s d.Schedule, txID string) (*d.Transaction, error)
+}
+
+// Scheduler executa os agendamentos vencidos. Só a réplica que detém a liderança dispara, e cada
+// ocorrência tem um ID de transação fixo: uma ocorrência repetida após uma queda não gera outro
+// saque, apenas avança o agendamento.
+type Scheduler struct {
+	repo      d.ScheduleRepository
+	lock      d.LeaderLock
+	executor  Executor
+	holder    string
+	leaseTTL  time.Duration
+	batchSize int
+}
+
+func NewScheduler(repo d.ScheduleRepository, lock d.LeaderLock, executor Executor, holder string, leaseTTL time.Duration) *Scheduler {
+	return &Scheduler{
+		repo:      repo,
+		lock:      lock,
+		executor:  executor,
+		holder:    holder,
+		leaseTTL:  leaseTTL,
+		batchSize: 100,
+	}
+}
+
+// refusals são as recusas de negócio de uma ocorrência: repetir não muda a resposta, então ela é
+// registrada e pulada. Qualquer outro erro (banco, Redis, limite de requisições) deixa o agendamento
+// como está e a próxima rodada tenta a mesma ocorrência.
+var refusals = []error{
+	d.ErrInsufficientFunds,
+	d.ErrLimitExceeded,
+	d.ErrInvalidAmount,
+	d.ErrAssetNotSupported,
+	d.ErrInvalidSchedule,
+	d.ErrWithdrawalAddressNotFound,
+	d.ErrWithdrawalAddressCoolingDown,
+	d.ErrDestinationChainMismatch,
+	d.ErrRecipientNotFound,
+	d.ErrSelfTransfer,
+	d.ErrMemoTooLong,
+}
+
+func refused(err error) bool {
+	for _, refusal := range refusals {
+		if errors.Is(err, refusal) {
+			return true
+		}
+	}
+	return false
+}
+
+// OccurrenceID deriva o ID da transação do agendamento e do horário da ocorrência
+func OccurrenceID(scheduleID uint, runAt time.Time) string {
+	name := fmt.Sprintf("schedule:%d:%d", scheduleID, runAt.UTC().Unix())
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()
+}
+
+// Tick executa as ocorrências vencidas até now se esta réplica for a líder e retorna quantas avançaram
+func (s *Scheduler) Tick(now time.Time) (int, error) {
+	leader, err := s.lock.AcquireLease(LeaseName, s.holder, s.leaseTTL)
+	if err != nil || !leader {
+		return 0, err
+	}
+
+	due, err := s.repo.ListDueSchedules(now, s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, schedule := range due {
+		advanced, err := s.fire(schedule, now)
+		if err != nil {
+			log.Printf("❌ Scheduler: erro ao executar agendamento %d: %v", schedule.ID, err)
+			continue
+		}
+		if advanced {
+			fired++
+		}
+	}
+	return fired, nil
+}
+
+func (s *Scheduler) fire(schedule d.Schedule, now time.Time) (bool, error) {
+	runAt := *schedule.NextRunAt
+	txID := OccurrenceID(schedule.ID, runAt)
+	run := d.ScheduleRun{ScheduleID: schedule.ID, RunAt: runAt}
+
+	exists, err := s.repo.TransactionExists(txID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		// a transação foi gravada antes de uma queda; falta só avançar o agendamento
+		run.TransactionID = txID
+	} else if tx, err := s.executor.ExecuteSchedule(schedule, txID); err != nil {
+		if !refused(err) {
+			// sem avançar, a próxima rodada repete a ocorrência com o mesmo ID de transação
+			return false, fmt.Errorf("ocorrência de %s: %w", runAt.Format(time.RFC3339), err)
+		}
+		// recusas (saldo, destino em carência, limite) ficam no agendamento e a ocorrência é pulada
+		log.Printf("⚠️ Scheduler: agendamento %d não executado em %s: %v", schedule.ID, runAt.Format(time.RFC3339), err)
+		run.Error = err.Error()
+	} else {
+		run.TransactionID = tx.ID
+		log.Printf("⏰ Scheduler: agendamento %d gerou a transação %s", schedule.ID, tx.ID)
+	}
+
+	// ocorrências perdidas enquanto nenhuma réplica rodava não são repostas: a próxima vem depois de now
+	after := runAt
+	if now.After(after) {
+		after = now
+	}
+	if run.NextRunAt, err = NextRun(schedule, after); err != nil {
+		run.Error = err.Error()
+	}
+
+	return s.repo.AdvanceSchedule(run)
+}
+
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.lock.ReleaseLease(LeaseName, s.holder); err != nil {
+				log.Printf("⚠️ Scheduler: erro ao liberar a liderança: %v", err)
+			}
+			log.Println("🛑 Scheduler encerrado")
+			return
+		case <-ticker.C:
+			fired, err := s.Tick(time.Now())
+			if err != nil {
+				log.Printf("❌ Scheduler: erro ao buscar agendamentos: %v", err)
+			}
+			if fired > 0 {
+				log.Printf("⏰ Scheduler: %d agendamento(s) executado(s)", fired)
+			}
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package scheduler_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/scheduler"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCron_Next(t *testing.T) {
+	cases := []struct {
+		expr  string
+		after string
+		want  string
+	}{
+		{"*/15 * * * *", "2026-03-10T10:07:30Z", "2026-03-10T10:15:00Z"},
+		{"0 9 * * 1-5", "2026-03-13T09:00:00Z", "2026-03-16T09:00:00Z"}, // sexta → segunda
+		{"30 8 1,15 * *", "2026-03-02T00:00:00Z", "2026-03-15T08:30:00Z"},
+		{"0 0 29 2 *", "2026-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"0 12 * * 7", "2026-03-10T00:00:00Z", "2026-03-15T12:00:00Z"}, // 7 é domingo
+		// dia do mês e dia da semana restritos: basta um deles
+		{"0 0 13 * 5", "2026-03-01T00:00:00Z", "2026-03-06T00:00:00Z"},
+	}
+	for _, tc := range cases {
+		cron, err := scheduler.ParseCron(tc.expr)
+		require.NoError(t, err, tc.expr)
+		assert.Equal(t, date(tc.want), cron.Next(date(tc.after)), tc.expr)
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := scheduler.ParseCron(expr)
+		assert.ErrorIs(t, err, scheduler.ErrInvalidCron, expr)
+	}
+}
+
+func TestNextRun_Recurrences(t *testing.T) {
+	start := date("2026-01-31T10:00:00Z")
+
+	monthly := domain.Schedule{Recurrence: domain.RecurrenceMonthly, StartAt: start}
+	next, err := scheduler.NextRun(monthly, start)
+	require.NoError(t, err)
+	assert.Equal(t, date("2026-02-28T10:00:00Z"), *next, "fevereiro cai no último dia")
+	next, err = scheduler.NextRun(monthly, *next)
+	require.NoError(t, err)
+	assert.Equal(t, date("2026-03-31T10:00:00Z"), *next, "março volta ao dia 31")
+
+	weekly := domain.Schedule{Recurrence: domain.RecurrenceWeekly, StartAt: start}
+	next, err = scheduler.NextRun(weekly, date("2026-02-10T00:00:00Z"))
+	require.NoError(t, err)
+	assert.Equal(t, date("2026-02-14T10:00:00Z"), *next)
+
+	once := domain.Schedule{Recurrence: domain.RecurrenceOnce, StartAt: start}
+	first, err := scheduler.FirstRun(once)
+	require.NoError(t, err)
+	assert.Equal(t, start, *first)
+	next, err = scheduler.NextRun(once, start)
+	require.NoError(t, err)
+	assert.Nil(t, next, "execução única não se repete")
+
+	assert.ErrorIs(t, scheduler.Validate(domain.Schedule{Recurrence: "hourly"}), domain.ErrInvalidSchedule)
+	assert.ErrorIs(t, scheduler.Validate(domain.Schedule{Recurrence: domain.RecurrenceCron, Cron: "* *"}), domain.ErrInvalidSchedule)
+	assert.ErrorIs(t, scheduler.Validate(domain.Schedule{Recurrence: domain.RecurrenceDaily, Cron: "* * * * *"}), domain.ErrInvalidSchedule)
+}
+
+// fakeExecutor grava a transação como o outbox faria e conta as execuções
+type fakeExecutor struct {
+	db    *gorm.DB
+	calls []string
+	err   error
+}
+
+func (f *fakeExecutor) ExecuteSchedule(s domain.Schedule, txID string) (*domain.Transaction, error) {
+	f.calls = append(f.calls, txID)
+	if f.err != nil {
+		return nil, f.err
+	}
+	tx := domain.Transaction{ID: txID, UserID: s.UserID, Amount: s.Amount, Type: domain.TransferOutTransaction, Status: domain.StatusReceived}
+	if err := f.db.Create(&tx).Error; err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func setupScheduler(t *testing.T) (*gorm.DB, *repositories.GormRepository) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	require.NoError(t, db.AutoMigrate(&domain.Schedule{}, &domain.Lease{}, &domain.Transaction{}))
+	return db, repositories.NewGormRepository(db)
+}
+
+func createDaily(t *testing.T, repo *repositories.GormRepository, start time.Time) domain.Schedule {
+	s := domain.Schedule{
+		UserID:     1,
+		Type:       domain.ScheduleTransfer,
+		Amount:     domain.NewMoney(1_000000, "TRX"),
+		Recurrence: domain.RecurrenceDaily,
+		StartAt:    start,
+		NextRunAt:  &start,
+	}
+	require.NoError(t, repo.CreateSchedule(&s))
+	return s
+}
+
+func TestScheduler_OnlyLeaderFiresEachOccurrenceOnce(t *testing.T) {
+	db, repo := setupScheduler(t)
+	start := date("2026-03-10T09:00:00Z")
+	s := createDaily(t, repo, start)
+
+	exec := &fakeExecutor{db: db}
+	leader := scheduler.NewScheduler(repo, repo, exec, "replica-a", time.Minute)
+	follower := scheduler.NewScheduler(repo, repo, exec, "replica-b", time.Minute)
+
+	now := start.Add(30 * time.Second)
+	fired, err := leader.Tick(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+
+	// a outra réplica não tem a liderança e o próprio líder não repete a ocorrência
+	fired, err = follower.Tick(now)
+	require.NoError(t, err)
+	assert.Zero(t, fired)
+	fired, err = leader.Tick(now)
+	require.NoError(t, err)
+	assert.Zero(t, fired)
+
+	assert.Equal(t, []string{scheduler.OccurrenceID(s.ID, start)}, exec.calls)
+
+	stored, err := repo.GetSchedule(1, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored.Runs)
+	assert.Equal(t, scheduler.OccurrenceID(s.ID, start), stored.LastTransactionID)
+	assert.Equal(t, start.AddDate(0, 0, 1), stored.NextRunAt.UTC())
+
+	// liberada a liderança, a outra réplica assume a ocorrência seguinte
+	require.NoError(t, repo.ReleaseLease(scheduler.LeaseName, "replica-a"))
+	fired, err = follower.Tick(start.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Len(t, exec.calls, 2)
+}
+
+func TestScheduler_ReplayAfterCrashDoesNotExecuteAgain(t *testing.T) {
+	db, repo := setupScheduler(t)
+	start := date("2026-03-10T09:00:00Z")
+	s := createDaily(t, repo, start)
+
+	// a transação da ocorrência foi gravada, mas o processo caiu antes de avançar o agendamento
+	txID := scheduler.OccurrenceID(s.ID, start)
+	require.NoError(t, db.Create(&domain.Transaction{ID: txID, UserID: 1, Status: domain.StatusReceived}).Error)
+
+	exec := &fakeExecutor{db: db}
+	fired, err := scheduler.NewScheduler(repo, repo, exec, "replica-a", time.Minute).Tick(start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Empty(t, exec.calls)
+
+	stored, err := repo.GetSchedule(1, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, txID, stored.LastTransactionID)
+	assert.Equal(t, start.AddDate(0, 0, 1), stored.NextRunAt.UTC())
+}
+
+func TestScheduler_FailedOccurrenceIsRecordedAndSkipped(t *testing.T) {
+	db, repo := setupScheduler(t)
+	start := date("2026-03-10T09:00:00Z")
+	s := createDaily(t, repo, start)
+
+	exec := &fakeExecutor{db: db, err: domain.ErrInsufficientFunds}
+	// três dias parado: as ocorrências perdidas não são repostas
+	now := start.AddDate(0, 0, 3).Add(time.Hour)
+	fired, err := scheduler.NewScheduler(repo, repo, exec, "replica-a", time.Minute).Tick(now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+	assert.Len(t, exec.calls, 1)
+
+	stored, err := repo.GetSchedule(1, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ErrInsufficientFunds.Error(), stored.LastError)
+	assert.Empty(t, stored.LastTransactionID)
+	assert.Equal(t, start.AddDate(0, 0, 4), stored.NextRunAt.UTC())
+}
+
+func TestScheduler_TransientFailureRetriesSameOccurrence(t *testing.T) {
+	db, repo := setupScheduler(t)
+	start := date("2026-03-10T09:00:00Z")
+	s := createDaily(t, repo, start)
+
+	exec := &fakeExecutor{db: db, err: errors.New("db down")}
+	sched := scheduler.NewScheduler(repo, repo, exec, "replica-a", time.Minute)
+	now := start.Add(time.Minute)
+	fired, err := sched.Tick(now)
+	require.NoError(t, err)
+	assert.Zero(t, fired)
+
+	// a ocorrência não foi pulada: o agendamento segue vencido e sem erro registrado
+	stored, err := repo.GetSchedule(1, s.ID)
+	require.NoError(t, err)
+	assert.Zero(t, stored.Runs)
+	assert.Empty(t, stored.LastError)
+	assert.Equal(t, start, stored.NextRunAt.UTC())
+
+	exec.err = nil
+	fired, err = sched.Tick(now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fired)
+
+	txID := scheduler.OccurrenceID(s.ID, start)
+	assert.Equal(t, []string{txID, txID}, exec.calls)
+	stored, err = repo.GetSchedule(1, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, txID, stored.LastTransactionID)
+	assert.Equal(t, start.AddDate(0, 0, 1), stored.NextRunAt.UTC())
+}
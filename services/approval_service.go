@@ -0,0 +1,41 @@
+package services
+
+import (
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const DefaultApprovalListLimit = 100
+
+// ApprovalService é o lado do revisor no fluxo de aprovação de saques: quem pede não decide
+type ApprovalService struct {
+	Repo      d.TransactionRepository
+	Approvals d.ApprovalRepository
+}
+
+func NewApprovalService(r d.TransactionRepository, a d.ApprovalRepository) *ApprovalService {
+	return &ApprovalService{
+		Repo:      r,
+		Approvals: a,
+	}
+}
+
+// ListPending devolve os saques aguardando aprovação, os mais antigos primeiro
+func (s *ApprovalService) ListPending(limit int) ([]d.Transaction, error) {
+	if limit <= 0 || limit > DefaultApprovalListLimit {
+		limit = DefaultApprovalListLimit
+	}
+	return s.Repo.ListTransactionsByStatus(d.StatusAwaitingApproval, limit)
+}
+
+func (s *ApprovalService) Approve(txID string, approverID uint, reason string) (*d.Transaction, error) {
+	return s.Approvals.ApproveWithdrawal(txID, approverID, reason)
+}
+
+func (s *ApprovalService) Reject(txID string, approverID uint, reason string) (*d.Transaction, error) {
+	return s.Approvals.RejectWithdrawal(txID, approverID, reason)
+}
+
+// Decisions é a trilha de auditoria do saque
+func (s *ApprovalService) Decisions(txID string) ([]d.ApprovalDecision, error) {
+	return s.Approvals.ListApprovalDecisions(txID)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	p "github.com/gabrielksneiva/go-financial-transactions/producer"
+)
+
+const (
+	DefaultDeadLetterLimit = 50
+	MaxDeadLetterLimit     = 500
+)
+
+var ErrDeadLetterNotRedrivable = errors.New("dead letter does not contain a valid transaction")
+
+type DeadLetterService struct {
+	Queue    d.DeadLetterQueue
+	producer p.Producer
+}
+
+func NewDeadLetterService(q d.DeadLetterQueue, p p.Producer) *DeadLetterService {
+	return &DeadLetterService{
+		Queue:    q,
+		producer: p,
+	}
+}
+
+func (s *DeadLetterService) List(ctx context.Context, limit int) ([]d.DeadLetter, error) {
+	if limit <= 0 {
+		limit = DefaultDeadLetterLimit
+	}
+	if limit > MaxDeadLetterLimit {
+		limit = MaxDeadLetterLimit
+	}
+	return s.Queue.ListDeadLetters(ctx, limit)
+}
+
+// Redrive publica novamente no tópico principal a transação guardada no dead-letter.
+// Reprocessar é seguro porque os workers ignoram IDs de transação já processados.
+func (s *DeadLetterService) Redrive(ctx context.Context, partition int, offset int64) (*d.Transaction, error) {
+	letter, err := s.Queue.GetDeadLetter(ctx, partition, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var tx d.Transaction
+	if err := json.Unmarshal([]byte(letter.Value), &tx); err != nil || tx.ID == "" {
+		return nil, ErrDeadLetterNotRedrivable
+	}
+
+	if err := s.producer.SendTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"errors"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/wallet"
+)
+
+var ErrDepositAddressesDisabled = errors.New("on-chain deposit addresses are not configured")
+
+type DepositAddressService struct {
+	Repo    d.DepositRepository
+	Deriver d.AddressDeriver
+}
+
+func NewDepositAddressService(r d.DepositRepository, deriver d.AddressDeriver) *DepositAddressService {
+	return &DepositAddressService{
+		Repo:    r,
+		Deriver: deriver,
+	}
+}
+
+// GetOrAssign devolve o endereço de depósito do usuário, atribuindo-o no primeiro acesso.
+// O índice do endereço é o próprio ID do usuário, então cada usuário tem um endereço fixo.
+func (s *DepositAddressService) GetOrAssign(userID uint) (*d.DepositAddress, error) {
+	addr, err := s.Repo.GetDepositAddress(userID)
+	if err == nil {
+		return addr, nil
+	}
+	if !errors.Is(err, d.ErrDepositAddressNotFound) {
+		return nil, err
+	}
+
+	if s.Deriver == nil {
+		return nil, ErrDepositAddressesDisabled
+	}
+	index, err := wallet.IndexForUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	address, err := s.Deriver.DeriveAddress(index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.SaveDepositAddress(d.DepositAddress{UserID: userID, Address: address, Index: index}); err != nil {
+		return nil, err
+	}
+
+	// relê para devolver o endereço gravado por uma requisição concorrente, se houver
+	return s.Repo.GetDepositAddress(userID)
+}
@@ -6,7 +6,6 @@ import (
 
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	d "github.com/gabrielksneiva/go-financial-transactions/domain"
-	p "github.com/gabrielksneiva/go-financial-transactions/producer"
 
 	"github.com/google/uuid"
 )
@@ -14,26 +13,28 @@ import (
 type DepositService struct {
 	Repo        d.TransactionRepository
 	BalanceRepo d.BalanceRepository
-	producer    p.Producer
+	Outbox      d.OutboxRepository
 	RateLimiter d.RateLimiter
+	// Limits aplica o teto diário de depósito; sem ele não há teto
+	Limits *LimitService
 }
 
-func NewDepositService(r d.TransactionRepository, b d.BalanceRepository, p p.Producer, rate d.RateLimiter) *DepositService {
+func NewDepositService(r d.TransactionRepository, b d.BalanceRepository, o d.OutboxRepository, rate d.RateLimiter) *DepositService {
 	return &DepositService{
 		Repo:        r,
 		BalanceRepo: b,
-		producer:    p,
+		Outbox:      o,
 		RateLimiter: rate,
 	}
 }
 
-func (s *DepositService) Deposit(userID uint, amount float64) error {
-	if amount <= 0 {
-		return errors.New("amount must be greater than zero")
+func (s *DepositService) Deposit(userID uint, amount d.Money) (*d.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
 	}
 
 	if err := s.RateLimiter.CheckTransactionRateLimit(userID); err != nil {
-		return err
+		return nil, err
 	}
 
 	tx := domain.Transaction{
@@ -44,7 +45,20 @@ func (s *DepositService) Deposit(userID uint, amount float64) error {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		Type:      "deposit",
+		Status:    d.StatusReceived,
 	}
 
-	return s.producer.SendTransaction(tx)
+	// A transação fica visível no extrato como RECEIVED assim que a API aceita o pedido;
+	// o relay do outbox publica no Kafka depois, mesmo que o Kafka esteja fora agora
+	if s.Limits != nil {
+		if err := s.Limits.saveDeposit(tx); err != nil {
+			return nil, err
+		}
+		return &tx, nil
+	}
+	if err := s.Outbox.SaveTransactionWithOutbox(tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
 }
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+// LimitService aplica os tetos de valor por papel, nível de KYC e usuário. A checagem vale na
+// gravação da transação (SaveXWithinLimits), com o saldo travado
+type LimitService struct {
+	Policy d.LimitPolicy
+	Repo   d.LimitRepository
+}
+
+func NewLimitService(p d.LimitPolicy, r d.LimitRepository) *LimitService {
+	return &LimitService{
+		Policy: p,
+		Repo:   r,
+	}
+}
+
+// LimitStatus são os tetos em vigor e o volume já usado nos períodos correntes
+type LimitStatus struct {
+	Limits d.Limits
+	Usage  d.LimitUsage
+}
+
+func (s *LimitService) Status(userID uint, currency string) (*LimitStatus, error) {
+	if currency == "" {
+		currency = d.DefaultCurrency
+	}
+	limits, err := s.Policy.LimitsFor(userID, currency)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.Repo.GetLimitUsage(userID, limits.Currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &LimitStatus{Limits: limits, Usage: usage}, nil
+}
+
+// SetOverride troca os tetos próprios do usuário na moeda; campos nulos herdam a regra
+func (s *LimitService) SetOverride(o d.LimitOverride) error {
+	if o.UserID == 0 || o.Currency == "" {
+		return errors.New("user and currency are required")
+	}
+	for _, v := range []*int64{o.MaxWithdrawal, o.DailyWithdrawal, o.MonthlyWithdrawal, o.DailyDeposit} {
+		if v != nil && *v < 0 {
+			return errors.New("limits must not be negative")
+		}
+	}
+	return s.Repo.SaveLimitOverride(o)
+}
+
+func (s *LimitService) DeleteOverride(userID uint, currency string) error {
+	return s.Repo.DeleteLimitOverride(userID, currency)
+}
+
+func (s *LimitService) saveDeposit(tx d.Transaction) error {
+	limits, err := s.Policy.LimitsFor(tx.UserID, tx.Amount.Currency)
+	if err != nil {
+		return err
+	}
+	return s.Repo.SaveDepositWithinLimits(tx, limits)
+}
+
+func (s *LimitService) saveWithdrawal(tx d.Transaction) error {
+	limits, err := s.Policy.LimitsFor(tx.UserID, tx.Amount.Currency)
+	if err != nil {
+		return err
+	}
+	return s.Repo.SaveWithdrawalWithinLimits(tx, limits)
+}
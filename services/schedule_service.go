@@ -0,0 +1,162 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/scheduler"
+)
+
+// ScheduleService guarda os agendamentos dos usuários e executa cada ocorrência pelas mesmas
+// services da API, com as mesmas validações (saldo, destinos, aprovação, limite de requisições)
+type ScheduleService struct {
+	Repo      d.ScheduleRepository
+	Withdraws *WithdrawService
+	Transfers *TransferService
+}
+
+var _ scheduler.Executor = &ScheduleService{}
+
+func NewScheduleService(r d.ScheduleRepository, w *WithdrawService, t *TransferService) *ScheduleService {
+	return &ScheduleService{Repo: r, Withdraws: w, Transfers: t}
+}
+
+// ScheduleInput descreve um agendamento; sem StartAt a primeira ocorrência é imediata
+type ScheduleInput struct {
+	Type           string
+	Amount         d.Money
+	DestinationID  uint
+	RecipientID    uint
+	RecipientEmail string
+	Memo           string
+	Recurrence     string
+	Cron           string
+	StartAt        time.Time
+}
+
+func (s *ScheduleService) List(userID uint) ([]d.Schedule, error) {
+	return s.Repo.ListSchedules(userID)
+}
+
+func (s *ScheduleService) Get(userID, id uint) (*d.Schedule, error) {
+	return s.Repo.GetSchedule(userID, id)
+}
+
+func (s *ScheduleService) Create(userID uint, in ScheduleInput) (*d.Schedule, error) {
+	schedule := &d.Schedule{UserID: userID}
+	if err := s.apply(schedule, in); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.CreateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Update troca a operação e a recorrência; o histórico de execuções é mantido
+func (s *ScheduleService) Update(userID, id uint, in ScheduleInput) (*d.Schedule, error) {
+	schedule, err := s.Repo.GetSchedule(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(schedule, in); err != nil {
+		return nil, err
+	}
+	if err := s.Repo.UpdateSchedule(schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *ScheduleService) Delete(userID, id uint) error {
+	return s.Repo.DeleteSchedule(userID, id)
+}
+
+func (s *ScheduleService) apply(schedule *d.Schedule, in ScheduleInput) error {
+	if !in.Amount.IsPositive() {
+		return fmt.Errorf("%w: amount must be greater than zero", d.ErrInvalidSchedule)
+	}
+
+	memo := strings.TrimSpace(in.Memo)
+	if len([]rune(memo)) > d.MaxTransferMemoLength {
+		return d.ErrMemoTooLong
+	}
+
+	schedule.Type = in.Type
+	schedule.Amount = in.Amount
+	schedule.Memo = memo
+	schedule.DestinationID, schedule.RecipientID = 0, 0
+
+	switch in.Type {
+	case d.ScheduleWithdraw:
+		if _, err := d.LookupAsset(in.Amount.Currency); err != nil {
+			return err
+		}
+		// o destino precisa existir agora; a carência é conferida em cada ocorrência
+		if in.DestinationID != 0 {
+			if s.Withdraws == nil || s.Withdraws.Destinations == nil {
+				return d.ErrWithdrawalAddressNotFound
+			}
+			if _, err := s.Withdraws.Destinations.GetWithdrawalAddress(schedule.UserID, in.DestinationID); err != nil {
+				return err
+			}
+		}
+		schedule.DestinationID = in.DestinationID
+	case d.ScheduleTransfer:
+		recipient, err := s.Transfers.findRecipient(in.RecipientID, in.RecipientEmail)
+		if err != nil {
+			return err
+		}
+		if recipient.ID == schedule.UserID {
+			return d.ErrSelfTransfer
+		}
+		schedule.RecipientID = recipient.ID
+	default:
+		return fmt.Errorf("%w: type must be %s or %s", d.ErrInvalidSchedule, d.ScheduleWithdraw, d.ScheduleTransfer)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	startAt := in.StartAt.UTC().Truncate(time.Second)
+	if startAt.IsZero() {
+		startAt = now
+	}
+	// numa atualização o início original de um agendamento em andamento continua valendo
+	keepsStart := schedule.ID != 0 && startAt.Equal(schedule.StartAt.UTC())
+	if startAt.Before(now.Add(-time.Minute)) && !keepsStart {
+		return fmt.Errorf("%w: start_at is in the past", d.ErrInvalidSchedule)
+	}
+
+	schedule.Recurrence = in.Recurrence
+	schedule.Cron = strings.TrimSpace(in.Cron)
+	schedule.StartAt = startAt
+	if err := scheduler.Validate(*schedule); err != nil {
+		return err
+	}
+
+	next, err := scheduler.FirstRun(*schedule)
+	if keepsStart && startAt.Before(now) {
+		next, err = scheduler.NextRun(*schedule, now)
+	}
+	if err != nil {
+		return err
+	}
+	if next == nil {
+		return fmt.Errorf("%w: the recurrence never runs", d.ErrInvalidSchedule)
+	}
+	schedule.NextRunAt = next
+	return nil
+}
+
+// ExecuteSchedule cria a transação da ocorrência com o ID definido pelo scheduler
+func (s *ScheduleService) ExecuteSchedule(schedule d.Schedule, txID string) (*d.Transaction, error) {
+	switch schedule.Type {
+	case d.ScheduleWithdraw:
+		return s.Withdraws.ScheduledWithdraw(txID, schedule.UserID, schedule.Amount, schedule.DestinationID)
+	case d.ScheduleTransfer:
+		return s.Transfers.ScheduledTransfer(txID, schedule.UserID, schedule.RecipientID, schedule.Amount, schedule.Memo)
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q", d.ErrInvalidSchedule, schedule.Type)
+	}
+}
@@ -12,7 +12,8 @@ type StatementService struct {
 }
 
 type Statement struct {
-	Balance      float64
+	Balance      d.Money
+	Balances     []d.Money // saldo em cada moeda que o usuário já movimentou
 	Transactions []d.Transaction
 }
 
@@ -24,13 +25,36 @@ func NewStatementService(r d.TransactionRepository, b d.BalanceRepository) *Stat
 	}
 }
 
-// Retorna saldo
-func (s *StatementService) GetBalance(userID uint) (float64, error) {
-	balance, err := s.BalanceRepo.GetBalance(userID)
+// Retorna saldo na moeda
+func (s *StatementService) GetBalance(userID uint, currency string) (d.Money, error) {
+	balance, err := s.BalanceRepo.GetBalance(userID, currency)
 	if err != nil {
-		return 0.0, err
+		return d.Money{}, err
 	}
-	return balance.Amount, nil
+	return balance.Amount(), nil
+}
+
+// Retorna o saldo disponível e o valor reservado por saques ainda não enviados
+func (s *StatementService) GetBalanceWithHeld(userID uint, currency string) (available, held d.Money, err error) {
+	balance, err := s.BalanceRepo.GetBalance(userID, currency)
+	if err != nil {
+		return d.Money{}, d.Money{}, err
+	}
+	return balance.Amount(), balance.HeldAmount(), nil
+}
+
+// Retorna os saldos em todas as moedas
+func (s *StatementService) GetBalances(userID uint) ([]d.Money, error) {
+	balances, err := s.BalanceRepo.ListBalances(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make([]d.Money, 0, len(balances))
+	for _, b := range balances {
+		amounts = append(amounts, b.Amount())
+	}
+	return amounts, nil
 }
 
 // Retorna transações
@@ -40,7 +64,7 @@ func (s *StatementService) GetTransactions(userID uint) ([]d.Transaction, error)
 
 // Retorna extrato completo (saldo + transações)
 func (s *StatementService) GetStatement(userID uint) (*Statement, error) {
-	balance, err := s.GetBalance(userID)
+	balance, err := s.GetBalance(userID, d.DefaultCurrency)
 	if err != nil {
 		return nil, err
 	}
@@ -50,8 +74,14 @@ func (s *StatementService) GetStatement(userID uint) (*Statement, error) {
 		return nil, err
 	}
 
+	balances, err := s.GetBalances(userID)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Statement{
 		Balance:      balance,
+		Balances:     balances,
 		Transactions: transactions,
 	}, nil
 }
@@ -7,27 +7,123 @@ import (
 )
 
 type TransactionDisplay struct {
-	ID            string    `json:"id"`
-	Amount        float64   `json:"amount"`
-	Type          string    `json:"type"`
-	Status        string    `json:"status"`
-	WalletAddress string    `json:"wallet"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID            string `json:"id"`
+	Amount        string `json:"amount"`
+	Currency      string `json:"currency"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	WalletAddress string `json:"wallet"`
+	// pernas de transferência interna: o outro usuário, a perna ligada e a mensagem
+	CounterpartyID      uint      `json:"counterparty_id,omitempty"`
+	LinkedTransactionID string    `json:"linked_transaction_id,omitempty"`
+	Memo                string    `json:"memo,omitempty"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
 }
 
 func ToTransactionDisplay(txs []d.Transaction) []TransactionDisplay {
 	result := make([]TransactionDisplay, 0, len(txs))
 	for _, tx := range txs {
 		result = append(result, TransactionDisplay{
-			ID:        tx.ID,
-			Amount:    tx.Amount,
-			Type:      tx.Type,
-			CreatedAt: tx.CreatedAt,
-			UpdatedAt: tx.UpdatedAt,
-			//			Status:        tx.Status,
+			ID:            tx.ID,
+			Amount:        tx.Amount.String(),
+			Currency:      tx.Amount.Currency,
+			Type:          tx.Type,
+			CreatedAt:     tx.CreatedAt,
+			UpdatedAt:     tx.UpdatedAt,
+			Status:        string(tx.Status),
 			WalletAddress: tx.WalletAddress,
+
+			CounterpartyID:      tx.CounterpartyID,
+			LinkedTransactionID: tx.LinkedTransactionID,
+			Memo:                tx.Memo,
 		})
 	}
 	return result
 }
+
+// ScheduleDisplay mostra o agendamento com o valor em decimal; NextRunAt nulo indica que terminou
+type ScheduleDisplay struct {
+	ID                uint       `json:"id"`
+	Type              string     `json:"type"`
+	Amount            string     `json:"amount"`
+	Currency          string     `json:"currency"`
+	DestinationID     uint       `json:"destination_id,omitempty"`
+	RecipientID       uint       `json:"recipient_id,omitempty"`
+	Memo              string     `json:"memo,omitempty"`
+	Recurrence        string     `json:"recurrence"`
+	Cron              string     `json:"cron,omitempty"`
+	StartAt           time.Time  `json:"start_at"`
+	NextRunAt         *time.Time `json:"next_run_at"`
+	LastRunAt         *time.Time `json:"last_run_at,omitempty"`
+	LastTransactionID string     `json:"last_transaction_id,omitempty"`
+	LastError         string     `json:"last_error,omitempty"`
+	Runs              int        `json:"runs"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+func ToScheduleDisplay(s d.Schedule) ScheduleDisplay {
+	return ScheduleDisplay{
+		ID:                s.ID,
+		Type:              s.Type,
+		Amount:            s.Amount.String(),
+		Currency:          s.Amount.Currency,
+		DestinationID:     s.DestinationID,
+		RecipientID:       s.RecipientID,
+		Memo:              s.Memo,
+		Recurrence:        s.Recurrence,
+		Cron:              s.Cron,
+		StartAt:           s.StartAt,
+		NextRunAt:         s.NextRunAt,
+		LastRunAt:         s.LastRunAt,
+		LastTransactionID: s.LastTransactionID,
+		LastError:         s.LastError,
+		Runs:              s.Runs,
+		CreatedAt:         s.CreatedAt,
+		UpdatedAt:         s.UpdatedAt,
+	}
+}
+
+// LimitWindowDisplay mostra um teto de período; Limit e Remaining nulos indicam que não há teto
+type LimitWindowDisplay struct {
+	Limit     *string `json:"limit"`
+	Used      string  `json:"used"`
+	Remaining *string `json:"remaining"`
+}
+
+// LimitDisplay mostra os tetos do usuário na moeda e quanto ainda resta em cada período
+type LimitDisplay struct {
+	Currency          string             `json:"currency"`
+	MaxWithdrawal     *string            `json:"max_withdrawal"`
+	DailyWithdrawal   LimitWindowDisplay `json:"daily_withdrawal"`
+	MonthlyWithdrawal LimitWindowDisplay `json:"monthly_withdrawal"`
+	DailyDeposit      LimitWindowDisplay `json:"daily_deposit"`
+}
+
+func ToLimitDisplay(status LimitStatus) LimitDisplay {
+	currency := status.Limits.Currency
+	format := func(units int64) *string {
+		if units == 0 {
+			return nil
+		}
+		s := d.NewMoney(units, currency).String()
+		return &s
+	}
+	window := func(limit, used int64) LimitWindowDisplay {
+		w := LimitWindowDisplay{Limit: format(limit), Used: d.NewMoney(used, currency).String()}
+		if limit > 0 {
+			remaining := d.NewMoney(max(limit-used, 0), currency).String()
+			w.Remaining = &remaining
+		}
+		return w
+	}
+
+	return LimitDisplay{
+		Currency:          currency,
+		MaxWithdrawal:     format(status.Limits.MaxWithdrawal),
+		DailyWithdrawal:   window(status.Limits.DailyWithdrawal, status.Usage.DailyWithdrawal),
+		MonthlyWithdrawal: window(status.Limits.MonthlyWithdrawal, status.Usage.MonthlyWithdrawal),
+		DailyDeposit:      window(status.Limits.DailyDeposit, status.Usage.DailyDeposit),
+	}
+}
@@ -1,36 +1,41 @@
 package services_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"golang.org/x/crypto/bcrypt"
 
+	"github.com/gabrielksneiva/go-financial-transactions/client"
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gabrielksneiva/go-financial-transactions/mocks"
 	"github.com/gabrielksneiva/go-financial-transactions/services"
 )
 
 // Setup helpers
-func setupDepositService() (*mocks.Producer, *mocks.RateLimiter, *services.DepositService) {
-	producer := new(mocks.Producer)
+func setupDepositService() (*mocks.OutboxRepository, *mocks.RateLimiter, *services.DepositService) {
+	outbox := new(mocks.OutboxRepository)
 	rateLimiter := new(mocks.RateLimiter)
 
-	service := services.NewDepositService(nil, nil, producer, rateLimiter)
-	return producer, rateLimiter, service
+	service := services.NewDepositService(nil, nil, outbox, rateLimiter)
+	return outbox, rateLimiter, service
 }
 
-func setupWithdrawService() (*mocks.TransactionRepository, *mocks.BalanceRepository, *mocks.Producer, *mocks.RateLimiter, *services.WithdrawService) {
+func setupWithdrawService() (*mocks.TransactionRepository, *mocks.BalanceRepository, *mocks.OutboxRepository, *mocks.RateLimiter, *services.WithdrawService) {
 	txRepo := new(mocks.TransactionRepository)
 	balanceRepo := new(mocks.BalanceRepository)
-	producer := new(mocks.Producer)
+	outbox := new(mocks.OutboxRepository)
 	rateLimiter := new(mocks.RateLimiter)
 
-	service := services.NewWithdrawService(txRepo, balanceRepo, producer, rateLimiter)
-	return txRepo, balanceRepo, producer, rateLimiter, service
+	service := services.NewWithdrawService(txRepo, balanceRepo, outbox, rateLimiter)
+	return txRepo, balanceRepo, outbox, rateLimiter, service
 }
 
 func setupStatementService() (*mocks.TransactionRepository, *mocks.BalanceRepository, *services.StatementService) {
@@ -44,82 +49,82 @@ func setupStatementService() (*mocks.TransactionRepository, *mocks.BalanceReposi
 
 func TestDepositService(t *testing.T) {
 	t.Run("Deposit_Success", func(t *testing.T) {
-		producer, rateLimiter, service := setupDepositService()
+		outbox, rateLimiter, service := setupDepositService()
 		userID := uint(123)
-		amount := 100.0
+		amount := domain.NewMoney(100_000000, "TRX")
 
 		// Configuração do mock para RateLimiter
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
 
-		// Configuração do mock para Producer
-		producer.On("SendTransaction", mock.AnythingOfType("domain.Transaction")).Return(nil)
+		// Configuração do mock para Outbox
+		outbox.On("SaveTransactionWithOutbox", mock.AnythingOfType("domain.Transaction")).Return(nil)
 
-		err := service.Deposit(userID, amount)
+		_, err := service.Deposit(userID, amount)
 
 		// Verificações
 		assert.NoError(t, err)
-		producer.AssertCalled(t, "SendTransaction", mock.AnythingOfType("domain.Transaction"))
+		outbox.AssertCalled(t, "SaveTransactionWithOutbox", mock.AnythingOfType("domain.Transaction"))
 		rateLimiter.AssertCalled(t, "CheckTransactionRateLimit", userID)
 	})
 
 	t.Run("Deposit_RateLimiterError", func(t *testing.T) {
-		producer, rateLimiter, service := setupDepositService()
+		outbox, rateLimiter, service := setupDepositService()
 		userID := uint(1)
-		amount := 100.0
+		amount := domain.NewMoney(100_000000, "TRX")
 
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(errors.New("rate limit exceeded"))
 
-		err := service.Deposit(userID, amount)
+		_, err := service.Deposit(userID, amount)
 		assert.EqualError(t, err, "rate limit exceeded")
-		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+		outbox.AssertNotCalled(t, "SaveTransactionWithOutbox", mock.Anything)
 	})
 
 	t.Run("Deposit_RateLimitExceeded", func(t *testing.T) {
-		producer, rateLimiter, service := setupDepositService()
+		outbox, rateLimiter, service := setupDepositService()
 		userID := uint(123)
-		amount := 100.0
+		amount := domain.NewMoney(100_000000, "TRX")
 
 		rateLimiter.On("CheckTransactionRateLimit", userID).
 			Return(errors.New("rate limit exceeded"))
 
-		err := service.Deposit(userID, amount)
+		_, err := service.Deposit(userID, amount)
 		assert.EqualError(t, err, "rate limit exceeded")
-		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+		outbox.AssertNotCalled(t, "SaveTransactionWithOutbox", mock.Anything)
 	})
 
-	t.Run("Deposit_ProducerError", func(t *testing.T) {
-		producer, rateLimiter, service := setupDepositService()
+	t.Run("Deposit_OutboxError", func(t *testing.T) {
+		outbox, rateLimiter, service := setupDepositService()
 		userID := uint(2)
-		amount := 150.0
+		amount := domain.NewMoney(150_000000, "TRX")
 
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
-		producer.On("SendTransaction", mock.AnythingOfType("domain.Transaction")).Return(errors.New("kafka down"))
+		outbox.On("SaveTransactionWithOutbox", mock.AnythingOfType("domain.Transaction")).Return(errors.New("db down"))
 
-		err := service.Deposit(userID, amount)
-		assert.EqualError(t, err, "kafka down")
-		producer.AssertCalled(t, "SendTransaction", mock.Anything)
+		_, err := service.Deposit(userID, amount)
+		assert.EqualError(t, err, "db down")
+		outbox.AssertCalled(t, "SaveTransactionWithOutbox", mock.Anything)
 	})
 
-	t.Run("Deposit_ProducerFails", func(t *testing.T) {
-		producer, rateLimiter, service := setupDepositService()
+	t.Run("Deposit_OutboxFails", func(t *testing.T) {
+		outbox, rateLimiter, service := setupDepositService()
 		userID := uint(123)
-		amount := 100.0
+		amount := domain.NewMoney(100_000000, "TRX")
 
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
-		producer.On("SendTransaction", mock.AnythingOfType("domain.Transaction")).Return(errors.New("kafka down"))
+		outbox.On("SaveTransactionWithOutbox", mock.AnythingOfType("domain.Transaction")).Return(errors.New("db down"))
 
-		err := service.Deposit(userID, amount)
-		assert.EqualError(t, err, "kafka down")
+		_, err := service.Deposit(userID, amount)
+		assert.EqualError(t, err, "db down")
 	})
 
 	t.Run("Deposit_InvalidAmount", func(t *testing.T) {
-		producer, rateLimiter, service := setupDepositService()
+		outbox, rateLimiter, service := setupDepositService()
 		userID := uint(10)
 
-		err := service.Deposit(userID, 0)
+		_, err := service.Deposit(userID, domain.Money{})
 		assert.EqualError(t, err, "amount must be greater than zero")
 
-		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+		outbox.AssertNotCalled(t, "SaveTransactionWithOutbox", mock.Anything)
 		rateLimiter.AssertNotCalled(t, "CheckTransactionRateLimit", userID)
 	})
 
@@ -128,84 +133,92 @@ func TestDepositService(t *testing.T) {
 // ----------------- Withdraw Tests -----------------
 func TestWithdrawService(t *testing.T) {
 	userID := uint(123)
-	amount := 50.0
+	amount := domain.NewMoney(50_000000, "TRX")
 
 	t.Run("Withdraw_Success", func(t *testing.T) {
-		_, balanceRepo, producer, rateLimiter, service := setupWithdrawService()
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
 
-		balanceRepo.On("GetBalance", userID).
-			Return(&domain.Balance{UserID: userID, Amount: 100.0}, nil)
+		balanceRepo.On("GetBalance", userID, "TRX").
+			Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 100_000000}, nil)
 
-		producer.On("SendTransaction", mock.AnythingOfType("domain.Transaction")).
+		outbox.On("SaveWithdrawalWithHold", mock.AnythingOfType("domain.Transaction")).
 			Return(nil)
 
 		// Configuração do mock para RateLimiter
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
 
-		err := service.Withdraw(userID, amount)
+		_, err := service.Withdraw(userID, amount)
 		assert.NoError(t, err)
 
 		balanceRepo.AssertExpectations(t)
-		producer.AssertExpectations(t)
+		outbox.AssertExpectations(t)
 		rateLimiter.AssertCalled(t, "CheckTransactionRateLimit", userID)
 	})
 
 	t.Run("Withdraw_InsufficientFunds", func(t *testing.T) {
-		_, balanceRepo, producer, rateLimiter, service := setupWithdrawService()
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
 
-		balanceRepo.On("GetBalance", userID).Return(&domain.Balance{
-			UserID: userID,
-			Amount: 20.0,
-		}, nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 20_000000}, nil)
 
 		// Configuração do mock para RateLimiter
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
 
-		err := service.Withdraw(userID, amount)
+		_, err := service.Withdraw(userID, amount)
 		assert.EqualError(t, err, "insufficient funds")
 
-		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+		outbox.AssertNotCalled(t, "SaveWithdrawalWithHold", mock.Anything)
 		rateLimiter.AssertCalled(t, "CheckTransactionRateLimit", userID)
 	})
 
 	t.Run("Withdraw_BalanceError", func(t *testing.T) {
-		_, balanceRepo, producer, rateLimiter, service := setupWithdrawService()
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
 
-		balanceRepo.On("GetBalance", userID).Return(nil, errors.New("db error"))
+		balanceRepo.On("GetBalance", userID, "TRX").Return(nil, errors.New("db error"))
 
 		// Configuração do mock para RateLimiter
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
 
-		err := service.Withdraw(userID, amount)
+		_, err := service.Withdraw(userID, amount)
 		assert.EqualError(t, err, "db error")
 
-		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+		outbox.AssertNotCalled(t, "SaveWithdrawalWithHold", mock.Anything)
 		rateLimiter.AssertCalled(t, "CheckTransactionRateLimit", userID)
 	})
 
+	t.Run("Withdraw_UnsupportedAsset", func(t *testing.T) {
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
+
+		_, err := service.Withdraw(userID, domain.NewMoney(10_00, "BRL"))
+		assert.ErrorIs(t, err, domain.ErrAssetNotSupported)
+
+		rateLimiter.AssertNotCalled(t, "CheckTransactionRateLimit", userID)
+		balanceRepo.AssertNotCalled(t, "GetBalance", mock.Anything, mock.Anything)
+		outbox.AssertNotCalled(t, "SaveWithdrawalWithHold", mock.Anything)
+	})
+
 	t.Run("Withdraw_RateLimiterError", func(t *testing.T) {
-		_, balanceRepo, producer, rateLimiter, service := setupWithdrawService()
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
 
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(errors.New("rate limit exceeded"))
 
-		err := service.Withdraw(userID, amount)
+		_, err := service.Withdraw(userID, amount)
 		assert.EqualError(t, err, "rate limit exceeded")
 
-		balanceRepo.AssertNotCalled(t, "GetBalance", mock.Anything)
-		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+		balanceRepo.AssertNotCalled(t, "GetBalance", mock.Anything, mock.Anything)
+		outbox.AssertNotCalled(t, "SaveWithdrawalWithHold", mock.Anything)
 	})
 
-	t.Run("Withdraw_ProducerError", func(t *testing.T) {
-		_, balanceRepo, producer, rateLimiter, service := setupWithdrawService()
+	t.Run("Withdraw_OutboxError", func(t *testing.T) {
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
 
 		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
-		balanceRepo.On("GetBalance", userID).Return(&domain.Balance{UserID: userID, Amount: 100.0}, nil)
-		producer.On("SendTransaction", mock.Anything).Return(errors.New("kafka fail"))
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 100_000000}, nil)
+		outbox.On("SaveWithdrawalWithHold", mock.Anything).Return(errors.New("db fail"))
 
-		err := service.Withdraw(userID, amount)
-		assert.EqualError(t, err, "kafka fail")
+		_, err := service.Withdraw(userID, amount)
+		assert.EqualError(t, err, "db fail")
 
-		producer.AssertCalled(t, "SendTransaction", mock.Anything)
+		outbox.AssertCalled(t, "SaveWithdrawalWithHold", mock.Anything)
 	})
 
 }
@@ -217,20 +230,20 @@ func TestStatementService(t *testing.T) {
 		_, balanceRepo, service := setupStatementService()
 		userID := uint(456)
 
-		balanceRepo.On("GetBalance", userID).Return(&domain.Balance{UserID: userID, Amount: 200.0}, nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 200_000000}, nil)
 
-		amount, err := service.GetBalance(userID)
+		amount, err := service.GetBalance(userID, "TRX")
 
 		assert.NoError(t, err)
-		assert.Equal(t, 200.0, amount)
+		assert.Equal(t, domain.NewMoney(200_000000, "TRX"), amount)
 	})
 
 	t.Run("GetTransactions_Success", func(t *testing.T) {
 		txRepo, _, service := setupStatementService()
 		userID := uint(789)
 		mockTxs := []domain.Transaction{
-			{ID: "tx1", UserID: userID, Amount: 100.0},
-			{ID: "tx2", UserID: userID, Amount: -50.0},
+			{ID: "tx1", UserID: userID, Amount: domain.NewMoney(100_000000, "TRX")},
+			{ID: "tx2", UserID: userID, Amount: domain.NewMoney(-50_000000, "TRX")},
 		}
 
 		txRepo.On("GetByUser", userID).Return(mockTxs, nil)
@@ -245,17 +258,19 @@ func TestStatementService(t *testing.T) {
 		txRepo, balanceRepo, service := setupStatementService()
 		userID := uint(999)
 		mockTxs := []domain.Transaction{
-			{ID: "tx1", UserID: userID, Amount: 150.0},
+			{ID: "tx1", UserID: userID, Amount: domain.NewMoney(150_000000, "TRX")},
 		}
-		mockBalance := &domain.Balance{UserID: userID, Amount: 150.0}
+		mockBalance := &domain.Balance{UserID: userID, Currency: "TRX", Units: 150_000000}
 
-		balanceRepo.On("GetBalance", userID).Return(mockBalance, nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(mockBalance, nil)
+		balanceRepo.On("ListBalances", userID).Return([]domain.Balance{*mockBalance, {UserID: userID, Currency: "USDT", Units: 30_000000}}, nil)
 		txRepo.On("GetByUser", userID).Return(mockTxs, nil)
 
 		statement, err := service.GetStatement(userID)
 
 		assert.NoError(t, err)
-		assert.Equal(t, 150.0, statement.Balance)
+		assert.Equal(t, domain.NewMoney(150_000000, "TRX"), statement.Balance)
+		assert.Equal(t, []domain.Money{domain.NewMoney(150_000000, "TRX"), domain.NewMoney(30_000000, "USDT")}, statement.Balances)
 		assert.Equal(t, mockTxs, statement.Transactions)
 	})
 
@@ -263,9 +278,9 @@ func TestStatementService(t *testing.T) {
 		_, balanceRepo, service := setupStatementService()
 		userID := uint(555)
 
-		balanceRepo.On("GetBalance", userID).Return(nil, errors.New("db error"))
+		balanceRepo.On("GetBalance", userID, "TRX").Return(nil, errors.New("db error"))
 
-		_, err := service.GetBalance(userID)
+		_, err := service.GetBalance(userID, "TRX")
 		assert.EqualError(t, err, "db error")
 	})
 
@@ -283,7 +298,7 @@ func TestStatementService(t *testing.T) {
 		_, balanceRepo, service := setupStatementService()
 		userID := uint(777)
 
-		balanceRepo.On("GetBalance", userID).Return(nil, errors.New("no balance"))
+		balanceRepo.On("GetBalance", userID, "TRX").Return(nil, errors.New("no balance"))
 
 		_, err := service.GetStatement(userID)
 		assert.EqualError(t, err, "no balance")
@@ -293,7 +308,7 @@ func TestStatementService(t *testing.T) {
 		txRepo, balanceRepo, service := setupStatementService()
 		userID := uint(888)
 
-		balanceRepo.On("GetBalance", userID).Return(&domain.Balance{UserID: userID, Amount: 100}, nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 100_000000}, nil)
 		txRepo.On("GetByUser", userID).Return(nil, errors.New("tx error"))
 
 		_, err := service.GetStatement(userID)
@@ -304,7 +319,7 @@ func TestStatementService(t *testing.T) {
 		txRepo, balanceRepo, service := setupStatementService()
 		userID := uint(1)
 
-		balanceRepo.On("GetBalance", userID).Return(&domain.Balance{UserID: userID, Amount: 0}, nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 0}, nil)
 		txRepo.On("GetByUser", userID).Return(nil, errors.New("tx fail"))
 
 		_, err := service.GetStatement(userID)
@@ -349,6 +364,31 @@ func TestUserService_CreateUser(t *testing.T) {
 		err := service.CreateUser(user)
 		assert.EqualError(t, err, "db error")
 	})
+
+	t.Run("InvalidWallet", func(t *testing.T) {
+		repo := new(mocks.UserRepository)
+		service := services.NewUserService(repo)
+
+		user := &domain.User{Email: "test@example.com", Password: "password123", WalletAddress: "TNotAnAddress"}
+
+		err := service.CreateUser(user)
+		assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("HexWalletIsStoredAsBase58", func(t *testing.T) {
+		repo := new(mocks.UserRepository)
+		service := services.NewUserService(repo)
+
+		repo.On("Create", mock.MatchedBy(func(u domain.User) bool {
+			return u.WalletAddress == "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+		})).Return(nil)
+
+		user := &domain.User{Email: "test@example.com", Password: "password123", WalletAddress: "41c8599111f29c1e1e061265b4af93ea1f274ad78a"}
+
+		assert.NoError(t, service.CreateUser(user))
+		repo.AssertExpectations(t)
+	})
 }
 
 func TestUserService_Authenticate(t *testing.T) {
@@ -377,3 +417,523 @@ func TestUserService_Authenticate(t *testing.T) {
 		assert.EqualError(t, err, "senha inválida")
 	})
 }
+
+// ----------------- Dead-letter Tests -----------------
+
+func TestDeadLetterService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("List_ClampsLimit", func(t *testing.T) {
+		queue := new(mocks.DeadLetterQueue)
+		service := services.NewDeadLetterService(queue, new(mocks.Producer))
+
+		queue.On("ListDeadLetters", ctx, services.DefaultDeadLetterLimit).Return([]domain.DeadLetter{{Offset: 1}}, nil)
+		queue.On("ListDeadLetters", ctx, services.MaxDeadLetterLimit).Return([]domain.DeadLetter{}, nil)
+
+		letters, err := service.List(ctx, 0)
+		assert.NoError(t, err)
+		assert.Len(t, letters, 1)
+
+		_, err = service.List(ctx, 10_000)
+		assert.NoError(t, err)
+		queue.AssertExpectations(t)
+	})
+
+	t.Run("Redrive_Success", func(t *testing.T) {
+		queue := new(mocks.DeadLetterQueue)
+		producer := new(mocks.Producer)
+		service := services.NewDeadLetterService(queue, producer)
+
+		queue.On("GetDeadLetter", ctx, 0, int64(3)).Return(&domain.DeadLetter{
+			Value: `{"id":"tx-1","UserID":7,"amount":{"units":1000000,"currency":"TRX"},"type":"deposit"}`,
+		}, nil)
+		producer.On("SendTransaction", mock.MatchedBy(func(tx domain.Transaction) bool {
+			return tx.ID == "tx-1" && tx.UserID == 7 && tx.Amount == domain.NewMoney(1_000000, "TRX")
+		})).Return(nil)
+
+		tx, err := service.Redrive(ctx, 0, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "tx-1", tx.ID)
+		producer.AssertExpectations(t)
+	})
+
+	t.Run("Redrive_InvalidPayload", func(t *testing.T) {
+		queue := new(mocks.DeadLetterQueue)
+		producer := new(mocks.Producer)
+		service := services.NewDeadLetterService(queue, producer)
+
+		queue.On("GetDeadLetter", ctx, 0, int64(4)).Return(&domain.DeadLetter{Value: "invalid-json"}, nil)
+
+		_, err := service.Redrive(ctx, 0, 4)
+		assert.ErrorIs(t, err, services.ErrDeadLetterNotRedrivable)
+		producer.AssertNotCalled(t, "SendTransaction", mock.Anything)
+	})
+
+	t.Run("Redrive_NotFound", func(t *testing.T) {
+		queue := new(mocks.DeadLetterQueue)
+		service := services.NewDeadLetterService(queue, new(mocks.Producer))
+
+		queue.On("GetDeadLetter", ctx, 1, int64(9)).Return(nil, domain.ErrDeadLetterNotFound)
+
+		_, err := service.Redrive(ctx, 1, 9)
+		assert.ErrorIs(t, err, domain.ErrDeadLetterNotFound)
+	})
+}
+
+// ----------------- Deposit address Tests -----------------
+
+func TestDepositAddressService(t *testing.T) {
+	t.Run("ReturnsExistingAddress", func(t *testing.T) {
+		repo := new(mocks.DepositRepository)
+		deriver := new(mocks.AddressDeriver)
+		service := services.NewDepositAddressService(repo, deriver)
+
+		repo.On("GetDepositAddress", uint(7)).Return(&domain.DepositAddress{UserID: 7, Address: "TAddr7", Index: 7}, nil)
+
+		addr, err := service.GetOrAssign(7)
+		assert.NoError(t, err)
+		assert.Equal(t, "TAddr7", addr.Address)
+		deriver.AssertNotCalled(t, "DeriveAddress", mock.Anything)
+	})
+
+	t.Run("AssignsOnFirstAccess", func(t *testing.T) {
+		repo := new(mocks.DepositRepository)
+		deriver := new(mocks.AddressDeriver)
+		service := services.NewDepositAddressService(repo, deriver)
+
+		repo.On("GetDepositAddress", uint(8)).Return(nil, domain.ErrDepositAddressNotFound).Once()
+		deriver.On("DeriveAddress", uint32(8)).Return("TAddr8", nil)
+		repo.On("SaveDepositAddress", domain.DepositAddress{UserID: 8, Address: "TAddr8", Index: 8}).Return(nil)
+		repo.On("GetDepositAddress", uint(8)).Return(&domain.DepositAddress{UserID: 8, Address: "TAddr8", Index: 8}, nil).Once()
+
+		addr, err := service.GetOrAssign(8)
+		assert.NoError(t, err)
+		assert.Equal(t, "TAddr8", addr.Address)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("DisabledWithoutDeriver", func(t *testing.T) {
+		repo := new(mocks.DepositRepository)
+		service := services.NewDepositAddressService(repo, nil)
+
+		repo.On("GetDepositAddress", uint(9)).Return(nil, domain.ErrDepositAddressNotFound)
+
+		_, err := service.GetOrAssign(9)
+		assert.ErrorIs(t, err, services.ErrDepositAddressesDisabled)
+		repo.AssertNotCalled(t, "SaveDepositAddress", mock.Anything)
+	})
+}
+
+// ----------------- Withdrawal Address Tests -----------------
+
+const tronDestination = "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH"
+
+func setupWithdrawalAddressService(t *testing.T) (*mocks.WithdrawalAddressRepository, *mocks.UserRepository, *services.WithdrawalAddressService) {
+	repo := new(mocks.WithdrawalAddressRepository)
+	users := new(mocks.UserRepository)
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	assert.NoError(t, err)
+	users.On("GetByID", uint(7)).Return(&domain.User{ID: 7, Password: string(hashed)}, nil)
+
+	validators := map[string]domain.AddressValidator{domain.ChainTron: client.TronAddressValidator{}}
+	return repo, users, services.NewWithdrawalAddressService(repo, users, validators, time.Hour)
+}
+
+func TestWithdrawalAddressService_Add(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo, _, service := setupWithdrawalAddressService(t)
+		repo.On("SaveWithdrawalAddress", mock.MatchedBy(func(a *domain.WithdrawalAddress) bool {
+			return a.UserID == 7 && a.Chain == domain.ChainTron && a.Currency == "TRX" && a.Label == "exchange"
+		})).Return(nil)
+
+		addr, err := service.Add(7, "secret", " exchange ", "trx", tronDestination)
+		assert.NoError(t, err)
+		assert.False(t, addr.IsActive(time.Now()))
+		assert.True(t, addr.IsActive(time.Now().Add(time.Hour)))
+	})
+
+	t.Run("HexFormIsStoredAsBase58", func(t *testing.T) {
+		repo, _, service := setupWithdrawalAddressService(t)
+		sameAddress := mock.MatchedBy(func(a *domain.WithdrawalAddress) bool { return a.Address == tronDestination })
+		repo.On("SaveWithdrawalAddress", sameAddress).Return(nil).Once()
+		repo.On("SaveWithdrawalAddress", sameAddress).Return(domain.ErrWithdrawalAddressExists).Once()
+
+		addr, err := service.Add(7, "secret", "hex", "TRX", "0x41c8599111f29c1e1e061265b4af93ea1f274ad78a")
+		assert.NoError(t, err)
+		assert.Equal(t, tronDestination, addr.Address)
+
+		_, err = service.Add(7, "secret", "base58", "TRX", tronDestination)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalAddressExists)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		repo, _, service := setupWithdrawalAddressService(t)
+
+		_, err := service.Add(7, "wrong", "exchange", "TRX", tronDestination)
+		assert.ErrorIs(t, err, services.ErrReauthenticationFailed)
+
+		_, err = service.Add(7, "", "exchange", "TRX", tronDestination)
+		assert.ErrorIs(t, err, services.ErrReauthenticationFailed)
+		repo.AssertNotCalled(t, "SaveWithdrawalAddress", mock.Anything)
+	})
+
+	t.Run("InvalidAddressForAsset", func(t *testing.T) {
+		_, _, service := setupWithdrawalAddressService(t)
+
+		_, err := service.Add(7, "secret", "metamask", "TRX", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+		assert.ErrorIs(t, err, domain.ErrInvalidAddress)
+	})
+
+	t.Run("ChainWithoutValidator", func(t *testing.T) {
+		_, _, service := setupWithdrawalAddressService(t)
+		domain.RegisterAsset(domain.Asset{Symbol: "WETH", Decimals: 9, Chain: domain.ChainEthereum, Contract: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"})
+
+		_, err := service.Add(7, "secret", "weth", "WETH", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+		assert.ErrorIs(t, err, domain.ErrChainNotSupported)
+	})
+}
+
+func TestWithdrawalAddressService_Remove(t *testing.T) {
+	repo, _, service := setupWithdrawalAddressService(t)
+	repo.On("DeleteWithdrawalAddress", uint(7), uint(3)).Return(nil)
+
+	assert.ErrorIs(t, service.Remove(7, "wrong", 3), services.ErrReauthenticationFailed)
+	repo.AssertNotCalled(t, "DeleteWithdrawalAddress", uint(7), uint(3))
+
+	assert.NoError(t, service.Remove(7, "secret", 3))
+	repo.AssertExpectations(t)
+}
+
+func TestWithdrawService_WithdrawTo(t *testing.T) {
+	userID := uint(7)
+	amount := domain.NewMoney(10_000000, "TRX")
+
+	setup := func() (*mocks.WithdrawalAddressRepository, *mocks.BalanceRepository, *mocks.OutboxRepository, *services.WithdrawService) {
+		_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
+		destinations := new(mocks.WithdrawalAddressRepository)
+		service.Destinations = destinations
+		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 100_000000}, nil)
+		return destinations, balanceRepo, outbox, service
+	}
+
+	t.Run("ActiveDestination", func(t *testing.T) {
+		destinations, _, outbox, service := setup()
+		destinations.On("GetWithdrawalAddress", userID, uint(1)).Return(&domain.WithdrawalAddress{
+			ID: 1, UserID: userID, Chain: domain.ChainTron, Address: tronDestination, ActiveAt: time.Now().Add(-time.Minute),
+		}, nil)
+		outbox.On("SaveWithdrawalWithHold", mock.MatchedBy(func(tx domain.Transaction) bool {
+			return tx.WalletAddress == tronDestination
+		})).Return(nil)
+
+		tx, err := service.WithdrawTo(userID, amount, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, tronDestination, tx.WalletAddress)
+	})
+
+	t.Run("CoolingDown", func(t *testing.T) {
+		destinations, _, outbox, service := setup()
+		destinations.On("GetWithdrawalAddress", userID, uint(2)).Return(&domain.WithdrawalAddress{
+			ID: 2, UserID: userID, Chain: domain.ChainTron, Address: tronDestination, ActiveAt: time.Now().Add(time.Hour),
+		}, nil)
+
+		_, err := service.WithdrawTo(userID, amount, 2)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalAddressCoolingDown)
+		outbox.AssertNotCalled(t, "SaveWithdrawalWithHold", mock.Anything)
+	})
+
+	t.Run("OtherChain", func(t *testing.T) {
+		destinations, _, _, service := setup()
+		destinations.On("GetWithdrawalAddress", userID, uint(3)).Return(&domain.WithdrawalAddress{
+			ID: 3, UserID: userID, Chain: domain.ChainEthereum, Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		}, nil)
+
+		_, err := service.WithdrawTo(userID, amount, 3)
+		assert.ErrorIs(t, err, domain.ErrDestinationChainMismatch)
+	})
+
+	t.Run("UnknownDestination", func(t *testing.T) {
+		destinations, _, _, service := setup()
+		destinations.On("GetWithdrawalAddress", userID, uint(4)).Return(nil, domain.ErrWithdrawalAddressNotFound)
+
+		_, err := service.WithdrawTo(userID, amount, 4)
+		assert.ErrorIs(t, err, domain.ErrWithdrawalAddressNotFound)
+	})
+}
+
+func TestWithdrawService_ApprovalThreshold(t *testing.T) {
+	userID := uint(8)
+	_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
+	approvals := new(mocks.ApprovalRepository)
+	service.Approvals = approvals
+	service.ApprovalThresholds = map[string]domain.Money{"TRX": domain.NewMoney(50_000000, "TRX")}
+
+	rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
+	balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 100_000000}, nil)
+	outbox.On("SaveWithdrawalWithHold", mock.AnythingOfType("domain.Transaction")).Return(nil)
+	approvals.On("HoldForApproval", mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.Status == domain.StatusAwaitingApproval
+	})).Return(nil)
+
+	small, err := service.Withdraw(userID, domain.NewMoney(49_999999, "TRX"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusReceived, small.Status)
+	outbox.AssertNumberOfCalls(t, "SaveWithdrawalWithHold", 1)
+
+	large, err := service.Withdraw(userID, domain.NewMoney(50_000000, "TRX"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusAwaitingApproval, large.Status)
+	approvals.AssertNumberOfCalls(t, "HoldForApproval", 1)
+	outbox.AssertNumberOfCalls(t, "SaveWithdrawalWithHold", 1)
+}
+
+func TestWithdrawService_Fee(t *testing.T) {
+	userID := uint(9)
+	_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
+	fees := new(mocks.FeeSchedule)
+	service.Fees = fees
+
+	rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
+	balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 10_000000}, nil)
+	fees.On("Quote", userID, domain.WithdrawTransaction, mock.AnythingOfType("domain.Money")).Return(domain.NewMoney(1_100000, "TRX"), nil)
+	outbox.On("SaveWithdrawalWithHold", mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.Fee == 1_100000 && tx.Amount.Units == 8_900000
+	})).Return(nil)
+
+	quote, err := service.QuoteFee(userID, domain.NewMoney(5_000000, "TRX"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(6_100000, "TRX"), quote.Total)
+
+	// o saldo precisa cobrir o valor e a tarifa
+	_, err = service.Withdraw(userID, domain.NewMoney(9_000000, "TRX"))
+	assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+
+	tx, err := service.Withdraw(userID, domain.NewMoney(8_900000, "TRX"))
+	assert.NoError(t, err)
+	assert.Equal(t, domain.NewMoney(10_000000, "TRX"), tx.Total())
+	outbox.AssertNumberOfCalls(t, "SaveWithdrawalWithHold", 1)
+}
+
+func TestWithdrawService_Limits(t *testing.T) {
+	userID := uint(9)
+	_, balanceRepo, outbox, rateLimiter, service := setupWithdrawService()
+	policy := new(mocks.LimitPolicy)
+	limitRepo := new(mocks.LimitRepository)
+	service.Limits = services.NewLimitService(policy, limitRepo)
+	approvals := new(mocks.ApprovalRepository)
+	service.Approvals = approvals
+	service.ApprovalThresholds = map[string]domain.Money{"TRX": domain.NewMoney(8_000000, "TRX")}
+
+	limits := domain.Limits{Currency: "TRX", DailyWithdrawal: 10_000000}
+	rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
+	balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 50_000000}, nil)
+	policy.On("LimitsFor", userID, "TRX").Return(limits, nil)
+	limitRepo.On("SaveWithdrawalWithinLimits", mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.Amount.Units == 3_000000 && tx.Status == domain.StatusReceived
+	}), limits).Return(nil).Once()
+	limitRepo.On("SaveWithdrawalWithinLimits", mock.MatchedBy(func(tx domain.Transaction) bool {
+		return tx.Amount.Units == 9_000000 && tx.Status == domain.StatusAwaitingApproval
+	}), limits).Return(fmt.Errorf("%w: daily withdrawal limit is 10.000000", domain.ErrLimitExceeded)).Once()
+
+	_, err := service.Withdraw(userID, domain.NewMoney(3_000000, "TRX"))
+	assert.NoError(t, err)
+
+	// acima do limite de aprovação a retenção também passa pelos tetos
+	_, err = service.Withdraw(userID, domain.NewMoney(9_000000, "TRX"))
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+
+	outbox.AssertNotCalled(t, "SaveWithdrawalWithHold", mock.Anything)
+	approvals.AssertNotCalled(t, "HoldForApproval", mock.Anything)
+	limitRepo.AssertExpectations(t)
+}
+
+func TestLimitService_Status(t *testing.T) {
+	policy := new(mocks.LimitPolicy)
+	limitRepo := new(mocks.LimitRepository)
+	service := services.NewLimitService(policy, limitRepo)
+
+	limits := domain.Limits{Currency: "TRX", MaxWithdrawal: 5_000000, DailyWithdrawal: 10_000000}
+	policy.On("LimitsFor", uint(3), "TRX").Return(limits, nil)
+	limitRepo.On("GetLimitUsage", uint(3), "TRX", mock.AnythingOfType("time.Time")).Return(domain.LimitUsage{DailyWithdrawal: 12_000000, MonthlyWithdrawal: 12_000000}, nil)
+
+	status, err := service.Status(3, "")
+	assert.NoError(t, err)
+
+	display := services.ToLimitDisplay(*status)
+	assert.Equal(t, "5.000000", *display.MaxWithdrawal)
+	assert.Equal(t, "10.000000", *display.DailyWithdrawal.Limit)
+	assert.Equal(t, "0.000000", *display.DailyWithdrawal.Remaining, "um teto reduzido depois do uso não fica negativo")
+	assert.Nil(t, display.MonthlyWithdrawal.Limit)
+	assert.Nil(t, display.MonthlyWithdrawal.Remaining)
+	assert.Equal(t, "12.000000", display.MonthlyWithdrawal.Used)
+
+	negative := int64(-1)
+	assert.Error(t, service.SetOverride(domain.LimitOverride{UserID: 3, Currency: "TRX", DailyDeposit: &negative}))
+	limitRepo.AssertNotCalled(t, "SaveLimitOverride", mock.Anything)
+}
+
+// ----------------- Transfer Tests -----------------
+
+func TestTransferService(t *testing.T) {
+	senderID := uint(5)
+	amount := domain.NewMoney(3_000000, "TRX")
+
+	setup := func() (*mocks.UserRepository, *mocks.BalanceRepository, *mocks.OutboxRepository, *services.TransferService) {
+		users := new(mocks.UserRepository)
+		balanceRepo := new(mocks.BalanceRepository)
+		outbox := new(mocks.OutboxRepository)
+		rateLimiter := new(mocks.RateLimiter)
+		rateLimiter.On("CheckTransactionRateLimit", senderID).Return(nil)
+		balanceRepo.On("GetBalance", senderID, "TRX").Return(&domain.Balance{UserID: senderID, Currency: "TRX", Units: 10_000000}, nil)
+		return users, balanceRepo, outbox, services.NewTransferService(users, balanceRepo, outbox, rateLimiter)
+	}
+
+	t.Run("ByEmail", func(t *testing.T) {
+		users, _, outbox, service := setup()
+		users.On("GetByEmail", "bob@example.com").Return(&domain.User{ID: 6, Email: "bob@example.com"}, nil)
+		outbox.On("SaveTransactionWithOutbox", mock.MatchedBy(func(tx domain.Transaction) bool {
+			return tx.Type == domain.TransferOutTransaction && tx.UserID == senderID && tx.CounterpartyID == 6 && tx.Memo == "lunch"
+		})).Return(nil)
+
+		tx, err := service.Transfer(senderID, 0, " bob@example.com ", amount, " lunch ")
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusReceived, tx.Status)
+		outbox.AssertExpectations(t)
+	})
+
+	t.Run("SelfTransfer", func(t *testing.T) {
+		users, _, outbox, service := setup()
+		users.On("GetByID", senderID).Return(&domain.User{ID: senderID}, nil)
+
+		_, err := service.Transfer(senderID, senderID, "", amount, "")
+		assert.ErrorIs(t, err, domain.ErrSelfTransfer)
+		outbox.AssertNotCalled(t, "SaveTransactionWithOutbox", mock.Anything)
+	})
+
+	t.Run("RecipientNotFound", func(t *testing.T) {
+		users, _, outbox, service := setup()
+		users.On("GetByID", uint(404)).Return(nil, errors.New("record not found"))
+
+		_, err := service.Transfer(senderID, 404, "", amount, "")
+		assert.ErrorIs(t, err, domain.ErrRecipientNotFound)
+
+		_, err = service.Transfer(senderID, 0, "", amount, "")
+		assert.EqualError(t, err, "recipient_id or recipient_email is required")
+		outbox.AssertNotCalled(t, "SaveTransactionWithOutbox", mock.Anything)
+	})
+
+	t.Run("InsufficientFunds", func(t *testing.T) {
+		users, _, outbox, service := setup()
+		users.On("GetByID", uint(6)).Return(&domain.User{ID: 6}, nil)
+
+		_, err := service.Transfer(senderID, 6, "", domain.NewMoney(11_000000, "TRX"), "")
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+		outbox.AssertNotCalled(t, "SaveTransactionWithOutbox", mock.Anything)
+	})
+
+	t.Run("MemoTooLong", func(t *testing.T) {
+		users, _, _, service := setup()
+
+		_, err := service.Transfer(senderID, 6, "", amount, strings.Repeat("x", domain.MaxTransferMemoLength+1))
+		assert.ErrorIs(t, err, domain.ErrMemoTooLong)
+		users.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+}
+
+func TestScheduleService(t *testing.T) {
+	userID := uint(5)
+	amount := domain.NewMoney(3_000000, "TRX")
+
+	setup := func() (*mocks.ScheduleRepository, *mocks.UserRepository, *mocks.OutboxRepository, *services.ScheduleService) {
+		schedules := new(mocks.ScheduleRepository)
+		users := new(mocks.UserRepository)
+		balanceRepo := new(mocks.BalanceRepository)
+		outbox := new(mocks.OutboxRepository)
+		rateLimiter := new(mocks.RateLimiter)
+		rateLimiter.On("CheckTransactionRateLimit", userID).Return(nil)
+		balanceRepo.On("GetBalance", userID, "TRX").Return(&domain.Balance{UserID: userID, Currency: "TRX", Units: 10_000000}, nil)
+		users.On("GetByID", uint(6)).Return(&domain.User{ID: 6}, nil)
+		users.On("GetByID", userID).Return(&domain.User{ID: userID}, nil)
+		transfers := services.NewTransferService(users, balanceRepo, outbox, rateLimiter)
+		return schedules, users, outbox, services.NewScheduleService(schedules, nil, transfers)
+	}
+
+	t.Run("CreateMonthlyTransfer", func(t *testing.T) {
+		schedules, _, _, service := setup()
+		start := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+		schedules.On("CreateSchedule", mock.MatchedBy(func(s *domain.Schedule) bool {
+			return s.UserID == userID && s.RecipientID == 6 && s.NextRunAt != nil && s.NextRunAt.Equal(start)
+		})).Return(nil)
+
+		s, err := service.Create(userID, services.ScheduleInput{
+			Type: domain.ScheduleTransfer, Amount: amount, RecipientID: 6,
+			Recurrence: domain.RecurrenceMonthly, StartAt: start,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RecurrenceMonthly, s.Recurrence)
+		schedules.AssertExpectations(t)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		schedules, _, _, service := setup()
+		valid := services.ScheduleInput{Type: domain.ScheduleTransfer, Amount: amount, RecipientID: 6, Recurrence: domain.RecurrenceDaily}
+
+		past := valid
+		past.StartAt = time.Now().Add(-time.Hour)
+		_, err := service.Create(userID, past)
+		assert.ErrorIs(t, err, domain.ErrInvalidSchedule)
+
+		badCron := valid
+		badCron.Recurrence, badCron.Cron = domain.RecurrenceCron, "61 * * * *"
+		_, err = service.Create(userID, badCron)
+		assert.ErrorIs(t, err, domain.ErrInvalidSchedule)
+
+		self := valid
+		self.RecipientID = userID
+		_, err = service.Create(userID, self)
+		assert.ErrorIs(t, err, domain.ErrSelfTransfer)
+
+		unknownType := valid
+		unknownType.Type = "deposit"
+		_, err = service.Create(userID, unknownType)
+		assert.ErrorIs(t, err, domain.ErrInvalidSchedule)
+
+		schedules.AssertNotCalled(t, "CreateSchedule", mock.Anything)
+	})
+
+	t.Run("UpdateKeepsRunningStart", func(t *testing.T) {
+		schedules, _, _, service := setup()
+		start := time.Now().UTC().AddDate(0, 0, -10).Truncate(time.Second)
+		schedules.On("GetSchedule", userID, uint(9)).Return(&domain.Schedule{
+			ID: 9, UserID: userID, Type: domain.ScheduleTransfer, Amount: amount, RecipientID: 6,
+			Recurrence: domain.RecurrenceDaily, StartAt: start, Runs: 10,
+		}, nil)
+		schedules.On("UpdateSchedule", mock.MatchedBy(func(s *domain.Schedule) bool {
+			return s.Runs == 10 && s.NextRunAt != nil && s.NextRunAt.After(time.Now())
+		})).Return(nil)
+
+		s, err := service.Update(userID, 9, services.ScheduleInput{
+			Type: domain.ScheduleTransfer, Amount: domain.NewMoney(4_000000, "TRX"), RecipientID: 6,
+			Recurrence: domain.RecurrenceDaily, StartAt: start,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4_000000), s.Amount.Units)
+		schedules.AssertExpectations(t)
+	})
+
+	t.Run("ExecuteUsesOccurrenceID", func(t *testing.T) {
+		_, _, outbox, service := setup()
+		outbox.On("SaveTransactionWithOutbox", mock.MatchedBy(func(tx domain.Transaction) bool {
+			return tx.ID == "occurrence-1" && tx.CounterpartyID == 6 && tx.Memo == "rent"
+		})).Return(nil)
+
+		tx, err := service.ExecuteSchedule(domain.Schedule{
+			UserID: userID, Type: domain.ScheduleTransfer, Amount: amount, RecipientID: 6, Memo: "rent",
+		}, "occurrence-1")
+		assert.NoError(t, err)
+		assert.Equal(t, "occurrence-1", tx.ID)
+		outbox.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+
+	"github.com/google/uuid"
+)
+
+type TransferService struct {
+	Users       d.UserRepository
+	BalanceRepo d.BalanceRepository
+	Outbox      d.OutboxRepository
+	RateLimiter d.RateLimiter
+	// Fees calcula a tarifa paga pelo remetente além do valor; sem ela não há tarifa
+	Fees d.FeeSchedule
+}
+
+func NewTransferService(u d.UserRepository, b d.BalanceRepository, o d.OutboxRepository, rate d.RateLimiter) *TransferService {
+	return &TransferService{
+		Users:       u,
+		BalanceRepo: b,
+		Outbox:      o,
+		RateLimiter: rate,
+	}
+}
+
+// Transfer envia amount para outro usuário da plataforma, identificado pelo ID ou, sem ele, pelo e-mail.
+// Só a perna do remetente é gravada aqui; o worker aplica os dois saldos e grava a perna do destinatário
+func (s *TransferService) Transfer(fromUserID, recipientID uint, recipientEmail string, amount d.Money, memo string) (*d.Transaction, error) {
+	return s.transfer(uuid.New().String(), fromUserID, recipientID, recipientEmail, amount, memo)
+}
+
+// ScheduledTransfer executa a ocorrência de um agendamento com o ID derivado dela, como ScheduledWithdraw
+func (s *TransferService) ScheduledTransfer(txID string, fromUserID, recipientID uint, amount d.Money, memo string) (*d.Transaction, error) {
+	return s.transfer(txID, fromUserID, recipientID, "", amount, memo)
+}
+
+func (s *TransferService) transfer(txID string, fromUserID, recipientID uint, recipientEmail string, amount d.Money, memo string) (*d.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	memo = strings.TrimSpace(memo)
+	if len([]rune(memo)) > d.MaxTransferMemoLength {
+		return nil, d.ErrMemoTooLong
+	}
+
+	recipient, err := s.findRecipient(recipientID, recipientEmail)
+	if err != nil {
+		return nil, err
+	}
+	if recipient.ID == fromUserID {
+		return nil, d.ErrSelfTransfer
+	}
+
+	if err := s.RateLimiter.CheckTransactionRateLimit(fromUserID); err != nil {
+		return nil, err
+	}
+
+	quote, err := quoteFee(s.Fees, fromUserID, d.TransferOutTransaction, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	// leitura sem lock só para recusar cedo; o worker confere o saldo com os dois saldos travados
+	bal, err := s.BalanceRepo.GetBalance(fromUserID, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if bal.Amount().Cmp(quote.Total) < 0 {
+		return nil, d.ErrInsufficientFunds
+	}
+
+	tx := d.Transaction{
+		ID:             txID,
+		UserID:         fromUserID,
+		User:           d.User{ID: fromUserID},
+		Amount:         amount,
+		Fee:            quote.Fee.Units,
+		Timestamp:      time.Now(),
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+		Type:           d.TransferOutTransaction,
+		Status:         d.StatusReceived,
+		CounterpartyID: recipient.ID,
+		Memo:           memo,
+	}
+
+	if err := s.Outbox.SaveTransactionWithOutbox(tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// QuoteFee mostra a tarifa de uma transferência antes do envio
+func (s *TransferService) QuoteFee(userID uint, amount d.Money) (d.FeeQuote, error) {
+	if !amount.IsPositive() {
+		return d.FeeQuote{}, errors.New("amount must be greater than zero")
+	}
+	return quoteFee(s.Fees, userID, d.TransferOutTransaction, amount)
+}
+
+func (s *TransferService) findRecipient(recipientID uint, recipientEmail string) (*d.User, error) {
+	var (
+		user *d.User
+		err  error
+	)
+	switch {
+	case recipientID != 0:
+		user, err = s.Users.GetByID(recipientID)
+	case strings.TrimSpace(recipientEmail) != "":
+		user, err = s.Users.GetByEmail(strings.TrimSpace(recipientEmail))
+	default:
+		return nil, errors.New("recipient_id or recipient_email is required")
+	}
+	if err != nil || user == nil {
+		return nil, d.ErrRecipientNotFound
+	}
+	return user, nil
+}
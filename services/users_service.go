@@ -12,10 +12,12 @@ import (
 
 type UserService struct {
 	repo d.UserRepository
+	// Addresses valida a carteira do cadastro; por padrão offline, sem depender do nó
+	Addresses d.AddressValidator
 }
 
 func NewUserService(repo d.UserRepository) *UserService {
-	return &UserService{repo: repo}
+	return &UserService{repo: repo, Addresses: client.TronAddressValidator{}}
 }
 
 func (s *UserService) CreateUser(user *d.User) error {
@@ -25,10 +27,15 @@ func (s *UserService) CreateUser(user *d.User) error {
 	}
 
 	if user.WalletAddress != "" {
-		valid, err := client.ValidateTronAddress(user.WalletAddress)
-		if err != nil || !valid {
-			return fmt.Errorf("endereço TRON inválido")
+		if err := s.Addresses.ValidateAddress(user.WalletAddress); err != nil {
+			return fmt.Errorf("endereço TRON inválido: %w", err)
 		}
+		// a forma hex é guardada como Base58Check, a usada nos envios
+		normalized, err := client.TronAddressToBase58(user.WalletAddress)
+		if err != nil {
+			return fmt.Errorf("endereço TRON inválido: %w", err)
+		}
+		user.WalletAddress = normalized
 	}
 
 	user.Password = string(hashedPassword)
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/client"
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const maxWithdrawalAddressLabel = 64
+
+var (
+	// ErrReauthenticationFailed indica senha ausente ou errada em uma operação que exige reautenticação
+	ErrReauthenticationFailed = errors.New("reauthentication failed")
+	ErrInvalidLabel           = fmt.Errorf("label must have at most %d characters", maxWithdrawalAddressLabel)
+)
+
+// WithdrawalAddressService mantém a lista de destinos de saque do usuário. Incluir ou remover um
+// destino exige a senha de novo, e um destino novo só pode ser usado depois de Cooldown.
+type WithdrawalAddressService struct {
+	Repo  d.WithdrawalAddressRepository
+	Users d.UserRepository
+	// Validators valida o endereço conforme a rede do ativo; redes sem validador são recusadas
+	Validators map[string]d.AddressValidator
+	Cooldown   time.Duration
+}
+
+func NewWithdrawalAddressService(r d.WithdrawalAddressRepository, users d.UserRepository, validators map[string]d.AddressValidator, cooldown time.Duration) *WithdrawalAddressService {
+	return &WithdrawalAddressService{
+		Repo:       r,
+		Users:      users,
+		Validators: validators,
+		Cooldown:   cooldown,
+	}
+}
+
+func (s *WithdrawalAddressService) List(userID uint) ([]d.WithdrawalAddress, error) {
+	return s.Repo.ListWithdrawalAddresses(userID)
+}
+
+func (s *WithdrawalAddressService) Add(userID uint, password, label, currency, address string) (*d.WithdrawalAddress, error) {
+	label = strings.TrimSpace(label)
+	if len(label) > maxWithdrawalAddressLabel {
+		return nil, ErrInvalidLabel
+	}
+
+	asset, err := d.LookupAsset(currency)
+	if err != nil {
+		return nil, err
+	}
+	validator, ok := s.Validators[asset.Chain]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", d.ErrChainNotSupported, asset.Chain)
+	}
+	address = strings.TrimSpace(address)
+	if err := validator.ValidateAddress(address); err != nil {
+		return nil, err
+	}
+	if asset.Chain == d.ChainTron {
+		// a forma hex é guardada como Base58Check, a usada nos envios e na checagem de duplicidade
+		if address, err = client.TronAddressToBase58(address); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.reauthenticate(userID, password); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	addr := &d.WithdrawalAddress{
+		UserID:    userID,
+		Label:     label,
+		Currency:  asset.Symbol,
+		Chain:     asset.Chain,
+		Address:   address,
+		ActiveAt:  now.Add(s.Cooldown),
+		CreatedAt: now,
+	}
+	if err := s.Repo.SaveWithdrawalAddress(addr); err != nil {
+		return nil, err
+	}
+	return addr, nil
+}
+
+func (s *WithdrawalAddressService) Remove(userID uint, password string, id uint) error {
+	if err := s.reauthenticate(userID, password); err != nil {
+		return err
+	}
+	return s.Repo.DeleteWithdrawalAddress(userID, id)
+}
+
+// reauthenticate confere a senha mesmo com um JWT válido: um token vazado não basta para
+// cadastrar um destino
+func (s *WithdrawalAddressService) reauthenticate(userID uint, password string) error {
+	if password == "" {
+		return ErrReauthenticationFailed
+	}
+	user, err := s.Users.GetByID(userID)
+	if err != nil {
+		return ErrReauthenticationFailed
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return ErrReauthenticationFailed
+	}
+	return nil
+}
@@ -2,10 +2,10 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	d "github.com/gabrielksneiva/go-financial-transactions/domain"
-	p "github.com/gabrielksneiva/go-financial-transactions/producer"
 
 	"github.com/google/uuid"
 )
@@ -13,43 +13,170 @@ import (
 type WithdrawService struct {
 	Repo        d.TransactionRepository
 	BalanceRepo d.BalanceRepository
-	producer    p.Producer
+	Outbox      d.OutboxRepository
 	RateLimiter d.RateLimiter
+	// Destinations resolve o destino escolhido em WithdrawTo; sem ele só o saque para a carteira
+	// do cadastro é aceito
+	Destinations d.WithdrawalAddressRepository
+	// Saques a partir de ApprovalThresholds[moeda] ficam retidos até um admin decidir
+	Approvals          d.ApprovalRepository
+	ApprovalThresholds map[string]d.Money
+	// Fees calcula a tarifa cobrada além do valor; sem ela os saques não têm tarifa
+	Fees d.FeeSchedule
+	// Limits aplica os tetos por saque, diário e mensal; sem ele não há teto
+	Limits *LimitService
 }
 
-func NewWithdrawService(r d.TransactionRepository, b d.BalanceRepository, p p.Producer, rate d.RateLimiter) *WithdrawService {
+func NewWithdrawService(r d.TransactionRepository, b d.BalanceRepository, o d.OutboxRepository, rate d.RateLimiter) *WithdrawService {
 	return &WithdrawService{
 		Repo:        r,
 		BalanceRepo: b,
-		producer:    p,
+		Outbox:      o,
 		RateLimiter: rate,
 	}
 }
 
-func (s *WithdrawService) Withdraw(userID uint, amount float64) error {
+// Withdraw saca para a carteira do cadastro do usuário
+func (s *WithdrawService) Withdraw(userID uint, amount d.Money) (*d.Transaction, error) {
+	return s.WithdrawTo(userID, amount, 0)
+}
+
+// WithdrawTo saca para um destino da lista do usuário; destinationID 0 usa a carteira do cadastro
+func (s *WithdrawService) WithdrawTo(userID uint, amount d.Money, destinationID uint) (*d.Transaction, error) {
+	return s.withdraw(uuid.New().String(), userID, amount, destinationID)
+}
+
+// ScheduledWithdraw executa a ocorrência de um agendamento com o ID derivado dela: repetir a mesma
+// ocorrência falha ao gravar a transação em vez de sacar duas vezes
+func (s *WithdrawService) ScheduledWithdraw(txID string, userID uint, amount d.Money, destinationID uint) (*d.Transaction, error) {
+	return s.withdraw(txID, userID, amount, destinationID)
+}
+
+func (s *WithdrawService) withdraw(txID string, userID uint, amount d.Money, destinationID uint) (*d.Transaction, error) {
+	if !amount.IsPositive() {
+		return nil, errors.New("amount must be greater than zero")
+	}
+
+	// só moedas com envio on-chain configurado (TRX ou um token TRC-20 registrado) podem ser sacadas
+	asset, err := d.LookupAsset(amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	var toAddress string
+	if destinationID != 0 {
+		if s.Destinations == nil {
+			return nil, d.ErrWithdrawalAddressNotFound
+		}
+		dest, err := s.Destinations.GetWithdrawalAddress(userID, destinationID)
+		if err != nil {
+			return nil, err
+		}
+		if !dest.IsActive(time.Now()) {
+			return nil, fmt.Errorf("%w until %s", d.ErrWithdrawalAddressCoolingDown, dest.ActiveAt.UTC().Format(time.RFC3339))
+		}
+		if dest.Chain != asset.Chain {
+			return nil, d.ErrDestinationChainMismatch
+		}
+		toAddress = dest.Address
+	}
+
 	if err := s.RateLimiter.CheckTransactionRateLimit(userID); err != nil {
-		return err
+		return nil, err
 	}
 
-	bal, err := s.BalanceRepo.GetBalance(userID)
+	quote, err := quoteFee(s.Fees, userID, d.WithdrawTransaction, amount)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if bal.Amount < amount {
-		return errors.New("insufficient funds")
+	// leitura sem lock só para recusar cedo; quem garante o saldo é a reserva feita ao gravar
+	bal, err := s.BalanceRepo.GetBalance(userID, amount.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if bal.Amount().Cmp(quote.Total) < 0 {
+		return nil, d.ErrInsufficientFunds
 	}
 
 	tx := d.Transaction{
-		ID:        uuid.New().String(),
+		ID:        txID,
 		UserID:    userID,
 		User:      d.User{ID: userID},
 		Amount:    amount,
+		Fee:       quote.Fee.Units,
 		Timestamp: time.Now(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		Type:      "withdraw",
+		Status:    d.StatusReceived,
+		// vazio: o worker envia para a carteira do cadastro
+		WalletAddress: toAddress,
+	}
+
+	if s.requiresApproval(amount) {
+		// o valor sai do saldo agora, para que não seja gasto enquanto espera a decisão
+		tx.Status = d.StatusAwaitingApproval
 	}
 
-	return s.producer.SendTransaction(tx)
+	// com tetos configurados, a soma do período e a reserva são feitas na mesma transação do banco
+	if s.Limits != nil {
+		if err := s.Limits.saveWithdrawal(tx); err != nil {
+			return nil, err
+		}
+		return &tx, nil
+	}
+
+	if tx.Status == d.StatusAwaitingApproval {
+		if err := s.Approvals.HoldForApproval(tx); err != nil {
+			return nil, err
+		}
+		return &tx, nil
+	}
+
+	// O valor é reservado no saldo junto com a gravação da transação: pedidos concorrentes não
+	// passam do saldo disponível. O relay do outbox publica no Kafka depois, mesmo que o Kafka
+	// esteja fora agora, e o worker captura a reserva
+	if err := s.Outbox.SaveWithdrawalWithHold(tx); err != nil {
+		return nil, err
+	}
+
+	return &tx, nil
+}
+
+// QuoteFee mostra a tarifa de um saque antes do envio
+func (s *WithdrawService) QuoteFee(userID uint, amount d.Money) (d.FeeQuote, error) {
+	if !amount.IsPositive() {
+		return d.FeeQuote{}, errors.New("amount must be greater than zero")
+	}
+	if _, err := d.LookupAsset(amount.Currency); err != nil {
+		return d.FeeQuote{}, err
+	}
+	return quoteFee(s.Fees, userID, d.WithdrawTransaction, amount)
+}
+
+func (s *WithdrawService) requiresApproval(amount d.Money) bool {
+	if s.Approvals == nil {
+		return false
+	}
+	threshold, ok := s.ApprovalThresholds[amount.Currency]
+	return ok && amount.Cmp(threshold) >= 0
+}
+
+// quoteFee cota a tarifa de uma transação; sem tabela de tarifas nada é cobrado
+func quoteFee(fees d.FeeSchedule, userID uint, txType string, amount d.Money) (d.FeeQuote, error) {
+	fee := d.Zero(amount.Currency)
+	if fees != nil {
+		var err error
+		if fee, err = fees.Quote(userID, txType, amount); err != nil {
+			return d.FeeQuote{}, err
+		}
+	}
+
+	total, err := amount.Add(fee)
+	if err != nil {
+		return d.FeeQuote{}, err
+	}
+	return d.FeeQuote{Amount: amount, Fee: fee, Total: total}, nil
 }
@@ -0,0 +1,195 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+)
+
+var ErrNoKeys = errors.New("keystore has no keys")
+
+// Parâmetros do scrypt usados ao cifrar novas chaves; os testes usam os parâmetros leves
+// do go-ethereum. A decifragem lê os parâmetros gravados em cada arquivo.
+var (
+	ScryptN = keystore.StandardScryptN
+	ScryptP = keystore.StandardScryptP
+)
+
+// Keystore guarda as chaves em arquivos JSON v3 do go-ethereum (scrypt + AES-128-CTR), um por
+// chave, nomeados UTC--<data>--<endereço>. A chave ativa é a mais recente; as anteriores
+// ficam no diretório para que os fundos dos endereços antigos ainda possam ser movidos.
+type Keystore struct {
+	dir        string
+	passphrase string
+
+	mu     sync.RWMutex
+	active *ecdsa.PrivateKey
+}
+
+// OpenKeystore destranca a chave ativa do diretório com a senha
+func OpenKeystore(dir, passphrase string) (*Keystore, error) {
+	files, err := keyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoKeys, dir)
+	}
+
+	key, err := decryptFile(files[len(files)-1], passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Keystore{dir: dir, passphrase: passphrase, active: key}, nil
+}
+
+// CreateKeystore cria o diretório com uma primeira chave aleatória
+func CreateKeystore(dir, passphrase string) (*Keystore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	files, err := keyFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		return nil, fmt.Errorf("keystore %s já possui chaves", dir)
+	}
+
+	ks := &Keystore{dir: dir, passphrase: passphrase}
+	if _, err := ks.Rotate(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+func (k *Keystore) PublicKey() (*ecdsa.PublicKey, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return &k.active.PublicKey, nil
+}
+
+func (k *Keystore) SignHash(hash []byte) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return signHash(hash, k.active)
+}
+
+// Rotate gera uma nova chave, grava cifrada com a mesma senha e passa a assinar com ela.
+// O endereço muda: a carteira nova precisa ser abastecida e a antiga, esvaziada.
+func (k *Keystore) Rotate() (*ecdsa.PublicKey, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	encrypted, err := keystore.EncryptKey(&keystore.Key{Id: uuid.New(), Address: address, PrivateKey: key}, k.passphrase, ScryptN, ScryptP)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao cifrar chave: %w", err)
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	// o nome com nanossegundos mantém a ordem mesmo com duas rotações no mesmo segundo
+	name := fmt.Sprintf("UTC--%s--%x", time.Now().UTC().Format("2006-01-02T15-04-05.000000000Z"), address.Bytes())
+	if err := writeFileAtomic(filepath.Join(k.dir, name), encrypted); err != nil {
+		return nil, err
+	}
+
+	k.active = key
+	return &key.PublicKey, nil
+}
+
+// ChangePassphrase cifra de novo todas as chaves do diretório com a nova senha
+func (k *Keystore) ChangePassphrase(newPassphrase string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	files, err := keyFiles(k.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		key, err := keystore.DecryptKey(data, k.passphrase)
+		if err != nil {
+			return fmt.Errorf("erro ao decifrar %s: %w", filepath.Base(file), err)
+		}
+		encrypted, err := keystore.EncryptKey(key, newPassphrase, ScryptN, ScryptP)
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(file, encrypted); err != nil {
+			return err
+		}
+	}
+
+	k.passphrase = newPassphrase
+	return nil
+}
+
+func keyFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), "UTC--") {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func decryptFile(path, passphrase string) (*ecdsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decifrar %s: %w", filepath.Base(path), err)
+	}
+	return key.PrivateKey, nil
+}
+
+// writeFileAtomic grava em um arquivo temporário e renomeia: uma queda no meio não deixa
+// um arquivo de chave truncado
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-key-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,213 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/rpc"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const serviceName = "Signer"
+
+type SignArgs struct {
+	Hash []byte
+}
+
+type SignReply struct {
+	Signature []byte
+}
+
+type PublicKeyReply struct {
+	// chave pública não comprimida (0x04 || X || Y)
+	PublicKey []byte
+}
+
+// Service é o que o processo signer expõe no socket; só assinaturas e chaves públicas
+// saem dele, nunca a chave privada. A rotação fica fora do socket, só no `cmd/signer -rotate`,
+// para que quem alcança o socket não possa trocar a carteira de envio.
+type Service struct {
+	signer d.Signer
+}
+
+func (s *Service) Sign(args SignArgs, reply *SignReply) error {
+	sig, err := s.signer.SignHash(args.Hash)
+	if err != nil {
+		return err
+	}
+	reply.Signature = sig
+	return nil
+}
+
+func (s *Service) PublicKey(_ struct{}, reply *PublicKeyReply) error {
+	pub, err := s.signer.PublicKey()
+	if err != nil {
+		return err
+	}
+	reply.PublicKey = crypto.FromECDSAPub(pub)
+	return nil
+}
+
+// ListenUnix abre o socket com permissão só para o dono, removendo um socket antigo
+// deixado por um processo que não encerrou direito
+func ListenUnix(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// Serve atende conexões até o ctx ser cancelado; ao sair, fecha as conexões abertas
+func Serve(ctx context.Context, listener net.Listener, signer d.Signer) error {
+	server := rpc.NewServer()
+	if err := server.RegisterName(serviceName, &Service{signer: signer}); err != nil {
+		return err
+	}
+
+	var (
+		mu    sync.Mutex
+		conns = map[net.Conn]struct{}{}
+		wg    sync.WaitGroup
+	)
+	defer func() {
+		mu.Lock()
+		for conn := range conns {
+			conn.Close()
+		}
+		mu.Unlock()
+		wg.Wait()
+	}()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Println("🛑 Signer encerrado")
+				return nil
+			}
+			return err
+		}
+
+		mu.Lock()
+		conns[conn] = struct{}{}
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.ServeConn(conn)
+			mu.Lock()
+			delete(conns, conn)
+			mu.Unlock()
+		}()
+	}
+}
+
+// RemoteSigner fala com o processo signer pelo socket Unix. A chave pública é consultada a
+// cada uso, então a chave ativa depois de uma rotação (e do reinício do signer) vale já para
+// o próximo envio.
+type RemoteSigner struct {
+	network string
+	address string
+
+	mu     sync.Mutex
+	client *rpc.Client
+}
+
+func Dial(socketPath string) (*RemoteSigner, error) {
+	r := &RemoteSigner{network: "unix", address: socketPath}
+	if _, err := r.PublicKey(); err != nil {
+		return nil, fmt.Errorf("erro ao conectar no signer em %s: %w", socketPath, err)
+	}
+	return r, nil
+}
+
+func (r *RemoteSigner) PublicKey() (*ecdsa.PublicKey, error) {
+	var reply PublicKeyReply
+	if err := r.call("PublicKey", struct{}{}, &reply); err != nil {
+		return nil, err
+	}
+	return crypto.UnmarshalPubkey(reply.PublicKey)
+}
+
+func (r *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	var reply SignReply
+	if err := r.call("Sign", SignArgs{Hash: hash}, &reply); err != nil {
+		return nil, err
+	}
+	return reply.Signature, nil
+}
+
+func (r *RemoteSigner) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		return nil
+	}
+	err := r.client.Close()
+	r.client = nil
+	return err
+}
+
+// call reconecta uma vez quando o signer foi reiniciado e a conexão antiga caiu
+func (r *RemoteSigner) call(method string, args, reply any) error {
+	for attempt := 0; ; attempt++ {
+		client, err := r.conn()
+		if err != nil {
+			return err
+		}
+		err = client.Call(serviceName+"."+method, args, reply)
+		if err == nil {
+			return nil
+		}
+
+		var serverErr rpc.ServerError
+		if errors.As(err, &serverErr) || attempt > 0 {
+			return err
+		}
+		r.reset(client)
+	}
+}
+
+func (r *RemoteSigner) conn() (*rpc.Client, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == nil {
+		client, err := rpc.Dial(r.network, r.address)
+		if err != nil {
+			return nil, err
+		}
+		r.client = client
+	}
+	return r.client, nil
+}
+
+func (r *RemoteSigner) reset(stale *rpc.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.client == stale {
+		r.client.Close()
+		r.client = nil
+	}
+}
@@ -0,0 +1,55 @@
+// Package signer guarda a chave da carteira quente fora do código dos drivers: o keystore
+// cifrado a mantém só no processo que a destrancou e o RemoteSigner fala com um processo
+// separado por socket Unix, de modo que a API e os workers nunca veem a chave.
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+var ErrInvalidHash = errors.New("hash must have 32 bytes")
+
+var (
+	_ d.Signer = (*PrivateKeySigner)(nil)
+	_ d.Signer = (*Keystore)(nil)
+	_ d.Signer = (*RemoteSigner)(nil)
+)
+
+// PrivateKeySigner mantém a chave em memória; usado nos testes e pelo TRON_PRIVATE_KEY legado
+type PrivateKeySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func NewPrivateKeySigner(key *ecdsa.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{key: key}
+}
+
+// FromHex parseia uma chave hex sem 0x, o formato de TRON_PRIVATE_KEY
+func FromHex(hexKey string) (*PrivateKeySigner, error) {
+	key, err := crypto.HexToECDSA(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("chave privada inválida: %w", err)
+	}
+	return NewPrivateKeySigner(key), nil
+}
+
+func (s *PrivateKeySigner) PublicKey() (*ecdsa.PublicKey, error) {
+	return &s.key.PublicKey, nil
+}
+
+func (s *PrivateKeySigner) SignHash(hash []byte) ([]byte, error) {
+	return signHash(hash, s.key)
+}
+
+func signHash(hash []byte, key *ecdsa.PrivateKey) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("%w: got %d", ErrInvalidHash, len(hash))
+	}
+	return crypto.Sign(hash, key)
+}
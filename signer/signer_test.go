@@ -0,0 +1,182 @@
+package signer_test
+
+import (
+	"context"
+	"net/rpc"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/signer"
+)
+
+func TestMain(m *testing.M) {
+	// o scrypt padrão usa 256MB por chave; os testes usam os parâmetros leves
+	signer.ScryptN, signer.ScryptP = keystore.LightScryptN, keystore.LightScryptP
+	os.Exit(m.Run())
+}
+
+// assertSigns confere que a assinatura recupera a chave pública informada pelo signer
+func assertSigns(t *testing.T, s d.Signer) {
+	hash := crypto.Keccak256([]byte("withdraw tx-1"))
+	sig, err := s.SignHash(hash)
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+
+	recovered, err := crypto.SigToPub(hash, sig)
+	require.NoError(t, err)
+	pub, err := s.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(*pub), crypto.PubkeyToAddress(*recovered))
+}
+
+func publicAddress(t *testing.T, s d.Signer) string {
+	pub, err := s.PublicKey()
+	require.NoError(t, err)
+	return crypto.PubkeyToAddress(*pub).Hex()
+}
+
+func TestKeystore_CreateOpenAndSign(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "keys")
+	ks, err := signer.CreateKeystore(dir, "correct horse")
+	require.NoError(t, err)
+	assertSigns(t, ks)
+
+	reopened, err := signer.OpenKeystore(dir, "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, publicAddress(t, ks), publicAddress(t, reopened))
+
+	_, err = signer.OpenKeystore(dir, "wrong")
+	assert.ErrorIs(t, err, keystore.ErrDecrypt)
+
+	_, err = signer.CreateKeystore(dir, "correct horse")
+	assert.Error(t, err)
+
+	_, err = signer.OpenKeystore(t.TempDir(), "correct horse")
+	assert.ErrorIs(t, err, signer.ErrNoKeys)
+
+	// o arquivo é o JSON v3 do go-ethereum, cifrado com scrypt
+	files, _ := filepath.Glob(filepath.Join(dir, "UTC--*"))
+	require.Len(t, files, 1)
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"kdf":"scrypt"`)
+	assert.Contains(t, string(data), `"cipher":"aes-128-ctr"`)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	_, err = ks.SignHash([]byte("short"))
+	assert.ErrorIs(t, err, signer.ErrInvalidHash)
+}
+
+func TestKeystore_RotateAndChangePassphrase(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := signer.CreateKeystore(dir, "old")
+	require.NoError(t, err)
+	first := publicAddress(t, ks)
+
+	_, err = ks.Rotate()
+	require.NoError(t, err)
+	second := publicAddress(t, ks)
+	assert.NotEqual(t, first, second)
+	assertSigns(t, ks)
+
+	// a chave ativa é a mais recente; a anterior continua no diretório
+	reopened, err := signer.OpenKeystore(dir, "old")
+	require.NoError(t, err)
+	assert.Equal(t, second, publicAddress(t, reopened))
+	files, _ := filepath.Glob(filepath.Join(dir, "UTC--*"))
+	assert.Len(t, files, 2)
+
+	require.NoError(t, ks.ChangePassphrase("new"))
+	_, err = signer.OpenKeystore(dir, "old")
+	assert.Error(t, err)
+	reopened, err = signer.OpenKeystore(dir, "new")
+	require.NoError(t, err)
+	assert.Equal(t, second, publicAddress(t, reopened))
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, err = keystore.DecryptKey(data, "new")
+		assert.NoError(t, err, file)
+	}
+}
+
+func serveKeystore(t *testing.T, socket string, ks *signer.Keystore) context.CancelFunc {
+	listener, err := signer.ListenUnix(socket)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, signer.Serve(ctx, listener, ks))
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestRemoteSigner_SignsAndReconnectsAfterRotation(t *testing.T) {
+	dir := t.TempDir()
+	ks, err := signer.CreateKeystore(dir, "pass")
+	require.NoError(t, err)
+
+	// socket curto: o caminho de um socket Unix tem limite de ~100 bytes
+	socketDir, err := os.MkdirTemp("", "signer")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(socketDir) })
+	socket := filepath.Join(socketDir, "s.sock")
+
+	stop := serveKeystore(t, socket, ks)
+
+	remote, err := signer.Dial(socket)
+	require.NoError(t, err)
+	defer remote.Close()
+
+	assert.Equal(t, publicAddress(t, ks), publicAddress(t, remote))
+	assertSigns(t, remote)
+
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// erros do signer chegam ao cliente
+	_, err = remote.SignHash([]byte("short"))
+	assert.ErrorContains(t, err, signer.ErrInvalidHash.Error())
+
+	// a rotação não é exposta no socket
+	rawClient, err := rpc.Dial("unix", socket)
+	require.NoError(t, err)
+	defer rawClient.Close()
+	assert.Error(t, rawClient.Call("Signer.Rotate", struct{}{}, &signer.PublicKeyReply{}))
+
+	// a rotação é feita pelo cmd/signer -rotate no keystore; o signer reinicia com a chave nova
+	// e o cliente reconecta na próxima chamada
+	before := publicAddress(t, remote)
+	stop()
+	rotated, err := signer.OpenKeystore(dir, "pass")
+	require.NoError(t, err)
+	_, err = rotated.Rotate()
+	require.NoError(t, err)
+	stop = serveKeystore(t, socket, rotated)
+	defer stop()
+
+	assert.NotEqual(t, before, publicAddress(t, remote))
+	assert.Equal(t, publicAddress(t, rotated), publicAddress(t, remote))
+	assertSigns(t, remote)
+}
+
+func TestDial_NoSigner(t *testing.T) {
+	_, err := signer.Dial(filepath.Join(t.TempDir(), "missing.sock"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,250 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mr-tron/base58"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// HardenedOffset é somado ao índice para derivação hardened (i' no path)
+const HardenedOffset uint32 = 0x80000000
+
+var (
+	versionXprv = [4]byte{0x04, 0x88, 0xad, 0xe4}
+	versionXpub = [4]byte{0x04, 0x88, 0xb2, 0x1e}
+
+	ErrInvalidExtendedKey = errors.New("invalid extended key")
+	ErrHardenedFromPublic = errors.New("cannot derive a hardened child from a public key")
+	ErrInvalidChild       = errors.New("derived child key is invalid, use the next index")
+)
+
+// ExtendedKey é uma chave BIP32 (xprv ou xpub). key guarda os 32 bytes da chave privada
+// ou os 33 bytes da chave pública comprimida.
+type ExtendedKey struct {
+	depth       uint8
+	parentFP    [4]byte
+	childNumber uint32
+	chainCode   [32]byte
+	key         []byte
+	private     bool
+}
+
+// NewMaster gera a chave mestra a partir da seed (ex.: seed BIP39)
+func NewMaster(seed []byte) (*ExtendedKey, error) {
+	if len(seed) < 16 || len(seed) > 64 {
+		return nil, fmt.Errorf("seed must have between 16 and 64 bytes, got %d", len(seed))
+	}
+
+	il, ir := hmac512([]byte("Bitcoin seed"), seed)
+	if !validScalar(il) {
+		return nil, ErrInvalidChild
+	}
+
+	master := &ExtendedKey{key: il, private: true}
+	copy(master.chainCode[:], ir)
+	return master, nil
+}
+
+// ParseExtendedKey lê uma chave serializada em base58check (xprv... ou xpub...)
+func ParseExtendedKey(encoded string) (*ExtendedKey, error) {
+	raw, err := base58.Decode(encoded)
+	if err != nil || len(raw) != 82 {
+		return nil, ErrInvalidExtendedKey
+	}
+
+	payload, sum := raw[:78], raw[78:]
+	if !bytes.Equal(checksum(payload), sum) {
+		return nil, fmt.Errorf("%w: bad checksum", ErrInvalidExtendedKey)
+	}
+
+	k := &ExtendedKey{depth: payload[4], childNumber: binary.BigEndian.Uint32(payload[9:13])}
+	copy(k.parentFP[:], payload[5:9])
+	copy(k.chainCode[:], payload[13:45])
+
+	keyData := payload[45:78]
+	switch {
+	case bytes.Equal(payload[:4], versionXprv[:]):
+		if keyData[0] != 0 || !validScalar(keyData[1:]) {
+			return nil, fmt.Errorf("%w: bad private key", ErrInvalidExtendedKey)
+		}
+		k.key, k.private = append([]byte(nil), keyData[1:]...), true
+	case bytes.Equal(payload[:4], versionXpub[:]):
+		if _, err := crypto.DecompressPubkey(keyData); err != nil {
+			return nil, fmt.Errorf("%w: bad public key", ErrInvalidExtendedKey)
+		}
+		k.key = append([]byte(nil), keyData...)
+	default:
+		return nil, fmt.Errorf("%w: unknown version", ErrInvalidExtendedKey)
+	}
+
+	if k.depth == 0 && (k.childNumber != 0 || k.parentFP != [4]byte{}) {
+		return nil, fmt.Errorf("%w: master key with parent", ErrInvalidExtendedKey)
+	}
+
+	return k, nil
+}
+
+func (k *ExtendedKey) IsPrivate() bool {
+	return k.private
+}
+
+func (k *ExtendedKey) Depth() uint8 {
+	return k.depth
+}
+
+// Child deriva o filho index; índices >= HardenedOffset exigem a chave privada
+func (k *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	hardened := index >= HardenedOffset
+	if hardened && !k.private {
+		return nil, ErrHardenedFromPublic
+	}
+
+	data := make([]byte, 0, 37)
+	if hardened {
+		data = append(data, 0x00)
+		data = append(data, k.key...)
+	} else {
+		data = append(data, k.publicKeyBytes()...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	il, ir := hmac512(k.chainCode[:], data)
+	if !validScalar(il) {
+		return nil, ErrInvalidChild
+	}
+
+	child := &ExtendedKey{
+		depth:       k.depth + 1,
+		childNumber: index,
+		private:     k.private,
+	}
+	copy(child.parentFP[:], hash160(k.publicKeyBytes())[:4])
+	copy(child.chainCode[:], ir)
+
+	curve := crypto.S256()
+	if k.private {
+		// k_i = parse256(IL) + k_par (mod n)
+		sum := new(big.Int).Add(new(big.Int).SetBytes(il), new(big.Int).SetBytes(k.key))
+		sum.Mod(sum, curve.Params().N)
+		if sum.Sign() == 0 {
+			return nil, ErrInvalidChild
+		}
+		child.key = sum.FillBytes(make([]byte, 32))
+		return child, nil
+	}
+
+	// K_i = point(parse256(IL)) + K_par
+	parent, err := crypto.DecompressPubkey(k.key)
+	if err != nil {
+		return nil, err
+	}
+	x, y := curve.ScalarBaseMult(il)
+	x, y = curve.Add(x, y, parent.X, parent.Y)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return nil, ErrInvalidChild
+	}
+	child.key = crypto.CompressPubkey(&ecdsa.PublicKey{Curve: curve, X: x, Y: y})
+	return child, nil
+}
+
+// Derive segue os índices a partir desta chave, ex.: Derive(44+HardenedOffset, 195+HardenedOffset)
+func (k *ExtendedKey) Derive(path ...uint32) (*ExtendedKey, error) {
+	current := k
+	for _, index := range path {
+		next, err := current.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// Neuter devolve a chave pública correspondente (xpub), que deriva os mesmos endereços
+// não-hardened sem conseguir assinar
+func (k *ExtendedKey) Neuter() *ExtendedKey {
+	if !k.private {
+		return k
+	}
+	return &ExtendedKey{
+		depth:       k.depth,
+		parentFP:    k.parentFP,
+		childNumber: k.childNumber,
+		chainCode:   k.chainCode,
+		key:         k.publicKeyBytes(),
+	}
+}
+
+func (k *ExtendedKey) PublicKey() (*ecdsa.PublicKey, error) {
+	return crypto.DecompressPubkey(k.publicKeyBytes())
+}
+
+func (k *ExtendedKey) PrivateKey() (*ecdsa.PrivateKey, error) {
+	if !k.private {
+		return nil, errors.New("extended key has no private part")
+	}
+	return crypto.ToECDSA(k.key)
+}
+
+// String serializa a chave no formato xprv/xpub em base58check
+func (k *ExtendedKey) String() string {
+	payload := make([]byte, 0, 82)
+	if k.private {
+		payload = append(payload, versionXprv[:]...)
+	} else {
+		payload = append(payload, versionXpub[:]...)
+	}
+	payload = append(payload, k.depth)
+	payload = append(payload, k.parentFP[:]...)
+	payload = binary.BigEndian.AppendUint32(payload, k.childNumber)
+	payload = append(payload, k.chainCode[:]...)
+	if k.private {
+		payload = append(payload, 0x00)
+	}
+	payload = append(payload, k.key...)
+	return base58.Encode(append(payload, checksum(payload)...))
+}
+
+func (k *ExtendedKey) publicKeyBytes() []byte {
+	if !k.private {
+		return k.key
+	}
+	x, y := crypto.S256().ScalarBaseMult(k.key)
+	return crypto.CompressPubkey(&ecdsa.PublicKey{Curve: crypto.S256(), X: x, Y: y})
+}
+
+func hmac512(key, data []byte) ([]byte, []byte) {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	return sum[:32], sum[32:]
+}
+
+// validScalar verifica 0 < k < n
+func validScalar(b []byte) bool {
+	v := new(big.Int).SetBytes(b)
+	return v.Sign() > 0 && v.Cmp(crypto.S256().Params().N) < 0
+}
+
+func hash160(b []byte) []byte {
+	sha := sha256.Sum256(b)
+	h := ripemd160.New()
+	h.Write(sha[:])
+	return h.Sum(nil)
+}
+
+func checksum(payload []byte) []byte {
+	h1 := sha256.Sum256(payload)
+	h2 := sha256.Sum256(h1[:])
+	return h2[:4]
+}
@@ -0,0 +1,160 @@
+package wallet_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/client"
+	"github.com/gabrielksneiva/go-financial-transactions/wallet"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fbsobreira/gotron-sdk/pkg/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tyler-smith/go-bip39"
+)
+
+const (
+	h = wallet.HardenedOffset
+
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+)
+
+// Vetor de teste 1 do BIP32
+func TestExtendedKey_BIP32Vector1(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := wallet.NewMaster(seed)
+	require.NoError(t, err)
+
+	cases := []struct {
+		path []uint32
+		xprv string
+		xpub string
+	}{
+		{
+			nil,
+			"xprv9s21ZrQH143K3QTDL4LXw2F7HEK3wJUD2nW2nRk4stbPy6cq3jPPqjiChkVvvNKmPGJxWUtg6LnF5kejMRNNU3TGtRBeJgk33yuGBxrMPHi",
+			"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8",
+		},
+		{
+			[]uint32{0 + h},
+			"xprv9uHRZZhk6KAJC1avXpDAp4MDc3sQKNxDiPvvkX8Br5ngLNv1TxvUxt4cV1rGL5hj6KCesnDYUhd7oWgT11eZG7XnxHrnYeSvkzY7d2bhkJ7",
+			"xpub68Gmy5EdvgibQVfPdqkBBCHxA5htiqg55crXYuXoQRKfDBFA1WEjWgP6LHhwBZeNK1VTsfTFUHCdrfp1bgwQ9xv5ski8PX9rL2dZXvgGDnw",
+		},
+		{
+			[]uint32{0 + h, 1},
+			"xprv9wTYmMFdV23N2TdNG573QoEsfRrWKQgWeibmLntzniatZvR9BmLnvSxqu53Kw1UmYPxLgboyZQaXwTCg8MSY3H2EU4pWcQDnRnrVA1xe8fs",
+			"xpub6ASuArnXKPbfEwhqN6e3mwBcDTgzisQN1wXN9BJcM47sSikHjJf3UFHKkNAWbWMiGj7Wf5uMash7SyYq527Hqck2AxYysAA7xmALppuCkwQ",
+		},
+		{
+			[]uint32{0 + h, 1, 2 + h},
+			"xprv9z4pot5VBttmtdRTWfWQmoH1taj2axGVzFqSb8C9xaxKymcFzXBDptWmT7FwuEzG3ryjH4ktypQSAewRiNMjANTtpgP4mLTj34bhnZX7UiM",
+			"xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5",
+		},
+	}
+
+	for _, tc := range cases {
+		key, err := master.Derive(tc.path...)
+		require.NoError(t, err)
+		assert.Equal(t, tc.xprv, key.String())
+		assert.Equal(t, tc.xpub, key.Neuter().String())
+
+		parsed, err := wallet.ParseExtendedKey(tc.xpub)
+		require.NoError(t, err)
+		assert.Equal(t, tc.xpub, parsed.String())
+	}
+}
+
+func TestExtendedKey_PublicDerivationMatchesPrivate(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := wallet.NewMaster(seed)
+	require.NoError(t, err)
+
+	account, err := master.Derive(0+h, 1, 2+h)
+	require.NoError(t, err)
+
+	fromPrivate, err := account.Derive(2, 1000000000)
+	require.NoError(t, err)
+	fromPublic, err := account.Neuter().Derive(2, 1000000000)
+	require.NoError(t, err)
+
+	assert.Equal(t, fromPrivate.Neuter().String(), fromPublic.String())
+	assert.Equal(t, "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy", fromPublic.String())
+
+	_, err = account.Neuter().Child(0 + h)
+	assert.ErrorIs(t, err, wallet.ErrHardenedFromPublic)
+}
+
+func TestParseExtendedKey_Invalid(t *testing.T) {
+	valid := "xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8"
+
+	for _, encoded := range []string{
+		"",
+		"not-a-key",
+		valid[:len(valid)-1] + "9", // checksum
+		"TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH",
+	} {
+		_, err := wallet.ParseExtendedKey(encoded)
+		assert.ErrorIs(t, err, wallet.ErrInvalidExtendedKey, encoded)
+	}
+}
+
+// Os endereços gerados só com a xpub da conta precisam bater com a derivação privada
+// m/44'/195'/0'/0/index usada pelas carteiras TRON (gotron-sdk/TronLink)
+func TestDeriver_MatchesTronDerivation(t *testing.T) {
+	master, err := wallet.NewMaster(bip39.NewSeed(testMnemonic, ""))
+	require.NoError(t, err)
+	account, err := wallet.AccountKey(master, 0)
+	require.NoError(t, err)
+
+	deriver, err := wallet.NewDeriver(account.Neuter().String())
+	require.NoError(t, err)
+
+	for index := 0; index < 5; index++ {
+		priv, _ := keys.FromMnemonicSeedAndPassphrase(testMnemonic, "", index)
+		expected := client.AddressFromPubKey(&priv.ToECDSA().PublicKey)
+
+		address, err := deriver.DeriveAddress(uint32(index))
+		require.NoError(t, err)
+		assert.Equal(t, expected, address, fmt.Sprintf("index %d", index))
+	}
+
+	address, err := deriver.DeriveAddress(0)
+	require.NoError(t, err)
+	assert.Equal(t, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", address)
+
+	key, err := account.Derive(wallet.ExternalChain, 0)
+	require.NoError(t, err)
+	priv, err := key.PrivateKey()
+	require.NoError(t, err)
+	assert.Equal(t, "b5a4cea271ff424d7c31dc12a3e43e401df7a40d7412a15750f3f0b6b5449a28", hex.EncodeToString(crypto.FromECDSA(priv)))
+}
+
+func TestDeriver_AcceptsXprvAndRejectsOtherDepths(t *testing.T) {
+	master, err := wallet.NewMaster(bip39.NewSeed(testMnemonic, ""))
+	require.NoError(t, err)
+	account, err := wallet.AccountKey(master, 0)
+	require.NoError(t, err)
+
+	fromXprv, err := wallet.NewDeriver(account.String())
+	require.NoError(t, err)
+	address, err := fromXprv.DeriveAddress(0)
+	require.NoError(t, err)
+	assert.Equal(t, "TUEZSdKsoDHQMeZwihtdoBiN46zxhGWYdH", address)
+
+	_, err = wallet.NewDeriver(master.Neuter().String())
+	assert.ErrorIs(t, err, wallet.ErrNotAccountKey)
+
+	_, err = fromXprv.DeriveAddress(h)
+	assert.ErrorIs(t, err, wallet.ErrIndexOutOfRange)
+}
+
+func TestIndexForUser(t *testing.T) {
+	index, err := wallet.IndexForUser(42)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(42), index)
+
+	_, err = wallet.IndexForUser(uint(h))
+	assert.ErrorIs(t, err, wallet.ErrIndexOutOfRange)
+}
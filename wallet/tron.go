@@ -0,0 +1,82 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gabrielksneiva/go-financial-transactions/client"
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const (
+	Purpose      uint32 = 44
+	CoinTypeTron uint32 = 195
+	// ExternalChain é a cadeia de endereços de recebimento (o "0" em m/44'/195'/account'/0/index)
+	ExternalChain uint32 = 0
+)
+
+var ErrNotAccountKey = errors.New("deposit key must be an account-level key (m/44'/195'/account')")
+
+// ErrIndexOutOfRange indica um usuário sem índice não-hardened disponível
+var ErrIndexOutOfRange = errors.New("user ID out of range for deposit addresses")
+
+// AccountKey deriva m/44'/195'/account' a partir da chave mestra. Precisa da xprv mestra,
+// porque os três níveis são hardened; o resultado pode ser exportado com Neuter().String().
+func AccountKey(master *ExtendedKey, account uint32) (*ExtendedKey, error) {
+	if master.Depth() != 0 {
+		return nil, fmt.Errorf("expected a master key, got depth %d", master.Depth())
+	}
+	return master.Derive(Purpose+HardenedOffset, CoinTypeTron+HardenedOffset, account+HardenedOffset)
+}
+
+// IndexForUser mapeia o usuário para o índice do endereço: o próprio ID, limitado aos
+// índices não-hardened para que a xpub consiga derivá-lo
+func IndexForUser(userID uint) (uint32, error) {
+	if uint64(userID) >= uint64(HardenedOffset) {
+		return 0, ErrIndexOutOfRange
+	}
+	return uint32(userID), nil
+}
+
+// Deriver gera endereços de depósito TRON a partir da xpub da conta, sem chave privada.
+// Implementa domain.AddressDeriver.
+type Deriver struct {
+	external *ExtendedKey
+}
+
+var _ d.AddressDeriver = &Deriver{}
+
+// NewDeriver aceita a chave da conta (m/44'/195'/account'); uma xprv é convertida em xpub
+// para que a chave privada não fique em memória no servidor da API
+func NewDeriver(accountKey string) (*Deriver, error) {
+	key, err := ParseExtendedKey(accountKey)
+	if err != nil {
+		return nil, err
+	}
+	if key.Depth() != 3 {
+		return nil, fmt.Errorf("%w: got depth %d", ErrNotAccountKey, key.Depth())
+	}
+
+	external, err := key.Neuter().Child(ExternalChain)
+	if err != nil {
+		return nil, err
+	}
+	return &Deriver{external: external}, nil
+}
+
+// DeriveAddress devolve o endereço de m/44'/195'/account'/0/index
+func (w *Deriver) DeriveAddress(index uint32) (string, error) {
+	if index >= HardenedOffset {
+		return "", ErrIndexOutOfRange
+	}
+
+	child, err := w.external.Child(index)
+	if err != nil {
+		return "", err
+	}
+	pub, err := child.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	return client.AddressFromPubKey(pub), nil
+}
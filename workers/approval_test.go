@@ -0,0 +1,102 @@
+package workers_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+)
+
+const adminID = uint(99)
+
+// heldWithdrawal credita 10 TRX ao usuário 1 e pede um saque de 4 TRX que fica aguardando aprovação
+func heldWithdrawal(t *testing.T, db *gorm.DB, repo *repositories.GormRepository, chains *domain.ChainRegistry) domain.Transaction {
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "maker@example.com", WalletAddress: "TWallet"}).Error)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+
+	withdraw := domain.Transaction{ID: "tx-large", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw}
+	require.NoError(t, repo.HoldForApproval(withdraw))
+	return withdraw
+}
+
+func TestApproval_ApprovedWithdrawalUsesHeldFunds(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	blockchainMock := new(mocks.BlockchainClient)
+	chains := tronChains(blockchainMock)
+
+	withdraw := heldWithdrawal(t, db, repo, chains)
+	assert.Equal(t, domain.StatusAwaitingApproval, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Zero(t, countRows(t, db, &domain.OutboxMessage{}, "transaction_id = ?", withdraw.ID))
+
+	// sem saldo para um segundo saque enquanto o primeiro está retido
+	err := repo.HoldForApproval(domain.Transaction{ID: "tx-large-2", UserID: 1, Amount: domain.NewMoney(7_000000, "TRX"), Type: workers.TypeWithdraw})
+	assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+
+	_, err = repo.ApproveWithdrawal(withdraw.ID, 1, "self")
+	assert.ErrorIs(t, err, domain.ErrSelfApproval)
+
+	approved, err := repo.ApproveWithdrawal(withdraw.ID, adminID, "KYC ok")
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusApproved, approved.Status)
+
+	_, err = repo.RejectWithdrawal(withdraw.ID, adminID, "too late")
+	assert.ErrorIs(t, err, domain.ErrStatusConflict)
+
+	// o worker recebe a mensagem do outbox e envia sem debitar o usuário de novo
+	var msg domain.OutboxMessage
+	require.NoError(t, db.First(&msg, "transaction_id = ?", withdraw.ID).Error)
+	var published domain.Transaction
+	require.NoError(t, json.Unmarshal(msg.Payload, &published))
+
+	blockchainMock.On("ValidateAddress", "TWallet").Return(nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, withdraw.ID).Return(&domain.BlockchainTxResult{TxID: "chain-large"}, nil)
+	require.NoError(t, workers.CallProcessTransaction(published, 1, db, chains, repo))
+
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), balanceOf(t, db, 1))
+	assertLedgerOK(t, db)
+
+	decisions, err := repo.ListApprovalDecisions(withdraw.ID)
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, domain.ApprovalDecisionApproved, decisions[0].Decision)
+	assert.Equal(t, uint(1), decisions[0].RequesterID)
+	assert.Equal(t, adminID, decisions[0].ApproverID)
+	assert.Equal(t, "KYC ok", decisions[0].Reason)
+}
+
+func TestApproval_RejectionReleasesFunds(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chains := tronChains(new(mocks.BlockchainClient))
+
+	withdraw := heldWithdrawal(t, db, repo, chains)
+
+	rejected, err := repo.RejectWithdrawal(withdraw.ID, adminID, "suspicious destination")
+	require.NoError(t, err)
+	assert.Equal(t, domain.StatusRejected, rejected.Status)
+	assert.Equal(t, domain.NewMoney(10_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Zero(t, countRows(t, db, &domain.OutboxMessage{}, "transaction_id = ?", withdraw.ID))
+	assertLedgerOK(t, db)
+
+	_, err = repo.ApproveWithdrawal("missing", adminID, "")
+	assert.ErrorIs(t, err, domain.ErrTransactionNotFound)
+
+	history, err := repo.GetStatusHistory(withdraw.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.StatusRejected, history[1].To)
+	assert.Contains(t, history[1].Reason, "suspicious destination")
+}
@@ -0,0 +1,252 @@
+package workers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+)
+
+// stalePendingAfter é quanto um saque pode ficar PENDING antes de a varredura concluir que o
+// worker caiu (ou falhou de forma transitória) entre gravar o débito e enviar
+const stalePendingAfter = 2 * time.Minute
+
+// ConfirmationTracker acompanha os saques BROADCAST até terem confirmações suficientes,
+// consultando o driver da rede de cada ativo.
+// O estado fica todo no banco (status e tx_hash), então após um restart o acompanhamento
+// continua a partir das transações que ainda estão em BROADCAST.
+type ConfirmationTracker struct {
+	db            *gorm.DB
+	chains        *d.ChainRegistry
+	repo          d.TransactionRepository
+	confirmations map[string]int64
+	dropTimeout   time.Duration
+	batchSize     int
+}
+
+// NewConfirmationTracker recebe a profundidade exigida por rede (d.ChainTron, d.ChainEthereum);
+// uma rede sem valor no mapa exige 1 confirmação
+func NewConfirmationTracker(db *gorm.DB, chains *d.ChainRegistry, repo d.TransactionRepository, confirmations map[string]int, dropTimeout time.Duration) *ConfirmationTracker {
+	depths := make(map[string]int64, len(confirmations))
+	for chain, depth := range confirmations {
+		depths[chain] = int64(max(depth, 1))
+	}
+	return &ConfirmationTracker{
+		db:            db,
+		chains:        chains,
+		repo:          repo,
+		confirmations: depths,
+		dropTimeout:   dropTimeout,
+		batchSize:     100,
+	}
+}
+
+func (t *ConfirmationTracker) depth(chain string) int64 {
+	if depth, ok := t.confirmations[chain]; ok {
+		return depth
+	}
+	return 1
+}
+
+// Poll retoma os saques parados em PENDING e consulta o nó uma vez para cada saque em
+// BROADCAST, retornando quantos destes foram resolvidos (CONFIRMED ou FAILED com estorno).
+func (t *ConfirmationTracker) Poll() (int, error) {
+	if err := t.resumePending(); err != nil {
+		log.Printf("⚠️ Confirmações: erro ao listar saques PENDING: %v", err)
+	}
+
+	pending, err := t.repo.ListTransactionsByStatus(d.StatusBroadcast, t.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	// a altura de cada rede é lida uma vez por rodada
+	latest := map[string]int64{}
+
+	resolved := 0
+	for _, tx := range pending {
+		done, err := t.check(tx, latest)
+		if err != nil {
+			log.Printf("⚠️ Confirmações: erro ao verificar transação %s: %v", tx.ID, err)
+			continue
+		}
+		if done {
+			resolved++
+		}
+	}
+
+	return resolved, nil
+}
+
+// resumePending envia de novo os saques que ficaram PENDING depois do débito, como quando o
+// processo cai entre o commit do worker e o envio. O compare-and-set para SENDING em
+// handleWithdrawal impede que um saque ainda em andamento no worker seja enviado duas vezes.
+// Um saque PENDING que já tem hash foi à rede e também só é apontado.
+// Um saque parado em SENDING caiu durante o envio, sem hash gravado: ele pode ter chegado à
+// rede, então não é reenviado nem estornado, e sim apontado para verificação manual.
+func (t *ConfirmationTracker) resumePending() error {
+	stale, err := t.repo.ListTransactionsByStatus(d.StatusPending, t.batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range stale {
+		if tx.Type != TypeWithdraw || time.Since(tx.UpdatedAt) < stalePendingAfter {
+			continue
+		}
+		if tx.TxHash != "" {
+			// um hash gravado indica que o saque já foi à rede; reenviar pagaria duas vezes
+			log.Printf("🚨 Confirmações: saque %s parado em PENDING com hash %s, verificação manual necessária", tx.ID, tx.TxHash)
+			continue
+		}
+		log.Printf("🔄 Confirmações: saque %s parado em PENDING desde %s, retomando o envio", tx.ID, tx.UpdatedAt.Format(time.RFC3339))
+		handleWithdrawal(tx, 0, t.db, t.chains, t.repo)
+	}
+
+	sending, err := t.repo.ListTransactionsByStatus(d.StatusSending, t.batchSize)
+	if err != nil {
+		return err
+	}
+	for _, tx := range sending {
+		if time.Since(tx.UpdatedAt) < stalePendingAfter {
+			continue
+		}
+		log.Printf("🚨 Confirmações: saque %s parado em SENDING desde %s, envio com resultado desconhecido, verificação manual necessária", tx.ID, tx.UpdatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func (t *ConfirmationTracker) check(tx d.Transaction, latest map[string]int64) (bool, error) {
+	if tx.TxHash == "" {
+		// sem hash não há como saber se o envio entrou na rede; estornar arriscaria pagar duas vezes
+		log.Printf("⚠️ Confirmações: transação %s em BROADCAST sem hash, verificação manual necessária", tx.ID)
+		return false, nil
+	}
+
+	asset, chain, err := t.chains.ForCurrency(tx.Amount.Currency)
+	if err != nil {
+		return false, err
+	}
+
+	info, err := chain.GetTransactionInfo(tx.TxHash)
+	if errors.Is(err, d.ErrBlockchainTxPending) {
+		if time.Since(tx.UpdatedAt) >= t.dropTimeout {
+			log.Printf("⏳ Confirmações: transação %s ainda pendente na rede após %s", tx.ID, t.dropTimeout)
+		}
+		return false, nil
+	}
+	if errors.Is(err, d.ErrBlockchainTxNotFound) {
+		return t.checkMissing(tx, chain)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !info.Success {
+		return t.refund(tx, "failed on-chain: "+info.Reason)
+	}
+
+	tip, ok := latest[asset.Chain]
+	if !ok {
+		if tip, err = chain.GetLatestBlockNumber(); err != nil {
+			return false, err
+		}
+		latest[asset.Chain] = tip
+	}
+
+	depth := tip - info.BlockNumber
+	if depth < t.depth(asset.Chain) {
+		return false, nil
+	}
+
+	// O compare-and-set do status garante uma única liquidação mesmo com dois rastreadores
+	err = t.db.Transaction(func(txDB *gorm.DB) error {
+		reason := fmt.Sprintf("%d confirmations (block %d)", depth, info.BlockNumber)
+		if err := repositories.TransitionStatus(txDB, tx.ID, d.StatusBroadcast, d.StatusConfirmed, reason); err != nil {
+			return err
+		}
+		return ledger.SettleWithdraw(txDB, tx)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	log.Printf("✅ Confirmações: transação %s confirmada com %d blocos", tx.ID, depth)
+	return true, nil
+}
+
+// checkMissing trata o envio que o nó não conhece. Na TRON a transação expira, então depois
+// de dropTimeout ela não entra mais em bloco e o valor é estornado. Em redes EVM ela não
+// expira: só é estornada quando o nonce dela já foi usado por outra transação da carteira;
+// fora isso fica em BROADCAST e, passado dropTimeout, pede verificação manual.
+func (t *ConfirmationTracker) checkMissing(tx d.Transaction, chain d.BlockchainClient) (bool, error) {
+	nonces, ok := chain.(d.NonceTracker)
+	if !ok {
+		if time.Since(tx.UpdatedAt) < t.dropTimeout {
+			return false, nil
+		}
+		return t.refund(tx, fmt.Sprintf("dropped: %s not included after %s", tx.TxHash, t.dropTimeout))
+	}
+
+	if tx.Nonce == nil || tx.FromAddress == "" {
+		t.requestReview(tx, "sem nonce registrado")
+		return false, nil
+	}
+
+	consumed, err := nonces.NonceConsumed(tx.FromAddress, *tx.Nonce)
+	if err != nil {
+		return false, err
+	}
+	if !consumed {
+		t.requestReview(tx, fmt.Sprintf("nonce %d ainda não usado", *tx.Nonce))
+		return false, nil
+	}
+
+	// o nonce pode ter sido usado pela própria transação entre as duas consultas
+	if _, err := chain.GetTransactionInfo(tx.TxHash); !errors.Is(err, d.ErrBlockchainTxNotFound) {
+		return false, err
+	}
+	return t.refund(tx, fmt.Sprintf("replaced: nonce %d of %s used by another transaction", *tx.Nonce, tx.FromAddress))
+}
+
+func (t *ConfirmationTracker) requestReview(tx d.Transaction, detail string) {
+	if time.Since(tx.UpdatedAt) < t.dropTimeout {
+		return
+	}
+	log.Printf("⚠️ Confirmações: transação %s (%s) sumiu do nó há mais de %s (%s), verificação manual necessária", tx.ID, tx.TxHash, t.dropTimeout, detail)
+}
+
+func (t *ConfirmationTracker) refund(tx d.Transaction, reason string) (bool, error) {
+	if err := failWithdrawal(t.db, tx, d.StatusBroadcast, reason); err != nil {
+		return false, err
+	}
+	log.Printf("🔙 Confirmações: transação %s não será liquidada (%s), valor estornado", tx.ID, reason)
+	return true, nil
+}
+
+func (t *ConfirmationTracker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Rastreador de confirmações encerrado")
+			return
+		case <-ticker.C:
+			if _, err := t.Poll(); err != nil {
+				log.Printf("❌ Rastreador de confirmações: %v", err)
+			}
+		}
+	}
+}
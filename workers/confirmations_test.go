@@ -0,0 +1,347 @@
+package workers_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// fakeBlockchain simula o nó de uma rede: o teste decide a altura da cadeia e em que bloco
+// (e com que resultado) cada transação enviada foi incluída.
+type fakeBlockchain struct {
+	mu     sync.Mutex
+	latest int64
+	infos  map[string]*domain.BlockchainTxInfo
+}
+
+func newFakeBlockchain() *fakeBlockchain {
+	return &fakeBlockchain{infos: map[string]*domain.BlockchainTxInfo{}}
+}
+
+func (f *fakeBlockchain) ValidateAddress(address string) error {
+	return nil
+}
+
+func (f *fakeBlockchain) EstimateFee(tx domain.BlockchainTransaction, asset domain.Asset) (domain.Money, error) {
+	return domain.Money{}, nil
+}
+
+func (f *fakeBlockchain) Send(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	return &domain.BlockchainTxResult{TxID: "hash-" + transactionID, ToAddress: tx.ToAddress}, nil
+}
+
+func (f *fakeBlockchain) GetTransactionInfo(txID string) (*domain.BlockchainTxInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	info, ok := f.infos[txID]
+	if !ok {
+		return nil, domain.ErrBlockchainTxNotFound
+	}
+	return info, nil
+}
+
+func (f *fakeBlockchain) GetLatestBlockNumber() (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.latest, nil
+}
+
+// fakeEVM acrescenta o nonce por conta: os envios recebem nonces em sequência e o teste
+// decide até onde eles já foram usados em bloco
+type fakeEVM struct {
+	*fakeBlockchain
+	next      uint64
+	confirmed uint64
+}
+
+func (f *fakeEVM) Send(tx domain.BlockchainTransaction, asset domain.Asset, transactionID string) (*domain.BlockchainTxResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	nonce := f.next
+	f.next++
+	return &domain.BlockchainTxResult{TxID: "hash-" + transactionID, FromAddress: "0xHot", ToAddress: tx.ToAddress, Nonce: &nonce}, nil
+}
+
+func (f *fakeEVM) NonceConsumed(from string, nonce uint64) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.confirmed > nonce, nil
+}
+
+func (f *fakeBlockchain) include(txID string, block int64, success bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.infos["hash-"+txID] = &domain.BlockchainTxInfo{TxID: "hash-" + txID, BlockNumber: block, Success: success, Reason: "REVERT"}
+}
+
+func (f *fakeBlockchain) mine(blocks int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.latest += blocks
+}
+
+// broadcastWithdrawal deposita 100 TRX e processa um saque de 30 TRX até o BROADCAST
+func broadcastWithdrawal(t *testing.T, db *gorm.DB, chain *fakeBlockchain, repo domain.TransactionRepository) domain.Transaction {
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "chain@example.com", WalletAddress: "TWallet"}).Error)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(100_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, tronChains(chain), repo))
+
+	withdraw := domain.Transaction{ID: "tx-onchain", UserID: 1, Amount: domain.NewMoney(30_000000, "TRX"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, tronChains(chain), repo))
+
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, withdraw.ID))
+	return withdraw
+}
+
+// depths são as profundidades de confirmação dos testes, as mesmas dos padrões da configuração
+var depths = map[string]int{domain.ChainTron: 19, domain.ChainEthereum: 12}
+
+func statusOf(t *testing.T, db *gorm.DB, txID string) domain.TransactionStatus {
+	var tx domain.Transaction
+	assert.NoError(t, db.First(&tx, "id = ?", txID).Error)
+	return tx.Status
+}
+
+func assertLedgerOK(t *testing.T, db *gorm.DB) {
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestConfirmationTracker_ConfirmsAfterEnoughBlocks(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chain := newFakeBlockchain()
+	withdraw := broadcastWithdrawal(t, db, chain, repo)
+
+	tracker := workers.NewConfirmationTracker(db, tronChains(chain), repo, depths, time.Minute)
+
+	// ainda não incluída em bloco
+	resolved, err := tracker.Poll()
+	assert.NoError(t, err)
+	assert.Zero(t, resolved)
+
+	chain.include(withdraw.ID, 100, true)
+	chain.mine(110)
+	resolved, err = tracker.Poll()
+	assert.NoError(t, err)
+	assert.Zero(t, resolved)
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, withdraw.ID))
+
+	// um novo rastreador (como após um restart) retoma a partir do banco
+	chain.mine(9)
+	resolved, err = workers.NewConfirmationTracker(db, tronChains(chain), repo, depths, time.Minute).Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, domain.StatusConfirmed, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(70_000000, "TRX"), balanceOf(t, db, 1))
+	assertLedgerOK(t, db)
+
+	// já resolvida: não é liquidada de novo
+	resolved, err = tracker.Poll()
+	assert.NoError(t, err)
+	assert.Zero(t, resolved)
+	assert.Equal(t, int64(2), countRows(t, db, &domain.JournalEntry{}, "transaction_id = ?", withdraw.ID))
+}
+
+func TestConfirmationTracker_FailedOnChainIsRefunded(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chain := newFakeBlockchain()
+	withdraw := broadcastWithdrawal(t, db, chain, repo)
+
+	chain.include(withdraw.ID, 5, false)
+	chain.mine(6)
+
+	resolved, err := workers.NewConfirmationTracker(db, tronChains(chain), repo, depths, time.Minute).Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(100_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, int64(1), countRows(t, db, &domain.Transaction{}, "type = ?", workers.TypeRefund))
+	assertLedgerOK(t, db)
+
+	history, err := repo.GetStatusHistory(withdraw.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusFailed, history[len(history)-2].To)
+	assert.Contains(t, history[len(history)-2].Reason, "REVERT")
+}
+
+func TestConfirmationTracker_DroppedTransactionIsRefundedAfterTimeout(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chain := newFakeBlockchain()
+	withdraw := broadcastWithdrawal(t, db, chain, repo)
+
+	tracker := workers.NewConfirmationTracker(db, tronChains(chain), repo, depths, time.Minute)
+	resolved, err := tracker.Poll()
+	assert.NoError(t, err)
+	assert.Zero(t, resolved)
+
+	assert.NoError(t, db.Model(&domain.Transaction{}).Where("id = ?", withdraw.ID).
+		UpdateColumn("updated_at", time.Now().Add(-2*time.Minute)).Error)
+
+	resolved, err = tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(100_000000, "TRX"), balanceOf(t, db, 1))
+	assertLedgerOK(t, db)
+}
+
+func TestConfirmationTracker_UsesDriverOfEachChain(t *testing.T) {
+	domain.RegisterAsset(domain.Asset{Symbol: "ETH", Decimals: 9, OnchainDecimals: 18, Chain: domain.ChainEthereum})
+
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	tron, eth := newFakeBlockchain(), newFakeBlockchain()
+	chains := tronChains(tron)
+	chains.Register(domain.ChainEthereum, eth)
+
+	tronWithdraw := broadcastWithdrawal(t, db, tron, repo)
+	ethDeposit := domain.Transaction{ID: "tx-eth-funding", UserID: 1, Amount: domain.NewMoney(5_000000000, "ETH"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(ethDeposit, 1, db, chains, repo))
+	ethWithdraw := domain.Transaction{ID: "tx-eth-onchain", UserID: 1, Amount: domain.NewMoney(2_000000000, "ETH"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(ethWithdraw, 1, db, chains, repo))
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, ethWithdraw.ID))
+
+	// cada rede tem a sua altura: a TRON já tem 19 confirmações, a Ethereum ainda não
+	tron.include(tronWithdraw.ID, 100, true)
+	tron.mine(119)
+	eth.include(ethWithdraw.ID, 10, true)
+	eth.mine(12)
+
+	tracker := workers.NewConfirmationTracker(db, chains, repo, depths, time.Minute)
+	resolved, err := tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, domain.StatusConfirmed, statusOf(t, db, tronWithdraw.ID))
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, ethWithdraw.ID))
+
+	// 15 blocos bastam na Ethereum, que exige 12, mesmo abaixo dos 19 da TRON
+	eth.mine(13)
+	resolved, err = tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, domain.StatusConfirmed, statusOf(t, db, ethWithdraw.ID))
+
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ? AND currency = ?", 1, "ETH").Error)
+	assert.Equal(t, domain.NewMoney(3_000000000, "ETH"), balance.Amount())
+	assertLedgerOK(t, db)
+}
+
+func TestConfirmationTracker_MissingEVMTransactionIsRefundedOnlyWhenNonceIsReused(t *testing.T) {
+	domain.RegisterAsset(domain.Asset{Symbol: "ETH", Decimals: 9, OnchainDecimals: 18, Chain: domain.ChainEthereum})
+
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	eth := &fakeEVM{fakeBlockchain: newFakeBlockchain()}
+	chains := domain.NewChainRegistry()
+	chains.Register(domain.ChainEthereum, eth)
+
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "evm@example.com", WalletAddress: "0xWallet"}).Error)
+	deposit := domain.Transaction{ID: "tx-eth-funding", UserID: 1, Amount: domain.NewMoney(5_000000000, "ETH"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-eth-onchain", UserID: 1, Amount: domain.NewMoney(2_000000000, "ETH"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+
+	var sent domain.Transaction
+	assert.NoError(t, db.First(&sent, "id = ?", withdraw.ID).Error)
+	assert.Equal(t, domain.StatusBroadcast, sent.Status)
+	assert.Equal(t, "0xHot", sent.FromAddress)
+	if assert.NotNil(t, sent.Nonce) {
+		assert.Equal(t, uint64(0), *sent.Nonce)
+	}
+
+	assert.NoError(t, db.Model(&domain.Transaction{}).Where("id = ?", withdraw.ID).
+		UpdateColumn("updated_at", time.Now().Add(-time.Hour)).Error)
+
+	// sumiu do nó, mas o nonce ainda está livre: a transação pode voltar e ser minerada
+	tracker := workers.NewConfirmationTracker(db, chains, repo, depths, time.Minute)
+	resolved, err := tracker.Poll()
+	assert.NoError(t, err)
+	assert.Zero(t, resolved)
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, withdraw.ID))
+
+	// outra transação da carteira usou o nonce: esta nunca será minerada
+	eth.confirmed = 1
+	resolved, err = tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, withdraw.ID))
+
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ? AND currency = ?", 1, "ETH").Error)
+	assert.Equal(t, domain.NewMoney(5_000000000, "ETH"), balance.Amount())
+	assertLedgerOK(t, db)
+}
+
+func TestConfirmationTracker_ResumesStalePendingWithdrawal(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "stale@example.com", WalletAddress: "TWallet"}).Error)
+
+	// a primeira validação falha por indisponibilidade do nó e o saque fica PENDING com o débito feito
+	blockchainMock := new(mocks.BlockchainClient)
+	blockchainMock.On("ValidateAddress", "TWallet").Return(errors.New("node unavailable")).Once()
+	blockchainMock.On("ValidateAddress", "TWallet").Return(nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, "tx-stale").Return(&domain.BlockchainTxResult{TxID: "hash-stale"}, nil)
+	blockchainMock.On("GetLatestBlockNumber").Return(int64(0), nil)
+	blockchainMock.On("GetTransactionInfo", "hash-stale").Return(nil, domain.ErrBlockchainTxNotFound)
+	chains := tronChains(blockchainMock)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-stale", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+	assert.Equal(t, domain.StatusPending, statusOf(t, db, withdraw.ID))
+
+	tracker := workers.NewConfirmationTracker(db, chains, repo, depths, time.Minute)
+
+	// recente demais: o worker pode ainda estar enviando
+	_, err := tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusPending, statusOf(t, db, withdraw.ID))
+	blockchainMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+
+	assert.NoError(t, db.Model(&domain.Transaction{}).Where("id = ?", withdraw.ID).
+		UpdateColumn("updated_at", time.Now().Add(-time.Hour)).Error)
+	_, err = tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, withdraw.ID))
+	blockchainMock.AssertNumberOfCalls(t, "Send", 1)
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), balanceOf(t, db, 1))
+}
+
+func TestConfirmationTracker_StalePendingWithHashIsNotResent(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+
+	// saque antigo, enviado antes dos estados SENDING/BROADCAST: tem hash, mas segue PENDING
+	legacy := domain.Transaction{ID: "tx-legacy", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw, Status: domain.StatusPending, TxHash: "hash-legacy"}
+	assert.NoError(t, db.Create(&legacy).Error)
+	assert.NoError(t, db.Model(&domain.Transaction{}).Where("id = ?", legacy.ID).
+		UpdateColumn("updated_at", time.Now().Add(-time.Hour)).Error)
+
+	blockchainMock := new(mocks.BlockchainClient)
+	blockchainMock.On("GetLatestBlockNumber").Return(int64(0), nil)
+	tracker := workers.NewConfirmationTracker(db, tronChains(blockchainMock), repo, depths, time.Minute)
+
+	_, err := tracker.Poll()
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusPending, statusOf(t, db, legacy.ID))
+	blockchainMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+}
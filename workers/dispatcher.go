@@ -0,0 +1,43 @@
+package workers
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"strconv"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+// LaneFor escolhe o worker responsável pelo usuário. O mesmo usuário cai sempre
+// no mesmo worker, então suas transações são processadas na ordem em que chegaram.
+func LaneFor(userID uint, lanes int) int {
+	if lanes <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	h.Write([]byte(strconv.FormatUint(uint64(userID), 10)))
+	return int(h.Sum32() % uint32(lanes))
+}
+
+// Dispatcher lê os jobs do consumer e encaminha cada um para a fila do worker do usuário.
+// Usuários diferentes são processados em paralelo por workers diferentes.
+func Dispatcher(ctx context.Context, jobs <-chan d.TransactionJob, lanes []chan d.TransactionJob) {
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("🛑 Dispatcher encerrado")
+			return
+
+		case job := <-jobs:
+			lane := lanes[LaneFor(job.Transaction.UserID, len(lanes))]
+
+			select {
+			case lane <- job:
+			case <-ctx.Done():
+				log.Println("🛑 Dispatcher encerrado")
+				return
+			}
+		}
+	}
+}
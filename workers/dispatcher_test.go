@@ -0,0 +1,73 @@
+package workers_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaneFor_IsStablePerUser(t *testing.T) {
+	used := map[int]bool{}
+	for userID := uint(1); userID <= 100; userID++ {
+		lane := workers.LaneFor(userID, 4)
+		assert.Equal(t, lane, workers.LaneFor(userID, 4))
+		assert.GreaterOrEqual(t, lane, 0)
+		assert.Less(t, lane, 4)
+		used[lane] = true
+	}
+
+	// usuários diferentes se espalham entre os workers
+	assert.Len(t, used, 4)
+	assert.Equal(t, 0, workers.LaneFor(42, 1))
+}
+
+func TestDispatcher_PreservesPerUserOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	jobs := make(chan domain.TransactionJob)
+	lanes := make([]chan domain.TransactionJob, 3)
+	for i := range lanes {
+		lanes[i] = make(chan domain.TransactionJob, 100)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		workers.Dispatcher(ctx, jobs, lanes)
+	}()
+
+	// transações intercaladas de vários usuários, cada uma com offset crescente
+	for offset := int64(0); offset < 60; offset++ {
+		userID := uint(offset%5) + 1
+		jobs <- domain.TransactionJob{Transaction: domain.Transaction{UserID: userID}, Offset: offset}
+	}
+
+	assert.Eventually(t, func() bool {
+		total := 0
+		for _, lane := range lanes {
+			total += len(lane)
+		}
+		return total == 60
+	}, time.Second, 10*time.Millisecond)
+	cancel()
+	<-done
+
+	for i, lane := range lanes {
+		close(lane)
+		last := map[uint]int64{}
+		for job := range lane {
+			userID := job.Transaction.UserID
+			assert.Equal(t, i, workers.LaneFor(userID, len(lanes)), "usuário %d no worker errado", userID)
+			if prev, ok := last[userID]; ok {
+				assert.Greater(t, job.Offset, prev, "usuário %d fora de ordem", userID)
+			}
+			last[userID] = job.Offset
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package workers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+)
+
+func feesCollected(t *testing.T, db *gorm.DB) int64 {
+	var account domain.LedgerAccount
+	err := db.Where("code = ?", ledger.SystemAccount(ledger.AccountFees, "TRX")).First(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0
+	}
+	require.NoError(t, err)
+	return account.Balance.Units
+}
+
+func feeLines(t *testing.T, db *gorm.DB, txID string) []domain.Transaction {
+	var lines []domain.Transaction
+	require.NoError(t, db.Where("type = ? AND linked_transaction_id = ?", domain.FeeTransaction, txID).Find(&lines).Error)
+	return lines
+}
+
+func TestFee_WithdrawalChargesHeldFeeAndRefundsItOnFailure(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	blockchainMock := new(mocks.BlockchainClient)
+	chains := tronChains(blockchainMock)
+
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "fee@example.com", WalletAddress: "TWallet"}).Error)
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+
+	withdrawal := func(id string) domain.Transaction {
+		return domain.Transaction{ID: id, UserID: 1, Amount: domain.NewMoney(3_000000, "TRX"), Fee: 1_100000, Type: workers.TypeWithdraw, Status: domain.StatusReceived}
+	}
+
+	// o valor e a tarifa são reservados juntos
+	require.NoError(t, repo.SaveWithdrawalWithHold(withdrawal("tx-fee-sent")))
+	assert.Equal(t, domain.NewMoney(5_900000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(4_100000, "TRX"), heldOf(t, db, 1))
+
+	// só o valor vai on-chain; a tarifa vai para a conta de tarifas com uma linha própria no extrato
+	blockchainMock.On("ValidateAddress", "TWallet").Return(nil)
+	blockchainMock.On("Send", mock.MatchedBy(func(out domain.BlockchainTransaction) bool {
+		return out.Amount == 3_000000
+	}), mock.Anything, "tx-fee-sent").Return(&domain.BlockchainTxResult{TxID: "chain-fee"}, nil)
+	require.NoError(t, workers.CallProcessTransaction(publishedWithdrawal(t, db, "tx-fee-sent"), 1, db, chains, repo))
+
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, "tx-fee-sent"))
+	assert.True(t, heldOf(t, db, 1).IsZero())
+	assert.Equal(t, int64(1_100000), feesCollected(t, db))
+	lines := feeLines(t, db, "tx-fee-sent")
+	require.Len(t, lines, 1)
+	assert.Equal(t, domain.NewMoney(1_100000, "TRX"), lines[0].Amount)
+	assert.Equal(t, domain.StatusCompleted, lines[0].Status)
+	assertLedgerOK(t, db)
+
+	// um envio que falha devolve o valor e a tarifa
+	require.NoError(t, repo.SaveWithdrawalWithHold(withdrawal("tx-fee-failed")))
+	blockchainMock.On("Send", mock.Anything, mock.Anything, "tx-fee-failed").Return(nil, errors.New("node unavailable"))
+	require.NoError(t, workers.CallProcessTransaction(publishedWithdrawal(t, db, "tx-fee-failed"), 1, db, chains, repo))
+
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, "tx-fee-failed"))
+	assert.Equal(t, domain.NewMoney(5_900000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, int64(1_100000), feesCollected(t, db))
+	assertLedgerOK(t, db)
+}
+
+func TestFee_TransferChargesSenderOnly(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chains := tronChains(new(mocks.BlockchainClient))
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+
+	out := transfer("tx-transfer-fee", 1, 2, 4_000000)
+	out.Fee = 500000
+	require.NoError(t, repo.SaveTransactionWithOutbox(out))
+	require.NoError(t, workers.CallProcessTransaction(out, 1, db, chains, repo))
+
+	assert.Equal(t, domain.NewMoney(5_500000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(4_000000, "TRX"), balanceOf(t, db, 2))
+	assert.Equal(t, int64(500000), feesCollected(t, db))
+	assert.Len(t, feeLines(t, db, out.ID), 1)
+	assertLedgerOK(t, db)
+
+	// sem saldo para o valor mais a tarifa, nada é movido nem cobrado
+	short := transfer("tx-transfer-short", 1, 2, 5_200000)
+	short.Fee = 500000
+	require.NoError(t, repo.SaveTransactionWithOutbox(short))
+	require.NoError(t, workers.CallProcessTransaction(short, 1, db, chains, repo))
+
+	assert.Equal(t, domain.StatusFailed, statusOf(t, db, short.ID))
+	assert.Equal(t, domain.NewMoney(5_500000, "TRX"), balanceOf(t, db, 1))
+	assert.Empty(t, feeLines(t, db, short.ID))
+	assert.Equal(t, int64(500000), feesCollected(t, db))
+}
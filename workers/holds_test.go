@@ -0,0 +1,93 @@
+package workers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+)
+
+func heldOf(t *testing.T, db *gorm.DB, userID uint) domain.Money {
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ?", userID).Error)
+	return balance.HeldAmount()
+}
+
+// publishedWithdrawal lê a transação como o relay do outbox a publicaria no Kafka
+func publishedWithdrawal(t *testing.T, db *gorm.DB, txID string) domain.Transaction {
+	var msg domain.OutboxMessage
+	require.NoError(t, db.First(&msg, "transaction_id = ?", txID).Error)
+	var published domain.Transaction
+	require.NoError(t, json.Unmarshal(msg.Payload, &published))
+	return published
+}
+
+func TestHold_ConcurrentWithdrawalsCannotOverdraw(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	blockchainMock := new(mocks.BlockchainClient)
+	chains := tronChains(blockchainMock)
+
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "hold@example.com", WalletAddress: "TWallet"}).Error)
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+
+	// cinco pedidos de 3 TRX contra 10 TRX: só três cabem no saldo disponível
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.SaveWithdrawalWithHold(domain.Transaction{
+				ID:     fmt.Sprintf("tx-hold-%d", i),
+				UserID: 1,
+				Amount: domain.NewMoney(3_000000, "TRX"),
+				Type:   workers.TypeWithdraw,
+				Status: domain.StatusReceived,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	var accepted []string
+	for i, err := range errs {
+		if err == nil {
+			accepted = append(accepted, fmt.Sprintf("tx-hold-%d", i))
+			continue
+		}
+		assert.ErrorIs(t, err, domain.ErrInsufficientFunds)
+	}
+	require.Len(t, accepted, 3)
+	assert.Equal(t, domain.NewMoney(1_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(9_000000, "TRX"), heldOf(t, db, 1))
+	assert.Equal(t, int64(3), countRows(t, db, &domain.OutboxMessage{}, "transaction_id LIKE ?", "tx-hold-%"))
+	assertLedgerOK(t, db)
+
+	// o worker captura a reserva do primeiro; o envio do segundo falha e o valor volta ao disponível
+	blockchainMock.On("ValidateAddress", "TWallet").Return(nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, accepted[0]).Return(&domain.BlockchainTxResult{TxID: "chain-hold"}, nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, accepted[1]).Return(nil, errors.New("node unavailable"))
+
+	require.NoError(t, workers.CallProcessTransaction(publishedWithdrawal(t, db, accepted[0]), 1, db, chains, repo))
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, accepted[0]))
+	assert.Equal(t, domain.NewMoney(1_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), heldOf(t, db, 1))
+
+	require.NoError(t, workers.CallProcessTransaction(publishedWithdrawal(t, db, accepted[1]), 1, db, chains, repo))
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, accepted[1]))
+	assert.Equal(t, domain.NewMoney(4_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(3_000000, "TRX"), heldOf(t, db, 1))
+	assertLedgerOK(t, db)
+}
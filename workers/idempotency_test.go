@@ -0,0 +1,143 @@
+package workers_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const replays = 50
+
+func setupSQLiteDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	// :memory: é por conexão; uma única conexão mantém o mesmo banco para todas as goroutines
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	err = db.AutoMigrate(&domain.Transaction{}, &domain.Balance{}, &domain.User{},
+		&domain.LedgerAccount{}, &domain.JournalEntry{}, &domain.Posting{}, &domain.ProcessedMessage{},
+		&domain.TransactionStatusHistory{}, &domain.OutboxMessage{}, &domain.ApprovalDecision{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func replayConcurrently(t *testing.T, db *gorm.DB, tx domain.Transaction) {
+	blockchainMock := new(mocks.BlockchainClient)
+	repoMock := new(mocks.TransactionRepository)
+	blockchainMock.On("ValidateAddress", mock.Anything).Return(nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, tx.ID).Return(&domain.BlockchainTxResult{TxID: "hash-" + tx.ID}, nil)
+	repoMock.On("UpdateTransactionStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repoMock.On("UpdateTransactionHash", mock.Anything, mock.Anything).Return(nil)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, replays)
+	for i := 0; i < replays; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			errs <- workers.CallProcessTransaction(tx, workerID, db, tronChains(blockchainMock), repoMock)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	// só a entrega que gravou o marcador chega a enviar o saque
+	sends := 0
+	for _, call := range blockchainMock.Calls {
+		if call.Method == "Send" {
+			sends++
+		}
+	}
+	assert.LessOrEqual(t, sends, 1)
+}
+
+func balanceOf(t *testing.T, db *gorm.DB, userID uint) domain.Money {
+	var balance domain.Balance
+	assert.NoError(t, db.First(&balance, "user_id = ?", userID).Error)
+	return balance.Amount()
+}
+
+func countRows(t *testing.T, db *gorm.DB, model interface{}, query string, args ...interface{}) int64 {
+	var count int64
+	assert.NoError(t, db.Model(model).Where(query, args...).Count(&count).Error)
+	return count
+}
+
+func TestProcessTransaction_ConcurrentDepositReplays(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	tx := domain.Transaction{
+		ID:     "tx-replay-deposit",
+		UserID: 1,
+		Amount: domain.NewMoney(25_000000, "TRX"),
+		Type:   workers.TypeDeposit,
+	}
+
+	replayConcurrently(t, db, tx)
+
+	assert.Equal(t, tx.Amount, balanceOf(t, db, tx.UserID))
+	assert.Equal(t, int64(1), countRows(t, db, &domain.Transaction{}, "id = ?", tx.ID))
+	assert.Equal(t, int64(1), countRows(t, db, &domain.JournalEntry{}, "transaction_id = ?", tx.ID))
+	assert.Equal(t, int64(1), countRows(t, db, &domain.ProcessedMessage{}, "transaction_id = ?", tx.ID))
+
+	report, err := ledger.NewChecker(db).Check()
+	assert.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestProcessTransaction_ConcurrentWithdrawReplays(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	// usuário sem carteira: o saque é debitado, mas não segue para a blockchain
+	assert.NoError(t, db.Create(&domain.User{ID: 2, Email: "replay@example.com", WalletAddress: "TWallet"}).Error)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 2, Amount: domain.NewMoney(100_000000, "TRX"), Type: workers.TypeDeposit}
+	replayConcurrently(t, db, deposit)
+
+	withdraw := domain.Transaction{ID: "tx-replay-withdraw", UserID: 2, Amount: domain.NewMoney(30_000000, "TRX"), Type: workers.TypeWithdraw}
+	replayConcurrently(t, db, withdraw)
+
+	assert.Equal(t, domain.NewMoney(70_000000, "TRX"), balanceOf(t, db, 2))
+	assert.Equal(t, int64(1), countRows(t, db, &domain.Transaction{}, "id = ?", withdraw.ID))
+	assert.Equal(t, int64(1), countRows(t, db, &domain.JournalEntry{}, "transaction_id = ?", withdraw.ID))
+}
+
+func TestProcessTransaction_RejectedReplayStaysRejected(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	withdraw := domain.Transaction{ID: "tx-rejected", UserID: 3, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeWithdraw}
+	replayConcurrently(t, db, withdraw)
+
+	var processed domain.ProcessedMessage
+	assert.NoError(t, db.First(&processed, "transaction_id = ?", withdraw.ID).Error)
+	assert.Equal(t, domain.ProcessedOutcomeRejected, processed.Outcome)
+
+	// mesmo com saldo depois, a reentrega da mensagem rejeitada não pode debitar
+	deposit := domain.Transaction{ID: "tx-late-deposit", UserID: 3, Amount: domain.NewMoney(50_000000, "TRX"), Type: workers.TypeDeposit}
+	replayConcurrently(t, db, deposit)
+	replayConcurrently(t, db, withdraw)
+
+	assert.Equal(t, deposit.Amount, balanceOf(t, db, 3))
+
+	var rejected domain.Transaction
+	assert.NoError(t, db.First(&rejected, "id = ?", withdraw.ID).Error)
+	assert.Equal(t, domain.StatusFailed, rejected.Status)
+	assert.Equal(t, int64(2), countRows(t, db, &domain.TransactionStatusHistory{}, "transaction_id = ?", withdraw.ID))
+}
@@ -0,0 +1,94 @@
+package workers_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+)
+
+func TestLimits_ConcurrentWithdrawalsCannotExceedDailyCap(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chains := tronChains(new(mocks.BlockchainClient))
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(100_000000, "TRX"), Type: workers.TypeDeposit}
+	require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+
+	// um saque que falhou hoje não conta para o teto
+	require.NoError(t, db.Create(&domain.Transaction{ID: "tx-failed", UserID: 1, Amount: domain.NewMoney(5_000000, "TRX"),
+		Type: workers.TypeWithdraw, Status: domain.StatusFailed}).Error)
+
+	limits := domain.Limits{Currency: "TRX", MaxWithdrawal: 4_000000, DailyWithdrawal: 10_000000, MonthlyWithdrawal: 50_000000}
+
+	// cinco pedidos de 3 TRX contra um teto diário de 10 TRX: só três cabem, mesmo com saldo de sobra
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.SaveWithdrawalWithinLimits(domain.Transaction{
+				ID:     fmt.Sprintf("tx-limit-%d", i),
+				UserID: 1,
+				Amount: domain.NewMoney(3_000000, "TRX"),
+				Type:   workers.TypeWithdraw,
+				Status: domain.StatusReceived,
+			}, limits)
+		}(i)
+	}
+	wg.Wait()
+
+	accepted := 0
+	for _, err := range errs {
+		if err == nil {
+			accepted++
+			continue
+		}
+		assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+	}
+	assert.Equal(t, 3, accepted)
+	assert.Equal(t, domain.NewMoney(9_000000, "TRX"), heldOf(t, db, 1))
+
+	usage, err := repo.GetLimitUsage(1, "TRX", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(9_000000), usage.DailyWithdrawal)
+	assert.Equal(t, int64(9_000000), usage.MonthlyWithdrawal)
+	assert.Equal(t, int64(100_000000), usage.DailyDeposit)
+
+	// acima do teto por saque, mesmo com folga no dia
+	err = repo.SaveWithdrawalWithinLimits(domain.Transaction{ID: "tx-too-big", UserID: 1, Amount: domain.NewMoney(5_000000, "TRX"),
+		Type: workers.TypeWithdraw, Status: domain.StatusReceived}, domain.Limits{Currency: "TRX", MaxWithdrawal: 4_000000})
+	assert.ErrorIs(t, err, domain.ErrLimitExceeded)
+	assert.Zero(t, countRows(t, db, &domain.Transaction{}, "id = ?", "tx-too-big"))
+	assertLedgerOK(t, db)
+}
+
+func TestLimits_DailyDepositCap(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	limits := domain.Limits{Currency: "TRX", DailyDeposit: 10_000000}
+
+	deposit := func(id string, units int64) error {
+		return repo.SaveDepositWithinLimits(domain.Transaction{ID: id, UserID: 1, Amount: domain.NewMoney(units, "TRX"),
+			Type: workers.TypeDeposit, Status: domain.StatusReceived}, limits)
+	}
+
+	require.NoError(t, deposit("tx-dep-1", 6_000000))
+	assert.ErrorIs(t, deposit("tx-dep-2", 5_000000), domain.ErrLimitExceeded)
+	require.NoError(t, deposit("tx-dep-3", 4_000000))
+	assert.Equal(t, int64(2), countRows(t, db, &domain.OutboxMessage{}, "transaction_id LIKE ?", "tx-dep-%"))
+
+	// o teto é por moeda
+	usage, err := repo.GetLimitUsage(1, "USDT", time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, usage.DailyDeposit)
+}
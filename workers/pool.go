@@ -0,0 +1,243 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+)
+
+const defaultLaneSize = 100
+
+// WorkerStats é um retrato das métricas de um worker do pool
+type WorkerStats struct {
+	WorkerID      int           `json:"worker_id"`
+	Processed     uint64        `json:"processed"`
+	Failed        uint64        `json:"failed"`
+	InFlight      bool          `json:"in_flight"`
+	TotalDuration time.Duration `json:"total_duration"`
+	LastJobAt     time.Time     `json:"last_job_at"`
+}
+
+type workerMetrics struct {
+	processed     atomic.Uint64
+	failed        atomic.Uint64
+	inFlight      atomic.Bool
+	totalDuration atomic.Int64
+	lastJobAt     atomic.Int64
+}
+
+// Pool mantém um número fixo de workers, cada um com sua fila. O Dispatcher
+// encaminha cada usuário sempre para o mesmo worker, preservando a ordem por usuário.
+type Pool struct {
+	// Retry controla as novas tentativas de um job com falha. Elas acontecem na própria fila
+	// do worker, então os jobs seguintes do mesmo usuário esperam o resultado.
+	Retry RetryPolicy
+
+	size   int
+	db     *gorm.DB
+	chains *d.ChainRegistry
+	repo   d.TransactionRepository
+
+	lanes   []chan d.TransactionJob
+	metrics []*workerMetrics
+
+	stopDispatch context.CancelFunc
+	abort        chan struct{}
+	workers      sync.WaitGroup
+	startOnce    sync.Once
+	stopOnce     sync.Once
+}
+
+func NewPool(size int, db *gorm.DB, chains *d.ChainRegistry, repo d.TransactionRepository) *Pool {
+	if size < 1 {
+		size = 1
+	}
+
+	p := &Pool{
+		Retry:   DefaultRetryPolicy(),
+		size:    size,
+		db:      db,
+		chains:  chains,
+		repo:    repo,
+		lanes:   make([]chan d.TransactionJob, size),
+		metrics: make([]*workerMetrics, size),
+		abort:   make(chan struct{}),
+	}
+	for i := range p.lanes {
+		p.lanes[i] = make(chan d.TransactionJob, defaultLaneSize)
+		p.metrics[i] = &workerMetrics{}
+	}
+	return p
+}
+
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Start sobe o dispatcher e os workers. O pool tem ciclo de vida próprio: ele não
+// para com o ctx da aplicação, e sim com Stop, para poder drenar os jobs em andamento.
+func (p *Pool) Start(jobs <-chan d.TransactionJob, acks chan<- d.JobAck) {
+	p.startOnce.Do(func() {
+		dispatchCtx, cancel := context.WithCancel(context.Background())
+		p.stopDispatch = cancel
+
+		for i := range p.lanes {
+			p.workers.Add(1)
+			go p.run(i, acks)
+		}
+
+		go func() {
+			Dispatcher(dispatchCtx, jobs, p.lanes)
+			// sem novos jobs: os workers drenam o que já está na fila e encerram
+			for _, lane := range p.lanes {
+				close(lane)
+			}
+		}()
+
+		log.Printf("👷 Pool iniciado com %d workers", p.size)
+	})
+}
+
+// Stop para de aceitar jobs e espera os workers drenarem as filas. Se ctx expirar antes,
+// os jobs ainda não iniciados são abandonados sem ack (o offset não é confirmado e o
+// Kafka os reentrega), mas o job em andamento sempre termina a transação no banco.
+func (p *Pool) Stop(ctx context.Context) error {
+	var err error
+
+	p.stopOnce.Do(func() {
+		if p.stopDispatch != nil {
+			p.stopDispatch()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			p.workers.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			log.Printf("⚠️ Pool: tempo de drenagem esgotado, jobs pendentes ficam para reentrega")
+			close(p.abort)
+			<-done
+			err = ctx.Err()
+		}
+
+		for _, s := range p.Stats() {
+			log.Printf("📊 Worker %d: %d processadas, %d com falha, tempo total %s",
+				s.WorkerID, s.Processed, s.Failed, s.TotalDuration)
+		}
+		log.Println("🛑 Pool encerrado")
+	})
+
+	return err
+}
+
+func (p *Pool) Stats() []WorkerStats {
+	stats := make([]WorkerStats, len(p.metrics))
+	for i, m := range p.metrics {
+		stats[i] = WorkerStats{
+			WorkerID:      i + 1,
+			Processed:     m.processed.Load(),
+			Failed:        m.failed.Load(),
+			InFlight:      m.inFlight.Load(),
+			TotalDuration: time.Duration(m.totalDuration.Load()),
+		}
+		if last := m.lastJobAt.Load(); last > 0 {
+			stats[i].LastJobAt = time.Unix(0, last)
+		}
+	}
+	return stats
+}
+
+func (p *Pool) run(index int, acks chan<- d.JobAck) {
+	defer p.workers.Done()
+
+	id := index + 1
+	lane := p.lanes[index]
+	m := p.metrics[index]
+
+	for {
+		// abort tem prioridade: depois do prazo nenhum job novo é iniciado
+		select {
+		case <-p.abort:
+			return
+		default:
+		}
+
+		select {
+		case <-p.abort:
+			return
+
+		case job, ok := <-lane:
+			if !ok {
+				log.Printf("🛑 Worker %d encerrado", id)
+				return
+			}
+
+			m.inFlight.Store(true)
+			start := time.Now()
+			attempts, aborted, err := p.process(job, id)
+			m.totalDuration.Add(int64(time.Since(start)))
+			m.lastJobAt.Store(time.Now().UnixNano())
+			m.inFlight.Store(false)
+
+			if aborted {
+				log.Printf("🛑 Worker %d encerrado durante as tentativas do offset %d", id, job.Offset)
+				return
+			}
+
+			if err != nil {
+				m.failed.Add(1)
+			} else {
+				m.processed.Add(1)
+			}
+
+			// o job terminou: o ack tem prioridade sobre o abort sempre que houver espaço
+			ack := d.JobAck{Partition: job.Partition, Offset: job.Offset, Err: err, Attempts: attempts}
+			select {
+			case acks <- ack:
+				continue
+			default:
+			}
+
+			select {
+			case acks <- ack:
+			case <-p.abort:
+				log.Printf("🛑 Worker %d encerrado antes de confirmar offset %d", id, job.Offset)
+				return
+			}
+		}
+	}
+}
+
+// process tenta o job até ter sucesso ou esgotar a RetryPolicy. Se o pool for abortado
+// durante a espera, retorna aborted e o job fica sem ack para ser reentregue pelo Kafka.
+func (p *Pool) process(job d.TransactionJob, id int) (attempts int, aborted bool, err error) {
+	for {
+		attempts++
+		err = processTransaction(job.Transaction, id, p.db, p.chains, p.repo)
+		if err == nil || p.Retry.exhausted(attempts) {
+			return attempts, false, err
+		}
+
+		backoff := p.Retry.Backoff(attempts)
+		log.Printf("🔁 Worker %d: tentativa %d/%d falhou na partição %d offset %d, nova tentativa em %s: %v",
+			id, attempts, p.Retry.MaxAttempts, job.Partition, job.Offset, backoff, err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-p.abort:
+			timer.Stop()
+			return attempts, true, err
+		}
+	}
+}
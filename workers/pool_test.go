@@ -0,0 +1,183 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestPool_ProcessesAndDrains(t *testing.T) {
+	db := setupSQLiteDB(t)
+
+	pool := workers.NewPool(3, db, tronChains(new(mocks.BlockchainClient)), new(mocks.TransactionRepository))
+	assert.Equal(t, 3, pool.Size())
+
+	jobs := make(chan domain.TransactionJob, 30)
+	acks := make(chan domain.JobAck, 30)
+	pool.Start(jobs, acks)
+
+	for i := 0; i < 30; i++ {
+		jobs <- domain.TransactionJob{
+			Transaction: domain.Transaction{
+				ID:     fmt.Sprintf("tx-pool-%d", i),
+				UserID: uint(i%6) + 1,
+				Amount: domain.NewMoney(1_000000, "TRX"),
+				Type:   workers.TypeDeposit,
+			},
+			Offset: int64(i),
+		}
+	}
+
+	// espera o dispatcher tirar tudo do canal antes de parar
+	assert.Eventually(t, func() bool { return len(jobs) == 0 }, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, pool.Stop(ctx))
+
+	// tudo o que foi entregue ao pool terminou e foi confirmado
+	assert.Len(t, acks, 30)
+	for len(acks) > 0 {
+		assert.NoError(t, (<-acks).Err)
+	}
+
+	var processed uint64
+	for _, s := range pool.Stats() {
+		processed += s.Processed
+		assert.Zero(t, s.Failed)
+		assert.False(t, s.InFlight)
+	}
+	assert.Equal(t, uint64(30), processed)
+	assert.Equal(t, int64(30), countRows(t, db, &domain.Transaction{}, "1 = 1"))
+}
+
+func TestPool_StopTimeoutFinishesInFlightAndLeavesQueueUnacked(t *testing.T) {
+	db := setupSQLiteDB(t)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "pool@example.com", WalletAddress: "TWallet"}).Error)
+
+	blockchainMock := new(mocks.BlockchainClient)
+	repoMock := new(mocks.TransactionRepository)
+	repoMock.On("UpdateTransactionStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	repoMock.On("UpdateTransactionHash", mock.Anything, mock.Anything).Return(nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	blockchainMock.On("ValidateAddress", "TWallet").Return(nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, "tx-slow").
+		Run(func(args mock.Arguments) {
+			close(started)
+			<-release
+		}).
+		Return(&domain.BlockchainTxResult{TxID: "hash"}, nil)
+
+	pool := workers.NewPool(1, db, tronChains(blockchainMock), repoMock)
+	jobs := make(chan domain.TransactionJob, 10)
+	acks := make(chan domain.JobAck, 10)
+	pool.Start(jobs, acks)
+
+	jobs <- domain.TransactionJob{Transaction: domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}, Offset: 0}
+	jobs <- domain.TransactionJob{Transaction: domain.Transaction{ID: "tx-slow", UserID: 1, Amount: domain.NewMoney(1_000000, "TRX"), Type: workers.TypeWithdraw}, Offset: 1}
+	for i := 2; i < 5; i++ {
+		jobs <- domain.TransactionJob{Transaction: domain.Transaction{ID: fmt.Sprintf("tx-queued-%d", i), UserID: 1, Amount: domain.NewMoney(1_000000, "TRX"), Type: workers.TypeDeposit}, Offset: int64(i)}
+	}
+
+	<-started
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// o saque só termina depois que o prazo de drenagem expirou
+	go func() {
+		<-ctx.Done()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+
+	assert.ErrorIs(t, pool.Stop(ctx), context.DeadlineExceeded)
+
+	// o saque em andamento terminou; os depósitos na fila não foram iniciados nem confirmados
+	var offsets []int64
+	for len(acks) > 0 {
+		offsets = append(offsets, (<-acks).Offset)
+	}
+	assert.Equal(t, []int64{0, 1}, offsets)
+	assert.Equal(t, int64(0), countRows(t, db, &domain.Transaction{}, "id LIKE ?", "tx-queued-%"))
+	blockchainMock.AssertExpectations(t)
+}
+
+func waitAck(t *testing.T, acks <-chan domain.JobAck) domain.JobAck {
+	select {
+	case ack := <-acks:
+		return ack
+	case <-time.After(time.Second):
+		t.Fatal("timeout esperando ack do worker")
+		return domain.JobAck{}
+	}
+}
+
+// failFirstMarkers faz as primeiras n gravações do marcador de processamento falharem,
+// como um banco fora do ar por alguns instantes
+func failFirstMarkers(t *testing.T, db *gorm.DB, n int) {
+	var failures atomic.Int32
+	err := db.Callback().Create().Before("gorm:create").Register("test:fail_markers", func(tx *gorm.DB) {
+		if tx.Statement.Table == "processed_messages" && failures.Add(1) <= int32(n) {
+			tx.AddError(errors.New("db down"))
+		}
+	})
+	assert.NoError(t, err)
+}
+
+func TestPool_RetriesInTheUserLaneBeforeTheNextJob(t *testing.T) {
+	db := setupSQLiteDB(t)
+	failFirstMarkers(t, db, 1)
+
+	pool := workers.NewPool(1, db, tronChains(new(mocks.BlockchainClient)), new(mocks.TransactionRepository))
+	pool.Retry = workers.RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond}
+	jobs := make(chan domain.TransactionJob, 2)
+	acks := make(chan domain.JobAck, 2)
+	pool.Start(jobs, acks)
+	defer pool.Stop(context.Background())
+
+	jobs <- domain.TransactionJob{Transaction: domain.Transaction{ID: "tx-first", UserID: 1, Amount: domain.NewMoney(1_000000, "TRX"), Type: workers.TypeDeposit}, Offset: 0}
+	jobs <- domain.TransactionJob{Transaction: domain.Transaction{ID: "tx-second", UserID: 1, Amount: domain.NewMoney(1_000000, "TRX"), Type: workers.TypeDeposit}, Offset: 1}
+
+	// a falha não é devolvida ao consumer: o job seguinte do usuário espera a nova tentativa
+	first := waitAck(t, acks)
+	assert.Equal(t, int64(0), first.Offset)
+	assert.NoError(t, first.Err)
+	assert.Equal(t, 2, first.Attempts)
+
+	second := waitAck(t, acks)
+	assert.Equal(t, int64(1), second.Offset)
+	assert.NoError(t, second.Err)
+	assert.Equal(t, 1, second.Attempts)
+}
+
+func TestPool_ExhaustedRetriesAckTheFailure(t *testing.T) {
+	db := setupSQLiteDB(t)
+	failFirstMarkers(t, db, 10)
+
+	pool := workers.NewPool(1, db, tronChains(new(mocks.BlockchainClient)), new(mocks.TransactionRepository))
+	pool.Retry = workers.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}
+	jobs := make(chan domain.TransactionJob, 1)
+	acks := make(chan domain.JobAck, 1)
+	pool.Start(jobs, acks)
+	defer pool.Stop(context.Background())
+
+	jobs <- domain.TransactionJob{Transaction: domain.Transaction{ID: "tx-poison", UserID: 1, Amount: domain.NewMoney(1_000000, "TRX"), Type: workers.TypeDeposit}, Offset: 3}
+
+	ack := waitAck(t, acks)
+	assert.EqualError(t, ack.Err, "db down")
+	assert.Equal(t, 2, ack.Attempts)
+	assert.Equal(t, uint64(1), pool.Stats()[0].Failed)
+}
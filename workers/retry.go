@@ -0,0 +1,43 @@
+package workers
+
+import "time"
+
+// RetryPolicy define quantas vezes o worker tenta processar um job antes de devolver o ack
+// com falha (e a mensagem ir para o dead-letter), com backoff exponencial entre as tentativas.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// Backoff retorna a espera antes da próxima tentativa: InitialBackoff * 2^(attempt-1), limitado a MaxBackoff
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return backoff
+}
+
+func (p RetryPolicy) exhausted(attempts int) bool {
+	return attempts >= p.MaxAttempts
+}
@@ -0,0 +1,26 @@
+package workers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := workers.RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(0))
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 200*time.Millisecond, policy.Backoff(2))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, 800*time.Millisecond, policy.Backoff(4))
+	assert.Equal(t, time.Second, policy.Backoff(5))
+	assert.Equal(t, time.Second, policy.Backoff(50))
+}
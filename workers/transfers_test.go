@@ -0,0 +1,112 @@
+package workers_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
+	"github.com/gabrielksneiva/go-financial-transactions/workers"
+)
+
+func transfer(id string, from, to uint, units int64) domain.Transaction {
+	return domain.Transaction{
+		ID:             id,
+		UserID:         from,
+		Amount:         domain.NewMoney(units, "TRX"),
+		Type:           workers.TypeTransfer,
+		Status:         domain.StatusReceived,
+		CounterpartyID: to,
+		Memo:           "rent",
+	}
+}
+
+func TestTransfer_MovesFundsAndLinksLegs(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chains := tronChains(new(mocks.BlockchainClient))
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+
+	out := transfer("tx-transfer", 1, 2, 4_000000)
+	require.NoError(t, repo.SaveTransactionWithOutbox(out))
+	require.NoError(t, workers.CallProcessTransaction(out, 1, db, chains, repo))
+	// a reentrega não credita de novo
+	require.NoError(t, workers.CallProcessTransaction(out, 1, db, chains, repo))
+
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(4_000000, "TRX"), balanceOf(t, db, 2))
+	assert.Equal(t, domain.StatusCompleted, statusOf(t, db, out.ID))
+	assertLedgerOK(t, db)
+
+	// cada usuário vê a sua perna no extrato, apontando para a outra
+	senderTxs, err := repo.GetByUser(1)
+	require.NoError(t, err)
+	var sent domain.Transaction
+	for _, tx := range senderTxs {
+		if tx.ID == out.ID {
+			sent = tx
+		}
+	}
+	recipientTxs, err := repo.GetByUser(2)
+	require.NoError(t, err)
+	require.Len(t, recipientTxs, 1)
+	received := recipientTxs[0]
+
+	assert.Equal(t, domain.TransferInTransaction, received.Type)
+	assert.Equal(t, domain.StatusCompleted, received.Status)
+	assert.Equal(t, uint(1), received.CounterpartyID)
+	assert.Equal(t, out.ID, received.LinkedTransactionID)
+	assert.Equal(t, received.ID, sent.LinkedTransactionID)
+	assert.Equal(t, "rent", received.Memo)
+}
+
+func TestTransfer_InsufficientFundsFailsWithoutCredit(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chains := tronChains(new(mocks.BlockchainClient))
+
+	out := transfer("tx-transfer-empty", 1, 2, 1_000000)
+	require.NoError(t, repo.SaveTransactionWithOutbox(out))
+	require.NoError(t, workers.CallProcessTransaction(out, 1, db, chains, repo))
+
+	assert.Equal(t, domain.StatusFailed, statusOf(t, db, out.ID))
+	assert.Zero(t, countRows(t, db, &domain.Transaction{}, "user_id = ?", 2))
+	assertLedgerOK(t, db)
+}
+
+func TestTransfer_OppositeDirectionsConcurrently(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	chains := tronChains(new(mocks.BlockchainClient))
+
+	for _, userID := range []uint{1, 2} {
+		deposit := domain.Transaction{ID: fmt.Sprintf("tx-funding-%d", userID), UserID: userID, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+		require.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		from, to := uint(1), uint(2)
+		if i%2 == 1 {
+			from, to = to, from
+		}
+		wg.Add(1)
+		go func(tx domain.Transaction) {
+			defer wg.Done()
+			assert.NoError(t, workers.CallProcessTransaction(tx, 1, db, chains, repo))
+		}(transfer(fmt.Sprintf("tx-transfer-%d", i), from, to, 1_000000))
+	}
+	wg.Wait()
+
+	assert.Equal(t, domain.NewMoney(10_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, domain.NewMoney(10_000000, "TRX"), balanceOf(t, db, 2))
+	assert.Equal(t, int64(10), countRows(t, db, &domain.Transaction{}, "type = ?", domain.TransferInTransaction))
+	assertLedgerOK(t, db)
+}
@@ -1,178 +1,460 @@
 package workers
 
 import (
-	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 
 	d "github.com/gabrielksneiva/go-financial-transactions/domain"
+	"github.com/gabrielksneiva/go-financial-transactions/ledger"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
 )
 
 const (
 	TypeDeposit  = "deposit"
 	TypeWithdraw = "withdraw"
 	TypeRefund   = "refund"
+	TypeTransfer = d.TransferOutTransaction
+)
 
-	StatusPending   = "PENDING"
-	StatusCompleted = "COMPLETED"
-	StatusFailed    = "FAILED"
+var (
+	ErrInsufficientFunds = d.ErrInsufficientFunds
+	ErrAlreadyProcessed  = errors.New("transaction already processed")
 )
 
-func Worker(ctx context.Context, id int, jobs <-chan d.Transaction, db *gorm.DB, b d.BlockchainClient, repo d.TransactionRepository) {
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("🛑 Worker %d encerrado", id)
-			return
+// CallProcessTransaction is only exposed for tests
+var CallProcessTransaction = processTransaction
 
-		case tx := <-jobs:
-			processTransaction(tx, id, db, b, repo)
-		}
-	}
-}
-
-func processTransaction(tx d.Transaction, workerID int, db *gorm.DB, b d.BlockchainClient, repo d.TransactionRepository) {
-	log.Printf("📥 Worker %d recebeu transação %s (%.2f)", workerID, tx.ID, tx.Amount)
+// processTransaction retorna erro apenas quando a transação não pôde ser persistida
+// e deve ser reentregue; rejeições de negócio (ex.: saldo insuficiente) são definitivas.
+func processTransaction(tx d.Transaction, workerID int, db *gorm.DB, chains *d.ChainRegistry, repo d.TransactionRepository) error {
+	log.Printf("📥 Worker %d recebeu transação %s (%s)", workerID, tx.ID, tx.Amount)
 
 	err := db.Transaction(func(txDB *gorm.DB) error {
 		return handleTransactionDB(txDB, &tx, workerID)
 	})
 
+	if errors.Is(err, ErrAlreadyProcessed) {
+		log.Printf("🔁 Worker %d: transação %s já processada, mensagem ignorada", workerID, tx.ID)
+		return nil
+	}
+	if errors.Is(err, ErrInsufficientFunds) {
+		log.Printf("⛔ Worker %d rejeitou transação %s: %v", workerID, tx.ID, err)
+		// A rejeição também é registrada para que uma reentrega não reavalie o saldo,
+		// e a linha RECEIVED gravada pela API passa a FAILED
+		err := db.Transaction(func(txDB *gorm.DB) error {
+			if err := markProcessed(txDB, tx.ID, workerID, d.ProcessedOutcomeRejected); err != nil {
+				return err
+			}
+			if err := ensureReceived(txDB, tx); err != nil {
+				return err
+			}
+			return repositories.TransitionStatus(txDB, tx.ID, d.StatusReceived, d.StatusFailed, "insufficient funds")
+		})
+		if err != nil && !errors.Is(err, ErrAlreadyProcessed) {
+			log.Printf("❌ Worker %d: erro ao registrar rejeição da transação %s: %v", workerID, tx.ID, err)
+			return err
+		}
+		return nil
+	}
 	if err != nil {
 		log.Printf("❌ Worker %d falhou ao processar transação %s: %v", workerID, tx.ID, err)
-		return
+		return err
 	}
 
 	if tx.Type == TypeWithdraw {
-		handleWithdrawal(tx, workerID, db, b, repo)
+		handleWithdrawal(tx, workerID, db, chains, repo)
+	}
+
+	return nil
+}
+
+// markProcessed insere o ID da transação antes de qualquer efeito; se a linha já existe,
+// outra entrega chegou primeiro e ErrAlreadyProcessed é retornado.
+func markProcessed(txDB *gorm.DB, transactionID string, workerID int, outcome string) error {
+	result := txDB.Clauses(clause.OnConflict{DoNothing: true}).Create(&d.ProcessedMessage{
+		TransactionID: transactionID,
+		Outcome:       outcome,
+		WorkerID:      workerID,
+		ProcessedAt:   time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
 	}
+	if result.RowsAffected == 0 {
+		return ErrAlreadyProcessed
+	}
+	return nil
 }
 
 func handleTransactionDB(txDB *gorm.DB, tx *d.Transaction, workerID int) error {
+	if err := markProcessed(txDB, tx.ID, workerID, d.ProcessedOutcomeApplied); err != nil {
+		if !errors.Is(err, ErrAlreadyProcessed) {
+			log.Printf("❌ Worker %d: erro ao registrar processamento: %v", workerID, err)
+		}
+		return err
+	}
+
+	if tx.Type == TypeTransfer {
+		return applyTransfer(txDB, tx, workerID)
+	}
+
+	// O saque aceito pela API já tem o valor reservado; basta capturar a reserva
+	if tx.Type == TypeWithdraw {
+		captured, err := captureHold(txDB, tx)
+		if err != nil || captured {
+			return err
+		}
+	}
+
+	// Cada moeda tem seu saldo: um saque em USDT só trava e consulta a linha de USDT
 	var balance d.Balance
+	key := d.NewBalance(tx.UserID, tx.Amount)
 
 	if err := txDB.Clauses(clause.Locking{Strength: "UPDATE"}).
-		Where("user_id = ?", tx.UserID).
-		FirstOrCreate(&balance, d.Balance{UserID: tx.UserID}).Error; err != nil {
+		FirstOrCreate(&balance, d.Balance{UserID: key.UserID, Currency: key.Currency}).Error; err != nil {
 		log.Printf("❌ Worker %d: erro ao buscar/criar saldo: %v", workerID, err)
 		return err
 	}
 
-	var newBalance float64
+	// O saldo é uma projeção do razão: os lançamentos abaixo atualizam balances.amount
 	switch tx.Type {
 	case TypeWithdraw:
-		newBalance = balance.Amount - tx.Amount
-		if newBalance < 0 {
+		newBalance, err := balance.Amount().Sub(tx.Total())
+		if err != nil {
+			return err
+		}
+		if newBalance.IsNegative() {
 			log.Printf("⛔ Worker %d: fundos insuficientes para usuário %d", workerID, tx.UserID)
-			return fmt.Errorf("insufficient funds for user %d", tx.UserID)
+			return fmt.Errorf("%w for user %d", ErrInsufficientFunds, tx.UserID)
+		}
+		if err := ledger.Withdraw(txDB, *tx); err != nil {
+			log.Printf("❌ Worker %d: erro ao lançar saque no razão: %v", workerID, err)
+			return err
 		}
-		log.Printf("💸 Worker %d: saldo atual %.2f → novo saldo %.2f (saque)", workerID, balance.Amount, newBalance)
+		if err := chargeFee(txDB, *tx, false); err != nil {
+			log.Printf("❌ Worker %d: erro ao lançar tarifa no razão: %v", workerID, err)
+			return err
+		}
+		log.Printf("💸 Worker %d: saldo atual %s → novo saldo %s (saque)", workerID, balance.Amount(), newBalance)
 	case TypeDeposit:
-		newBalance = balance.Amount + tx.Amount
-		log.Printf("💰 Worker %d: saldo atual %.2f → novo saldo %.2f (depósito)", workerID, balance.Amount, newBalance)
+		newBalance, err := balance.Amount().Add(tx.Amount)
+		if err != nil {
+			return err
+		}
+		if err := ledger.Deposit(txDB, *tx); err != nil {
+			log.Printf("❌ Worker %d: erro ao lançar depósito no razão: %v", workerID, err)
+			return err
+		}
+		log.Printf("💰 Worker %d: saldo atual %s → novo saldo %s (depósito)", workerID, balance.Amount(), newBalance)
 	}
 
-	if err := txDB.Model(&d.Balance{}).
-		Where("user_id = ?", tx.UserID).
-		Update("amount", newBalance).Error; err != nil {
-		log.Printf("❌ Worker %d: erro ao atualizar saldo: %v", workerID, err)
+	if err := ensureReceived(txDB, *tx); err != nil {
+		log.Printf("❌ Worker %d: erro ao salvar transação: %v", workerID, err)
 		return err
 	}
 
-	tx.Status = map[string]string{
-		TypeDeposit:  StatusCompleted,
-		TypeWithdraw: StatusPending,
+	target := map[string]d.TransactionStatus{
+		TypeDeposit:  d.StatusCompleted,
+		TypeWithdraw: d.StatusPending,
 	}[tx.Type]
 
-	if err := txDB.Create(tx).Error; err != nil {
-		log.Printf("❌ Worker %d: erro ao salvar transação: %v", workerID, err)
+	if err := repositories.TransitionStatus(txDB, tx.ID, d.StatusReceived, target, "processed by worker"); err != nil {
+		log.Printf("❌ Worker %d: erro ao atualizar status para %s: %v", workerID, target, err)
 		return err
 	}
+	tx.Status = target
 
 	return nil
 }
 
-func handleWithdrawal(tx d.Transaction, workerID int, db *gorm.DB, b d.BlockchainClient, repo d.TransactionRepository) {
+// captureHold leva para clearing o valor reservado de um saque (RECEIVED, ou APPROVED após
+// aprovação) e o passa a PENDING; devolve false para saques sem reserva, gravados antes das
+// reservas ou publicados diretamente, que seguem debitando o saldo disponível
+func captureHold(txDB *gorm.DB, tx *d.Transaction) (bool, error) {
+	var holds int64
+	if err := txDB.Model(&d.JournalEntry{}).
+		Where("transaction_id = ? AND description = ?", tx.ID, ledger.DescriptionHold).
+		Count(&holds).Error; err != nil {
+		return false, err
+	}
+	if holds == 0 {
+		return false, nil
+	}
+
+	var stored d.Transaction
+	if err := txDB.Select("id", "status").Where("id = ?", tx.ID).Take(&stored).Error; err != nil {
+		return false, err
+	}
+
+	if err := ledger.CaptureHold(txDB, *tx); err != nil {
+		return false, err
+	}
+	if err := chargeFee(txDB, *tx, true); err != nil {
+		return false, err
+	}
+	if err := repositories.TransitionStatus(txDB, tx.ID, stored.Status, d.StatusPending, "processed by worker"); err != nil {
+		return false, err
+	}
+	tx.Status = d.StatusPending
+	return true, nil
+}
+
+// applyTransfer debita o remetente, credita o destinatário e grava a perna de entrada numa única
+// transação do banco
+func applyTransfer(txDB *gorm.DB, tx *d.Transaction, workerID int) error {
+	if tx.CounterpartyID == 0 {
+		return d.ErrRecipientNotFound
+	}
+	if tx.CounterpartyID == tx.UserID {
+		return d.ErrSelfTransfer
+	}
+
+	balances, err := lockBalances(txDB, tx.Amount.Currency, tx.UserID, tx.CounterpartyID)
+	if err != nil {
+		log.Printf("❌ Worker %d: erro ao travar saldos da transferência: %v", workerID, err)
+		return err
+	}
+	if balances[tx.UserID].Amount().Cmp(tx.Total()) < 0 {
+		log.Printf("⛔ Worker %d: fundos insuficientes para usuário %d", workerID, tx.UserID)
+		return fmt.Errorf("%w for user %d", ErrInsufficientFunds, tx.UserID)
+	}
+
+	if err := ledger.Transfer(txDB, *tx); err != nil {
+		log.Printf("❌ Worker %d: erro ao lançar transferência no razão: %v", workerID, err)
+		return err
+	}
+	if err := chargeFee(txDB, *tx, false); err != nil {
+		log.Printf("❌ Worker %d: erro ao lançar tarifa no razão: %v", workerID, err)
+		return err
+	}
+
+	if err := ensureReceived(txDB, *tx); err != nil {
+		return err
+	}
+
+	credit := d.Transaction{
+		ID:                  uuid.New().String(),
+		UserID:              tx.CounterpartyID,
+		Amount:              tx.Amount,
+		Timestamp:           time.Now(),
+		Type:                d.TransferInTransaction,
+		Status:              d.StatusCompleted,
+		CounterpartyID:      tx.UserID,
+		LinkedTransactionID: tx.ID,
+		Memo:                tx.Memo,
+	}
+	if _, err := repositories.EnsureTransaction(txDB, &credit, fmt.Sprintf("transfer from user %d", tx.UserID)); err != nil {
+		return err
+	}
+	if err := txDB.Model(&d.Transaction{}).Where("id = ?", tx.ID).
+		Update("linked_transaction_id", credit.ID).Error; err != nil {
+		return err
+	}
+	tx.LinkedTransactionID = credit.ID
+
+	if err := repositories.TransitionStatus(txDB, tx.ID, d.StatusReceived, d.StatusCompleted, "processed by worker"); err != nil {
+		log.Printf("❌ Worker %d: erro ao atualizar status para %s: %v", workerID, d.StatusCompleted, err)
+		return err
+	}
+	tx.Status = d.StatusCompleted
+
+	log.Printf("🔀 Worker %d: %s transferidos do usuário %d para o usuário %d", workerID, tx.Amount, tx.UserID, tx.CounterpartyID)
+	return nil
+}
+
+// chargeFee lança a tarifa como movimento próprio para a conta de tarifas e grava a linha dela no
+// extrato; fromHold indica que a tarifa foi reservada junto com o saque
+func chargeFee(txDB *gorm.DB, tx d.Transaction, fromHold bool) error {
+	if tx.Fee == 0 {
+		return nil
+	}
+
+	post := ledger.ChargeFee
+	if fromHold {
+		post = ledger.CaptureHeldFee
+	}
+	if err := post(txDB, tx); err != nil {
+		return err
+	}
+
+	fee := d.Transaction{
+		ID:                  uuid.New().String(),
+		UserID:              tx.UserID,
+		Amount:              tx.FeeAmount(),
+		Timestamp:           time.Now(),
+		Type:                d.FeeTransaction,
+		Status:              d.StatusCompleted,
+		LinkedTransactionID: tx.ID,
+	}
+	_, err := repositories.EnsureTransaction(txDB, &fee, "fee of "+tx.ID)
+	return err
+}
+
+// lockBalances trava os saldos dos usuários na moeda sempre em ordem crescente de user_id:
+// transferências opostas simultâneas (A→B e B→A) pedem os locks na mesma ordem e não entram em deadlock
+func lockBalances(txDB *gorm.DB, currency string, userIDs ...uint) (map[uint]d.Balance, error) {
+	ordered := append([]uint(nil), userIDs...)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i] < ordered[j] })
+
+	balances := make(map[uint]d.Balance, len(ordered))
+	for _, userID := range ordered {
+		var balance d.Balance
+		if err := txDB.Clauses(clause.Locking{Strength: "UPDATE"}).
+			FirstOrCreate(&balance, d.Balance{UserID: userID, Currency: currency}).Error; err != nil {
+			return nil, err
+		}
+		balances[userID] = balance
+	}
+	return balances, nil
+}
+
+// ensureReceived grava a transação como RECEIVED quando a API não a gravou (mensagens
+// anteriores ao outbox ou publicadas diretamente); caso contrário não altera nada.
+func ensureReceived(txDB *gorm.DB, tx d.Transaction) error {
+	tx.Status = d.StatusReceived
+	_, err := repositories.EnsureTransaction(txDB, &tx, "received from kafka")
+	return err
+}
+
+// handleWithdrawal envia um saque PENDING. Antes do envio ele passa a SENDING por
+// compare-and-set, então o worker e a varredura de saques parados nunca enviam o mesmo saque
+// duas vezes. Recusas definitivas estornam o valor; erros transitórios deixam o saque PENDING
+// para a varredura do ConfirmationTracker tentar de novo.
+func handleWithdrawal(tx d.Transaction, workerID int, db *gorm.DB, chains *d.ChainRegistry, repo d.TransactionRepository) {
 	var user d.User
-	if err := db.First(&user, tx.UserID).Error; err != nil {
+	err := db.First(&user, tx.UserID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("⚠️ Worker %d: usuário %d não encontrado", workerID, tx.UserID)
+		handleFailedTransaction(tx, workerID, db, d.StatusPending, "user not found")
+		return
+	}
+	if err != nil {
 		log.Printf("❌ Worker %d: erro ao buscar usuário: %v", workerID, err)
 		return
 	}
 
-	if user.WalletAddress == "" {
-		log.Printf("⚠️ Worker %d: usuário %d sem endereço TRON", workerID, tx.UserID)
+	// o destino escolhido da lista de endereços vem na transação; sem ele vale a carteira do cadastro
+	toAddress := tx.WalletAddress
+	if toAddress == "" {
+		toAddress = user.WalletAddress
+	}
+	if toAddress == "" {
+		log.Printf("⚠️ Worker %d: usuário %d sem endereço de saque", workerID, tx.UserID)
+		handleFailedTransaction(tx, workerID, db, d.StatusPending, "no withdrawal address")
+		return
+	}
+
+	asset, chain, err := chains.ForCurrency(tx.Amount.Currency)
+	if err != nil {
+		log.Printf("⚠️ Worker %d: moeda %s não suportada para saque on-chain: %v", workerID, tx.Amount.Currency, err)
+		handleFailedTransaction(tx, workerID, db, d.StatusPending, "unsupported currency "+tx.Amount.Currency)
 		return
 	}
 
-	if err := repo.UpdateTransactionStatus(tx.ID, StatusPending); err != nil {
-		log.Printf("⚠️ Worker %d: erro ao atualizar status para PENDING: %v", workerID, err)
+	if err := chain.ValidateAddress(toAddress); err != nil {
+		if !errors.Is(err, d.ErrInvalidAddress) {
+			log.Printf("❌ Worker %d: erro ao validar endereço de saque para %s: %v", workerID, asset.Chain, err)
+			return
+		}
+		log.Printf("⚠️ Worker %d: endereço de saque inválido para %s: %v", workerID, asset.Chain, err)
+		handleFailedTransaction(tx, workerID, db, d.StatusPending, err.Error())
 		return
 	}
 
+	if err := repo.UpdateTransactionStatus(tx.ID, d.StatusPending, d.StatusSending, "sending to "+asset.Chain); err != nil {
+		log.Printf("⚠️ Worker %d: saque %s não está mais PENDING, envio ignorado: %v", workerID, tx.ID, err)
+		return
+	}
+
+	// Money já está na unidade do saldo do ativo (SUN para TRX, 10^-6 USDT); o driver converte para a rede
 	txOut := d.BlockchainTransaction{
-		FromAddress: os.Getenv("TRON_FROM_ADDR"),
-		ToAddress:   user.WalletAddress,
-		Amount:      int64(tx.Amount * 1e6),
-		Visible:     true,
+		ToAddress: toAddress,
+		Amount:    tx.Amount.Units,
+		Timestamp: time.Now(),
 	}
 
-	result, err := b.SendSignedTRX(txOut, tx.ID)
+	result, err := chain.Send(txOut, asset, tx.ID)
+	var broadcastErr *d.BroadcastError
+	if errors.As(err, &broadcastErr) {
+		// assinada e talvez na rede: estornar agora poderia pagar duas vezes, então quem
+		// decide é o ConfirmationTracker, pela transação on-chain
+		log.Printf("⚠️ Worker %d: envio %s sem resposta do nó, acompanhando pelo hash %s: %v", workerID, tx.ID, broadcastErr.Result.TxID, err)
+		recordBroadcast(tx, workerID, db, repo, broadcastErr.Result, "broadcast unconfirmed: "+err.Error())
+		return
+	}
 	if err != nil {
-		log.Printf("❌ Worker %d: erro ao enviar TRX: %v", workerID, err)
-		handleFailedTransaction(tx, workerID, db, repo)
+		// o nó recusou ou a transação nem foi assinada: nada saiu da carteira
+		log.Printf("❌ Worker %d: erro ao enviar %s (%s): %v", workerID, asset.Symbol, asset.Chain, err)
+		handleFailedTransaction(tx, workerID, db, d.StatusSending, err.Error())
 		return
 	}
 
 	log.Printf("✅ Worker %d: transação enviada com sucesso | txID: %s", workerID, result.TxID)
+	recordBroadcast(tx, workerID, db, repo, result, "broadcast "+result.TxID)
+}
 
+// recordBroadcast grava hash e nonce do envio e passa o saque a BROADCAST, onde o
+// ConfirmationTracker o acompanha até a confirmação ou o estorno
+func recordBroadcast(tx d.Transaction, workerID int, db *gorm.DB, repo d.TransactionRepository, result *d.BlockchainTxResult, reason string) {
+	// O hash é gravado antes do BROADCAST: é por ele que o ConfirmationTracker consulta o nó
 	if err := repo.UpdateTransactionHash(tx.ID, result.TxID); err != nil {
 		log.Printf("⚠️ Worker %d: erro ao atualizar hash: %v", workerID, err)
 	}
 
-	if err := repo.UpdateTransactionStatus(tx.ID, StatusCompleted); err != nil {
-		log.Printf("⚠️ Worker %d: erro ao atualizar status para COMPLETED: %v", workerID, err)
+	// em redes EVM o nonce é o que diz ao ConfirmationTracker se um envio sumido foi substituído
+	if result.Nonce != nil {
+		if err := db.Model(&d.Transaction{}).Where("id = ?", tx.ID).
+			Updates(map[string]interface{}{"from_address": result.FromAddress, "nonce": *result.Nonce}).Error; err != nil {
+			log.Printf("⚠️ Worker %d: erro ao gravar nonce: %v", workerID, err)
+		}
 	}
-}
 
-func handleFailedTransaction(tx d.Transaction, workerID int, db *gorm.DB, repo d.TransactionRepository) {
-	if err := repo.UpdateTransactionStatus(tx.ID, StatusFailed); err != nil {
-		log.Printf("⚠️ Worker %d: falha ao marcar transação como FAILED: %v", workerID, err)
+	// A liquidação no razão fica para o ConfirmationTracker, após as confirmações on-chain
+	if err := repo.UpdateTransactionStatus(tx.ID, d.StatusSending, d.StatusBroadcast, reason); err != nil {
+		log.Printf("⚠️ Worker %d: erro ao atualizar status para BROADCAST: %v", workerID, err)
 	}
+}
 
-	err := db.Transaction(func(txDB *gorm.DB) error {
-		var balance d.Balance
-		if err := txDB.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("user_id = ?", tx.UserID).
-			First(&balance).Error; err != nil {
-			return err
-		}
+func handleFailedTransaction(tx d.Transaction, workerID int, db *gorm.DB, from d.TransactionStatus, reason string) {
+	if err := failWithdrawal(db, tx, from, reason); err != nil {
+		log.Printf("⚠️ Worker %d: erro ao processar estorno: %v", workerID, err)
+	} else {
+		log.Printf("✅ Worker %d: estorno concluído para usuário %d", workerID, tx.UserID)
+	}
+}
 
-		newBalance := balance.Amount + tx.Amount
-		if err := txDB.Model(&d.Balance{}).
-			Where("user_id = ?", tx.UserID).
-			Update("amount", newBalance).Error; err != nil {
-			return err
+// failWithdrawal marca o saque como FAILED e devolve o valor e a tarifa ao usuário. A falha, o
+// estorno e a transição FAILED → REFUNDED são gravados na mesma transação do banco, então um
+// saque nunca fica FAILED sem o valor devolvido.
+func failWithdrawal(db *gorm.DB, tx d.Transaction, from d.TransactionStatus, reason string) error {
+	return db.Transaction(func(txDB *gorm.DB) error {
+		if err := repositories.TransitionStatus(txDB, tx.ID, from, d.StatusFailed, reason); err != nil {
+			return fmt.Errorf("falha ao marcar transação como FAILED: %w", err)
 		}
 
 		refundTx := d.Transaction{
 			ID:     uuid.New().String(),
 			UserID: tx.UserID,
-			Amount: tx.Amount,
+			Amount: tx.Total(),
 			Type:   TypeRefund,
-			Status: StatusCompleted,
+			Status: d.StatusCompleted,
 		}
 
-		return txDB.Create(&refundTx).Error
-	})
+		if err := ledger.Refund(txDB, refundTx.ID, tx); err != nil {
+			return err
+		}
 
-	if err != nil {
-		log.Printf("⚠️ Worker %d: erro ao processar estorno: %v", workerID, err)
-	} else {
-		log.Printf("✅ Worker %d: estorno concluído para usuário %d", workerID, tx.UserID)
-	}
+		if _, err := repositories.EnsureTransaction(txDB, &refundTx, "refund of "+tx.ID); err != nil {
+			return err
+		}
+
+		return repositories.TransitionStatus(txDB, tx.ID, d.StatusFailed, d.StatusRefunded, "refund "+refundTx.ID)
+	})
 }
@@ -1,16 +1,18 @@
 package workers_test
 
 import (
-	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/gabrielksneiva/go-financial-transactions/domain"
 	"github.com/gabrielksneiva/go-financial-transactions/mocks"
+	"github.com/gabrielksneiva/go-financial-transactions/repositories"
 	"github.com/gabrielksneiva/go-financial-transactions/workers"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -34,48 +36,115 @@ func setupMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock, func()) {
 	return db, mock, cleanup
 }
 
-func TestWorker_ProcessTransaction_Success(t *testing.T) {
+func expectProcessedMark(mock sqlmock.Sqlmock, txID string, rowsAffected int64) {
+	mock.ExpectExec(`INSERT INTO "processed_messages" .* ON CONFLICT DO NOTHING`).
+		WithArgs(txID, sqlmock.AnyArg(), 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, rowsAffected))
+}
+
+// expectNoHold espera a busca pela reserva de um saque, sem encontrá-la: o worker segue
+// debitando o saldo disponível
+func expectNoHold(mock sqlmock.Sqlmock, txID string) {
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "journal_entries"`).
+		WithArgs(txID, "withdraw_held").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+}
+
+// expectEnsureReceived espera o insert da transação como RECEIVED; rowsAffected 0 simula
+// a linha já gravada pela API, e nesse caso nenhum histórico é inserido
+func expectEnsureReceived(mock sqlmock.Sqlmock, tx domain.Transaction, rowsAffected int64) {
+	mock.ExpectExec(`INSERT INTO "transactions" .* ON CONFLICT DO NOTHING`).
+		WithArgs(tx.ID, tx.UserID, tx.Amount.Units, tx.Amount.Currency, sqlmock.AnyArg(), tx.Type, sqlmock.AnyArg(), sqlmock.AnyArg(), tx.Fee, sqlmock.AnyArg(), sqlmock.AnyArg(), tx.CounterpartyID, tx.LinkedTransactionID, tx.Memo, domain.StatusReceived, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, rowsAffected))
+	if rowsAffected > 0 {
+		expectStatusHistory(mock, tx.ID)
+	}
+}
+
+// tronChains registra o driver como a rede TRON, a única usada pelos saques em TRX dos testes
+func tronChains(c domain.BlockchainClient) *domain.ChainRegistry {
+	chains := domain.NewChainRegistry()
+	chains.Register(domain.ChainTron, c)
+	return chains
+}
+
+// expectTransition espera o compare-and-set de status seguido do registro no histórico
+func expectTransition(mock sqlmock.Sqlmock, tx domain.Transaction, from, to domain.TransactionStatus) {
+	mock.ExpectQuery(`SELECT "id","type","status" FROM "transactions"`).
+		WithArgs(tx.ID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "status"}).AddRow(tx.ID, tx.Type, from))
+	mock.ExpectExec(`UPDATE "transactions" SET "status"=\$1,"updated_at"=\$2 WHERE id = \$3 AND status = \$4`).
+		WithArgs(to, sqlmock.AnyArg(), tx.ID, from).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectStatusHistory(mock, tx.ID)
+}
+
+func expectStatusHistory(mock sqlmock.Sqlmock, txID string) {
+	mock.ExpectQuery(`INSERT INTO "transaction_status_history"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+}
+
+// expectLedgerPosting espera um lançamento de duas pernas: as contas são resolvidas primeiro e
+// atualizadas em ordem de ID, a do usuário (1) antes da de sistema (2)
+func expectLedgerPosting(mock sqlmock.Sqlmock, userID uint) {
+	mock.ExpectExec(`INSERT INTO "journal_entries"`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	for accountID := 1; accountID <= 2; accountID++ {
+		mock.ExpectQuery(`SELECT .* FROM "ledger_accounts"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "code", "type"}).
+				AddRow(accountID, "code", "type"))
+	}
+	for i, isUser := range []bool{true, false} {
+		accountID := i + 1
+		mock.ExpectQuery(`INSERT INTO "postings"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(accountID))
+		mock.ExpectExec(`UPDATE "ledger_accounts"`).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		if isUser {
+			mock.ExpectExec(`INSERT INTO "balances"`).
+				WithArgs(userID, "TRX", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+	}
+}
+
+func TestProcessTransaction_Success(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
 	tx := domain.Transaction{
 		ID:        "tx-1",
 		UserID:    1,
-		Amount:    100.0,
+		Amount:    domain.NewMoney(100_000000, "TRX"),
 		Type:      "deposit",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	mock.ExpectBegin()
+	expectProcessedMark(mock, tx.ID, 1)
 	mock.ExpectQuery(`SELECT .* FROM "balances"`).
-		WithArgs(tx.UserID).
-		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount"}).
-			AddRow(tx.UserID, 0.0))
+		WithArgs(tx.UserID, "TRX", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount", "currency"}).
+			AddRow(tx.UserID, 0, "TRX"))
 
-	mock.ExpectExec(`UPDATE "balances"`).
-		WithArgs(100.0, tx.UserID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLedgerPosting(mock, tx.UserID)
 
-	mock.ExpectExec(`INSERT INTO "transactions"`).
-		WithArgs(tx.ID, tx.UserID, tx.Amount, sqlmock.AnyArg(), tx.Type, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectEnsureReceived(mock, tx, 1)
+	expectTransition(mock, tx, domain.StatusReceived, domain.StatusCompleted)
 
 	mock.ExpectCommit()
 
-	ch := make(chan domain.Transaction, 1)
-	ch <- tx
-
 	blockchainMock := new(mocks.BlockchainClient)
 	repoMock := new(mocks.TransactionRepository)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	go workers.Worker(ctx, 1, ch, db, blockchainMock, repoMock)
-	time.Sleep(200 * time.Millisecond)
-	cancel()
+	err := workers.CallProcessTransaction(tx, 1, db, tronChains(blockchainMock), repoMock)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestWorker_InsufficientFunds(t *testing.T) {
+func TestProcessTransaction_InsufficientFunds(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
@@ -85,29 +154,35 @@ func TestWorker_InsufficientFunds(t *testing.T) {
 	tx := domain.Transaction{
 		ID:        "tx-2",
 		UserID:    2,
-		Amount:    -100.0,
+		Amount:    domain.NewMoney(100_000000, "TRX"),
 		Type:      "withdraw",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	mock.ExpectBegin()
+	expectProcessedMark(mock, tx.ID, 1)
+	expectNoHold(mock, tx.ID)
 	mock.ExpectQuery(`SELECT .* FROM "balances"`).
-		WithArgs(tx.UserID).
-		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount"}).
-			AddRow(tx.UserID, 50.0))
+		WithArgs(tx.UserID, "TRX", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount", "currency"}).
+			AddRow(tx.UserID, 50_000000, "TRX"))
 	mock.ExpectRollback()
 
-	ch := make(chan domain.Transaction, 1)
-	ch <- tx
+	// a rejeição é registrada fora da transação revertida e a transação vai de RECEIVED a FAILED
+	mock.ExpectBegin()
+	expectProcessedMark(mock, tx.ID, 1)
+	expectEnsureReceived(mock, tx, 0)
+	expectTransition(mock, tx, domain.StatusReceived, domain.StatusFailed)
+	mock.ExpectCommit()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	go workers.Worker(ctx, 1, ch, db, blockchainMock, repoMock)
-	time.Sleep(200 * time.Millisecond)
-	cancel()
+	err := workers.CallProcessTransaction(tx, 1, db, tronChains(blockchainMock), repoMock)
+	// saldo insuficiente é uma rejeição definitiva: o offset pode ser confirmado
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestWorker_ErrorOnInsert(t *testing.T) {
+func TestProcessTransaction_ErrorOnInsert(t *testing.T) {
 	db, mock, cleanup := setupMockDB(t)
 	defer cleanup()
 
@@ -117,33 +192,164 @@ func TestWorker_ErrorOnInsert(t *testing.T) {
 	tx := domain.Transaction{
 		ID:        "tx-3",
 		UserID:    3,
-		Amount:    50.0,
+		Amount:    domain.NewMoney(50_000000, "TRX"),
 		Type:      "deposit",
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	mock.ExpectBegin()
+	expectProcessedMark(mock, tx.ID, 1)
 	mock.ExpectQuery(`SELECT .* FROM "balances"`).
-		WithArgs(tx.UserID, tx.UserID, 1).
-		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount"}).
-			AddRow(tx.UserID, 100.0))
+		WithArgs(tx.UserID, "TRX", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "amount", "currency"}).
+			AddRow(tx.UserID, 100_000000, "TRX"))
 
-	mock.ExpectExec(`UPDATE "balances"`).
-		WithArgs(150.0, tx.UserID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	expectLedgerPosting(mock, tx.UserID)
 
-	mock.ExpectExec(`INSERT INTO "transactions"`).
-		WithArgs(tx.ID, tx.UserID, tx.Amount, sqlmock.AnyArg(), tx.Type, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+	mock.ExpectExec(`INSERT INTO "transactions" .* ON CONFLICT DO NOTHING`).
 		WillReturnError(assert.AnError)
 
 	mock.ExpectRollback()
 
-	ch := make(chan domain.Transaction, 1)
-	ch <- tx
+	err := workers.CallProcessTransaction(tx, 1, db, tronChains(blockchainMock), repoMock)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProcessTransaction_AlreadyProcessedIsNoop(t *testing.T) {
+	db, mock, cleanup := setupMockDB(t)
+	defer cleanup()
+
+	tx := domain.Transaction{
+		ID:     "tx-4",
+		UserID: 4,
+		Amount: domain.NewMoney(10_000000, "TRX"),
+		Type:   "withdraw",
+	}
+
+	// a marcação não insere nada: nenhum saldo é lido e nada é enviado à blockchain
+	mock.ExpectBegin()
+	expectProcessedMark(mock, tx.ID, 0)
+	mock.ExpectRollback()
+
+	blockchainMock := new(mocks.BlockchainClient)
+	repoMock := new(mocks.TransactionRepository)
+
+	err := workers.CallProcessTransaction(tx, 1, db, tronChains(blockchainMock), repoMock)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Empty(t, blockchainMock.Calls)
+}
+
+func TestHandleWithdrawal_InvalidAddressIsRefunded(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "invalid@example.com", WalletAddress: "0xnot-tron"}).Error)
+
+	blockchainMock := new(mocks.BlockchainClient)
+	blockchainMock.On("ValidateAddress", "0xnot-tron").Return(domain.ErrInvalidAddress)
+	chains := tronChains(blockchainMock)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-bad-address", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+
+	// o envio nunca é tentado e o valor volta ao usuário
+	blockchainMock.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(10_000000, "TRX"), balanceOf(t, db, 1))
+}
+
+func TestHandleWithdrawal_MissingAddressIsRefunded(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "noaddress@example.com"}).Error)
+
+	blockchainMock := new(mocks.BlockchainClient)
+	chains := tronChains(blockchainMock)
 
-	ctx, cancel := context.WithCancel(context.Background())
-	go workers.Worker(ctx, 1, ch, db, blockchainMock, repoMock)
-	time.Sleep(200 * time.Millisecond)
-	cancel()
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-no-address", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+
+	// sem destino o saque não pode ficar PENDING com o saldo debitado
+	assert.Empty(t, blockchainMock.Calls)
+	assert.Equal(t, domain.StatusRefunded, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(10_000000, "TRX"), balanceOf(t, db, 1))
+}
+
+func TestHandleWithdrawal_RefundFailureKeepsWithdrawalPending(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "norefund@example.com"}).Error)
+
+	// a gravação da transação de estorno falha depois que o saque já foi marcado FAILED
+	err := db.Callback().Create().Before("gorm:create").Register("test:fail_refund", func(txDB *gorm.DB) {
+		if tx, ok := txDB.Statement.Dest.(*domain.Transaction); ok && tx.Type == workers.TypeRefund {
+			txDB.AddError(errors.New("db down"))
+		}
+	})
+	assert.NoError(t, err)
+
+	chains := tronChains(new(mocks.BlockchainClient))
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-no-refund", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+
+	// nada do estorno fica gravado pela metade: o saque continua PENDING para uma nova tentativa
+	assert.Equal(t, domain.StatusPending, statusOf(t, db, withdraw.ID))
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), balanceOf(t, db, 1))
+	assert.Equal(t, int64(0), countRows(t, db, &domain.TransactionStatusHistory{}, "transaction_id = ? AND \"to\" = ?", withdraw.ID, domain.StatusFailed))
+	assertLedgerOK(t, db)
+}
+
+func TestHandleWithdrawal_SendWithoutAnswerIsTrackedNotRefunded(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "timeout@example.com", WalletAddress: "TWallet"}).Error)
+
+	blockchainMock := new(mocks.BlockchainClient)
+	blockchainMock.On("ValidateAddress", "TWallet").Return(nil)
+	blockchainMock.On("Send", mock.Anything, mock.Anything, "tx-timeout").Return(nil, &domain.BroadcastError{
+		Result: &domain.BlockchainTxResult{TxID: "hash-timeout"},
+		Err:    errors.New("context deadline exceeded"),
+	})
+	chains := tronChains(blockchainMock)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-timeout", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+
+	// a transação pode estar na rede: fica com o ConfirmationTracker, sem estorno
+	var sent domain.Transaction
+	assert.NoError(t, db.First(&sent, "id = ?", withdraw.ID).Error)
+	assert.Equal(t, domain.StatusBroadcast, sent.Status)
+	assert.Equal(t, "hash-timeout", sent.TxHash)
+	assert.Equal(t, domain.NewMoney(6_000000, "TRX"), balanceOf(t, db, 1))
+}
+
+func TestHandleWithdrawal_SendsToChosenDestination(t *testing.T) {
+	db := setupSQLiteDB(t)
+	repo := repositories.NewGormRepository(db)
+	assert.NoError(t, db.Create(&domain.User{ID: 1, Email: "dest@example.com", WalletAddress: "TWallet"}).Error)
+
+	blockchainMock := new(mocks.BlockchainClient)
+	blockchainMock.On("ValidateAddress", "TDestination").Return(nil)
+	blockchainMock.On("Send", mock.MatchedBy(func(tx domain.BlockchainTransaction) bool {
+		return tx.ToAddress == "TDestination"
+	}), mock.Anything, "tx-dest").Return(&domain.BlockchainTxResult{TxID: "chain-dest"}, nil)
+	chains := tronChains(blockchainMock)
+
+	deposit := domain.Transaction{ID: "tx-funding", UserID: 1, Amount: domain.NewMoney(10_000000, "TRX"), Type: workers.TypeDeposit}
+	assert.NoError(t, workers.CallProcessTransaction(deposit, 1, db, chains, repo))
+	withdraw := domain.Transaction{ID: "tx-dest", UserID: 1, Amount: domain.NewMoney(4_000000, "TRX"), Type: workers.TypeWithdraw, WalletAddress: "TDestination"}
+	assert.NoError(t, workers.CallProcessTransaction(withdraw, 1, db, chains, repo))
+
+	blockchainMock.AssertExpectations(t)
+	assert.Equal(t, domain.StatusBroadcast, statusOf(t, db, withdraw.ID))
 }